@@ -81,6 +81,18 @@ func initFlags() {
 		flags.TxPoolNoLocalsFlag,
 		flags.TxPoolJournalFlag,
 		flags.TxPoolRejournalFlag,
+		flags.TxPoolSnapshotFlag,
+		flags.TxPoolSnapshotIntervalFlag,
+		flags.TxPoolSnapshotMaxTxsFlag,
+		flags.TxPoolSnapshotMaxAgeFlag,
+		flags.TxPoolPolicyFlag,
+		flags.TxPoolPolicySenderRateFlag,
+		flags.TxPoolPolicySenderBurstFlag,
+		flags.TxPoolHistoryFlag,
+		flags.TxPoolPrivateFlag,
+		flags.TxPoolPrivateAccountSlotsFlag,
+		flags.TxPoolPrivateLifetimeFlag,
+		flags.GPOStrategyFlag,
 		flags.TxPoolPriceLimitFlag,
 		flags.TxPoolPriceBumpFlag,
 		flags.TxPoolAccountSlotsFlag,
@@ -96,6 +108,9 @@ func initFlags() {
 		flags.KeyStoreDirFlag,
 		flags.USBFlag,
 		flags.SmartCardDaemonPathFlag,
+		flags.SyncModeFlag,
+		flags.SyncSnapshotFlag,
+		flags.SnapshotPeriodFlag,
 		flags.ExitWhenAgeFlag,
 		flags.ExitWhenEpochFlag,
 		flags.LightKDFFlag,
@@ -103,6 +118,11 @@ func initFlags() {
 		flags.ValidatorIDFlag,
 		flags.ValidatorPubkeyFlag,
 		flags.ValidatorPasswordFlag,
+		flags.ValidatorSignerFlag,
+		flags.ValidatorSignerCertFlag,
+		flags.ValidatorSignerKeyFlag,
+		flags.ValidatorSignerCAFlag,
+		flags.ValidatorSignerTimeoutFlag,
 		flags.ModeFlag,
 	}
 
@@ -120,12 +140,18 @@ func initFlags() {
 		flags.WSApiFlag,
 		flags.WSAllowedOriginsFlag,
 		flags.WSPathPrefixFlag,
+		flags.GraphQLEnabledFlag,
+		flags.GraphQLCORSDomainFlag,
+		flags.GraphQLVirtualHostsFlag,
 		flags.IPCDisabledFlag,
 		flags.IPCPathFlag,
 		flags.RPCGlobalGasCapFlag,
 		flags.RPCGlobalEVMTimeoutFlag,
 		flags.RPCGlobalTxFeeCapFlag,
 		flags.RPCGlobalTimeoutFlag,
+		flags.TraceIndexFlag,
+		flags.ArchiveQueryWindowFlag,
+		flags.HistoryRetentionFlag,
 	}
 
 	metricsFlags = []cli.Flag{
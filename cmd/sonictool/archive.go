@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"github.com/Fantom-foundation/go-opera/cmd/sonictool/chain"
+	"github.com/Fantom-foundation/go-opera/config/flags"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func pruneArchive(ctx *cli.Context) error {
+	dataDir := ctx.GlobalString(flags.DataDirFlag.Name)
+	if dataDir == "" {
+		return fmt.Errorf("--%s need to be set", flags.DataDirFlag.Name)
+	}
+	cacheRatio, err := cacheScaler(ctx)
+	if err != nil {
+		return err
+	}
+	if len(ctx.Args()) < 1 {
+		return fmt.Errorf("number of retained blocks is required")
+	}
+	retention, err := strconv.ParseUint(ctx.Args().Get(0), 10, 64)
+	if err != nil {
+		return err
+	}
+
+	cancelCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	return chain.PruneArchive(cancelCtx, dataDir, cacheRatio, retention)
+}
@@ -1,17 +1,34 @@
 package main
 
 import (
+	"bufio"
 	"compress/gzip"
+	"context"
 	"fmt"
 	"github.com/Fantom-foundation/go-opera/cmd/sonictool/chain"
 	"github.com/Fantom-foundation/go-opera/config/flags"
 	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/utils/cachescale"
 	"github.com/ethereum/go-ethereum/log"
 	"gopkg.in/urfave/cli.v1"
 	"io"
 	"os"
+	"os/signal"
 	"strconv"
 	"strings"
+	"syscall"
+)
+
+var (
+	ExportFormatFlag = cli.StringFlag{
+		Name:  "format",
+		Usage: `Format of the exported files ("parquet" or "csv")`,
+		Value: chain.ExportFormatParquet,
+	}
+	ExportEpochsFlag = cli.BoolFlag{
+		Name:  "epochs",
+		Usage: "Interpret the range as epochs instead of blocks",
+	}
 )
 
 func exportEvents(ctx *cli.Context) error {
@@ -77,3 +94,138 @@ func importEvents(ctx *cli.Context) error {
 
 	return nil
 }
+
+func exportData(ctx *cli.Context) error {
+	if len(ctx.Args()) < 1 {
+		return fmt.Errorf("this command requires an argument - the output directory")
+	}
+	dataDir := ctx.GlobalString(flags.DataDirFlag.Name)
+	if dataDir == "" {
+		return fmt.Errorf("--%s need to be set", flags.DataDirFlag.Name)
+	}
+	cacheRatio, err := cacheScaler(ctx)
+	if err != nil {
+		return err
+	}
+
+	cfg := chain.ExportDataConfig{
+		OutDir: ctx.Args().First(),
+		Format: ctx.String(ExportFormatFlag.Name),
+		From:   1,
+		Epochs: ctx.Bool(ExportEpochsFlag.Name),
+	}
+	if len(ctx.Args()) > 1 {
+		cfg.From, err = strconv.ParseUint(ctx.Args().Get(1), 10, 64)
+		if err != nil {
+			return err
+		}
+	}
+	if len(ctx.Args()) > 2 {
+		cfg.To, err = strconv.ParseUint(ctx.Args().Get(2), 10, 64)
+		if err != nil {
+			return err
+		}
+	}
+
+	cancelCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	return chain.ExportData(cancelCtx, dataDir, cacheRatio, cfg)
+}
+
+func exportBlocks(ctx *cli.Context) error {
+	if len(ctx.Args()) < 1 {
+		return fmt.Errorf("this command requires an argument - the output file")
+	}
+	dataDir := ctx.GlobalString(flags.DataDirFlag.Name)
+	if dataDir == "" {
+		return fmt.Errorf("--%s need to be set", flags.DataDirFlag.Name)
+	}
+	cacheRatio, err := cacheScaler(ctx)
+	if err != nil {
+		return err
+	}
+
+	from, to := idx.Block(1), idx.Block(0)
+	if len(ctx.Args()) > 1 {
+		n, err := strconv.ParseUint(ctx.Args().Get(1), 10, 64)
+		if err != nil {
+			return err
+		}
+		from = idx.Block(n)
+	}
+	if len(ctx.Args()) > 2 {
+		n, err := strconv.ParseUint(ctx.Args().Get(2), 10, 64)
+		if err != nil {
+			return err
+		}
+		to = idx.Block(n)
+	}
+
+	// Open the file handle and potentially wrap with a gzip stream
+	fn := ctx.Args().First()
+	fh, err := os.OpenFile(fn, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	var writer io.Writer = fh
+	if strings.HasSuffix(fn, ".gz") {
+		writer = gzip.NewWriter(writer)
+		defer writer.(*gzip.Writer).Close()
+	}
+
+	cancelCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	log.Info("Exporting blocks to file", "file", fn)
+	if err := chain.ExportBlocks(cancelCtx, writer, dataDir, cacheRatio, from, to); err != nil {
+		return fmt.Errorf("export error: %w", err)
+	}
+	return nil
+}
+
+func importBlocks(ctx *cli.Context) error {
+	if len(ctx.Args()) < 1 {
+		return fmt.Errorf("this command requires an argument - the input file")
+	}
+	dataDir := ctx.GlobalString(flags.DataDirFlag.Name)
+	if dataDir == "" {
+		return fmt.Errorf("--%s need to be set", flags.DataDirFlag.Name)
+	}
+	cacheRatio, err := cacheScaler(ctx)
+	if err != nil {
+		return err
+	}
+
+	cancelCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	for _, fn := range ctx.Args() {
+		log.Info("Importing blocks from file", "file", fn)
+		if err := importBlocksFile(cancelCtx, fn, dataDir, cacheRatio); err != nil {
+			log.Error("Import error", "file", fn, "err", err)
+			return err
+		}
+	}
+	return nil
+}
+
+func importBlocksFile(ctx context.Context, fn string, dataDir string, cacheRatio cachescale.Func) error {
+	// Open the file handle and potentially unwrap the gzip stream
+	fh, err := os.Open(fn)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	var reader io.Reader = bufio.NewReader(fh)
+	if strings.HasSuffix(fn, ".gz") {
+		if reader, err = gzip.NewReader(reader); err != nil {
+			return err
+		}
+		defer reader.(*gzip.Reader).Close()
+	}
+	return chain.ImportBlocks(ctx, reader, dataDir, cacheRatio)
+}
@@ -0,0 +1,136 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/utils/cachescale"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/status-im/keycard-go/hexutils"
+
+	"github.com/Fantom-foundation/go-opera/cmd/sonictool/db"
+	"github.com/Fantom-foundation/go-opera/gossip"
+	"github.com/Fantom-foundation/go-opera/inter"
+	"github.com/Fantom-foundation/go-opera/opera"
+)
+
+var (
+	blocksFileHeader  = hexutils.HexToBytes("7e99b10c")
+	blocksFileVersion = hexutils.HexToBytes("00010001")
+)
+
+// exportedEpoch is the network rules the blocks of the epoch are executed with
+type exportedEpoch struct {
+	Epoch          idx.Epoch
+	Rules          opera.Rules
+	UpgradeHeights []opera.UpgradeHeight
+}
+
+// exportedBlock is a record of the blocks file.
+// Epoch is set for the first exported block of every epoch only.
+type exportedBlock struct {
+	Number idx.Block
+	Block  *inter.Block
+	Txs    types.Transactions // all the block txs in the execution order, including the skipped ones
+	Epoch  *exportedEpoch     `rlp:"nil"`
+}
+
+// blockExecutedTxs returns all the txs executed by the block, including the skipped ones
+func blockExecutedTxs(gdb *gossip.Store, block *inter.Block) (types.Transactions, error) {
+	txs := make(types.Transactions, 0, len(block.InternalTxs)+len(block.Txs)+len(block.Events)*10)
+	for _, txid := range append(append([]common.Hash{}, block.InternalTxs...), block.Txs...) {
+		tx := gdb.EvmStore().GetTx(txid)
+		if tx == nil {
+			return nil, fmt.Errorf("tx %s not found", txid.String())
+		}
+		txs = append(txs, tx)
+	}
+	for _, id := range block.Events {
+		e := gdb.GetEventPayload(id)
+		if e == nil {
+			return nil, fmt.Errorf("event %s not found", id.String())
+		}
+		txs = append(txs, e.Txs()...)
+	}
+	return txs, nil
+}
+
+// ExportBlocks writes the blocks together with their transactions as RLP records,
+// so they can be re-executed without the DAG events by ImportBlocks.
+// If to is zero, blocks up to the latest one are exported.
+func ExportBlocks(ctx context.Context, w io.Writer, dataDir string, cacheRatio cachescale.Func, from, to idx.Block) error {
+	chaindataDir := filepath.Join(dataDir, "chaindata")
+	dbs, err := db.MakeDbProducer(chaindataDir, cacheRatio)
+	if err != nil {
+		return err
+	}
+	defer dbs.Close()
+
+	gdb, err := db.MakeGossipDb(dbs, dataDir, false, cacheRatio)
+	if err != nil {
+		return err
+	}
+	defer gdb.Close()
+
+	if latest := gdb.GetLatestBlockIndex(); to == 0 || to > latest {
+		to = latest
+	}
+	if from == 0 {
+		from = 1 // genesis block is not executed
+	}
+
+	// Write header and version
+	if _, err := w.Write(append(blocksFileHeader, blocksFileVersion...)); err != nil {
+		return err
+	}
+
+	start, reported := time.Now(), time.Now()
+	epoch := idx.Epoch(0)
+	for n := from; n <= to; n++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		block := gdb.GetBlock(n)
+		if block == nil {
+			return fmt.Errorf("block %d not found", n)
+		}
+		txs, err := blockExecutedTxs(gdb, block)
+		if err != nil {
+			return fmt.Errorf("block %d: %w", n, err)
+		}
+		record := exportedBlock{
+			Number: n,
+			Block:  block,
+			Txs:    txs,
+		}
+		if e := block.Atropos.Epoch(); e != epoch {
+			es := gdb.GetHistoryEpochState(e)
+			if es == nil {
+				return fmt.Errorf("epoch %d of block %d not found", e, n)
+			}
+			record.Epoch = &exportedEpoch{
+				Epoch:          e,
+				Rules:          es.Rules,
+				UpgradeHeights: gdb.GetUpgradeHeights(),
+			}
+			epoch = e
+		}
+		if err := rlp.Encode(w, &record); err != nil {
+			return err
+		}
+
+		if time.Since(reported) >= statsReportLimit {
+			log.Info("Exporting blocks", "last", n, "to", to, "elapsed", common.PrettyDuration(time.Since(start)))
+			reported = time.Now()
+		}
+	}
+	log.Info("Exported blocks", "first", from, "last", to, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
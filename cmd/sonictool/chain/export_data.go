@@ -0,0 +1,565 @@
+package chain
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/utils/cachescale"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/Fantom-foundation/go-opera/cmd/sonictool/db"
+	"github.com/Fantom-foundation/go-opera/evmcore"
+	"github.com/Fantom-foundation/go-opera/gossip"
+	"github.com/Fantom-foundation/go-opera/inter"
+	"github.com/Fantom-foundation/go-opera/opera"
+	"github.com/Fantom-foundation/go-opera/utils/parquet"
+	"github.com/Fantom-foundation/go-opera/utils/signers/gsignercache"
+	"github.com/Fantom-foundation/go-opera/utils/signers/internaltx"
+)
+
+// Formats of the exported data files
+const (
+	ExportFormatParquet = "parquet"
+	ExportFormatCSV     = "csv"
+)
+
+const exportCheckpointFile = "checkpoint.json"
+
+// ExportDataConfig is the configuration of the chain data export
+type ExportDataConfig struct {
+	OutDir string // directory of the exported files and the checkpoint
+	Format string // ExportFormatParquet or ExportFormatCSV
+	From   uint64 // first block (or epoch) of the range
+	To     uint64 // last block (or epoch) of the range, zero means the latest one
+	Epochs bool   // if true, the range is of epochs instead of blocks
+}
+
+// exportCheckpoint is the progress of the export, the export is resumed after the last exported block
+type exportCheckpoint struct {
+	Format    string    `json:"format"`
+	LastBlock idx.Block `json:"lastBlock"`
+}
+
+// exportTable is a table of the exported data
+type exportTable struct {
+	name    string
+	columns []parquet.Column
+}
+
+var (
+	blocksTable = exportTable{"blocks", []parquet.Column{
+		{Name: "epoch", Type: parquet.Int64},
+		{Name: "number", Type: parquet.Int64},
+		{Name: "hash", Type: parquet.String},
+		{Name: "parent_hash", Type: parquet.String},
+		{Name: "timestamp", Type: parquet.Int64},
+		{Name: "timestamp_nano", Type: parquet.Int64},
+		{Name: "state_root", Type: parquet.String},
+		{Name: "gas_used", Type: parquet.Int64},
+		{Name: "base_fee_per_gas", Type: parquet.String},
+		{Name: "transaction_count", Type: parquet.Int64},
+		{Name: "event_count", Type: parquet.Int64},
+	}}
+	transactionsTable = exportTable{"transactions", []parquet.Column{
+		{Name: "epoch", Type: parquet.Int64},
+		{Name: "block_number", Type: parquet.Int64},
+		{Name: "transaction_index", Type: parquet.Int64},
+		{Name: "hash", Type: parquet.String},
+		{Name: "type", Type: parquet.Int64},
+		{Name: "from", Type: parquet.String},
+		{Name: "to", Type: parquet.String},
+		{Name: "nonce", Type: parquet.Int64},
+		{Name: "value", Type: parquet.String},
+		{Name: "gas", Type: parquet.Int64},
+		{Name: "gas_price", Type: parquet.String},
+		{Name: "max_fee_per_gas", Type: parquet.String},
+		{Name: "max_priority_fee_per_gas", Type: parquet.String},
+		{Name: "input", Type: parquet.String},
+	}}
+	receiptsTable = exportTable{"receipts", []parquet.Column{
+		{Name: "epoch", Type: parquet.Int64},
+		{Name: "block_number", Type: parquet.Int64},
+		{Name: "transaction_index", Type: parquet.Int64},
+		{Name: "transaction_hash", Type: parquet.String},
+		{Name: "status", Type: parquet.Int64},
+		{Name: "gas_used", Type: parquet.Int64},
+		{Name: "cumulative_gas_used", Type: parquet.Int64},
+		{Name: "effective_gas_price", Type: parquet.String},
+		{Name: "contract_address", Type: parquet.String},
+		{Name: "log_count", Type: parquet.Int64},
+	}}
+	logsTable = exportTable{"logs", []parquet.Column{
+		{Name: "epoch", Type: parquet.Int64},
+		{Name: "block_number", Type: parquet.Int64},
+		{Name: "transaction_index", Type: parquet.Int64},
+		{Name: "log_index", Type: parquet.Int64},
+		{Name: "transaction_hash", Type: parquet.String},
+		{Name: "address", Type: parquet.String},
+		{Name: "topic0", Type: parquet.String},
+		{Name: "topic1", Type: parquet.String},
+		{Name: "topic2", Type: parquet.String},
+		{Name: "topic3", Type: parquet.String},
+		{Name: "data", Type: parquet.String},
+	}}
+
+	exportTables = []exportTable{blocksTable, transactionsTable, receiptsTable, logsTable}
+)
+
+// tableWriter writes rows of a table into a file
+type tableWriter interface {
+	Write(row []interface{}) error
+	Close() error
+}
+
+// csvTableWriter writes rows as CSV records, the first record is the header
+type csvTableWriter struct {
+	w *csv.Writer
+}
+
+func newCsvTableWriter(w *bufio.Writer, columns []parquet.Column) (*csvTableWriter, error) {
+	cw := csv.NewWriter(w)
+	header := make([]string, len(columns))
+	for i, c := range columns {
+		header[i] = c.Name
+	}
+	if err := cw.Write(header); err != nil {
+		return nil, err
+	}
+	return &csvTableWriter{cw}, nil
+}
+
+func (w *csvTableWriter) Write(row []interface{}) error {
+	record := make([]string, len(row))
+	for i, v := range row {
+		switch v := v.(type) {
+		case int64:
+			record[i] = strconv.FormatInt(v, 10)
+		case string:
+			record[i] = v
+		default:
+			return fmt.Errorf("unexpected value type %T", v)
+		}
+	}
+	return w.w.Write(record)
+}
+
+func (w *csvTableWriter) Close() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+// partitionFile is a file of the table partition, it's written into a temporary
+// file which is renamed when the partition is complete
+type partitionFile struct {
+	table  exportTable
+	dir    string
+	tmp    string
+	file   *os.File
+	buf    *bufio.Writer
+	writer tableWriter
+}
+
+// exportPartition is the set of files of the tables for a range of blocks of one epoch
+type exportPartition struct {
+	epoch  idx.Epoch
+	first  idx.Block
+	last   idx.Block
+	format string
+	files  map[string]*partitionFile
+}
+
+func openExportPartition(outDir, format string, epoch idx.Epoch, first idx.Block) (*exportPartition, error) {
+	p := &exportPartition{
+		epoch:  epoch,
+		first:  first,
+		last:   first,
+		format: format,
+		files:  make(map[string]*partitionFile, len(exportTables)),
+	}
+	for _, table := range exportTables {
+		f := &partitionFile{
+			table: table,
+			dir:   filepath.Join(outDir, table.name, fmt.Sprintf("epoch=%d", epoch)),
+		}
+		f.tmp = filepath.Join(f.dir, fmt.Sprintf("%s-%d.%s.tmp", table.name, first, format))
+		err := os.MkdirAll(f.dir, 0700)
+		if err == nil {
+			f.file, err = os.OpenFile(f.tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+		}
+		if err == nil {
+			f.buf = bufio.NewWriter(f.file)
+			if format == ExportFormatCSV {
+				f.writer, err = newCsvTableWriter(f.buf, table.columns)
+			} else {
+				f.writer, err = parquet.NewWriter(f.buf, table.columns, parquet.DefaultRowGroupSize)
+			}
+		}
+		if f.file != nil {
+			p.files[table.name] = f
+		}
+		if err != nil {
+			p.abort()
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+func (p *exportPartition) write(table exportTable, row ...interface{}) error {
+	return p.files[table.name].writer.Write(row)
+}
+
+// close finishes the files and moves them to their final names
+func (p *exportPartition) close() error {
+	for _, f := range p.files {
+		err := f.writer.Close()
+		if err == nil {
+			err = f.buf.Flush()
+		}
+		if err == nil {
+			err = f.file.Sync()
+		}
+		if closeErr := f.file.Close(); err == nil {
+			err = closeErr
+		}
+		if err == nil {
+			name := fmt.Sprintf("%s-%d-%d.%s", f.table.name, p.first, p.last, p.format)
+			err = os.Rename(f.tmp, filepath.Join(f.dir, name))
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write %s of epoch %d: %w", f.table.name, p.epoch, err)
+		}
+	}
+	return nil
+}
+
+// abort removes the unfinished files
+func (p *exportPartition) abort() {
+	for _, f := range p.files {
+		_ = f.file.Close()
+		_ = os.Remove(f.tmp)
+	}
+}
+
+func readExportCheckpoint(outDir string) (*exportCheckpoint, error) {
+	data, err := os.ReadFile(filepath.Join(outDir, exportCheckpointFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	cp := &exportCheckpoint{}
+	if err := json.Unmarshal(data, cp); err != nil {
+		return nil, fmt.Errorf("failed to parse the export checkpoint: %w", err)
+	}
+	return cp, nil
+}
+
+func writeExportCheckpoint(outDir string, cp exportCheckpoint) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(outDir, exportCheckpointFile)
+	if err := os.WriteFile(path+".tmp", data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// epochBlocksRange returns the first and the last block of the epochs range.
+// If to is zero or not sealed yet, the range ends by the latest block.
+func epochBlocksRange(gdb *gossip.Store, from, to idx.Epoch) (idx.Block, idx.Block, error) {
+	latest := gdb.GetLatestBlockIndex()
+	bs, _ := gdb.GetHistoryBlockEpochState(from)
+	if bs == nil {
+		return 0, 0, fmt.Errorf("epoch %d not found", from)
+	}
+	first := bs.LastBlock.Idx + 1
+	last := latest
+	if to != 0 {
+		if bs, _ := gdb.GetHistoryBlockEpochState(to + 1); bs != nil {
+			last = bs.LastBlock.Idx
+		}
+	}
+	return first, last, nil
+}
+
+// bigToString formats the number as decimal, nil is formatted as an empty string
+func bigToString(v *big.Int) string {
+	if v == nil {
+		return ""
+	}
+	return v.String()
+}
+
+func addressToString(a *common.Address) string {
+	if a == nil {
+		return ""
+	}
+	return a.Hex()
+}
+
+// effectiveGasPrice returns the gas price paid by the transaction in the block of the given base fee
+func effectiveGasPrice(tx *types.Transaction, baseFee *big.Int) *big.Int {
+	if tx.Type() != types.DynamicFeeTxType || baseFee == nil {
+		return tx.GasPrice()
+	}
+	return math.BigMin(new(big.Int).Add(tx.GasTipCap(), baseFee), tx.GasFeeCap())
+}
+
+// ExportData exports blocks, transactions, receipts and logs of the given range into Parquet
+// or CSV files partitioned by epochs. The progress is saved into the checkpoint file after
+// every epoch, so an interrupted export is resumed after the last exported block.
+func ExportData(ctx context.Context, dataDir string, cacheRatio cachescale.Func, cfg ExportDataConfig) error {
+	if cfg.Format != ExportFormatParquet && cfg.Format != ExportFormatCSV {
+		return fmt.Errorf("unknown export format %q", cfg.Format)
+	}
+	if err := os.MkdirAll(cfg.OutDir, 0700); err != nil {
+		return err
+	}
+
+	chaindataDir := filepath.Join(dataDir, "chaindata")
+	dbs, err := db.MakeDbProducer(chaindataDir, cacheRatio)
+	if err != nil {
+		return err
+	}
+	defer dbs.Close()
+
+	gdb, err := db.MakeGossipDb(dbs, dataDir, false, cacheRatio)
+	if err != nil {
+		return err
+	}
+	defer gdb.Close()
+
+	latest := gdb.GetLatestBlockIndex()
+	from, to := idx.Block(cfg.From), idx.Block(cfg.To)
+	if cfg.Epochs {
+		from, to, err = epochBlocksRange(gdb, idx.Epoch(cfg.From), idx.Epoch(cfg.To))
+		if err != nil {
+			return err
+		}
+	}
+	if to == 0 || to > latest {
+		to = latest
+	}
+
+	cp, err := readExportCheckpoint(cfg.OutDir)
+	if err != nil {
+		return err
+	}
+	if cp != nil {
+		if cp.Format != cfg.Format {
+			return fmt.Errorf("the directory contains data exported in the %s format", cp.Format)
+		}
+		if cp.LastBlock >= from {
+			from = cp.LastBlock + 1
+			log.Info("Resuming the export", "checkpoint", cp.LastBlock)
+		}
+	}
+	if to < from {
+		log.Info("No blocks to export", "from", from, "to", to)
+		return nil
+	}
+
+	log.Info("Exporting chain data", "from", from, "to", to, "format", cfg.Format, "dir", cfg.OutDir)
+	if pruned := gdb.EvmStore().GetHistoryFirstBlock(); pruned > from {
+		log.Warn("Receipts and logs of pruned blocks are not exported", "first", from, "last", pruned-1)
+	}
+	signer := gsignercache.Wrap(types.LatestSignerForChainID(gdb.GetEvmChainConfig().ChainID))
+
+	var (
+		part     *exportPartition
+		rules    opera.Rules
+		prev     hash.Event
+		exported int
+	)
+	if from > 0 {
+		if prevBlock := gdb.GetBlock(from - 1); prevBlock != nil {
+			prev = prevBlock.Atropos
+		}
+	}
+	finishPartition := func() error {
+		if part == nil {
+			return nil
+		}
+		last := part.last
+		err := part.close()
+		part = nil
+		if err != nil {
+			return err
+		}
+		return writeExportCheckpoint(cfg.OutDir, exportCheckpoint{Format: cfg.Format, LastBlock: last})
+	}
+	defer func() {
+		if part != nil {
+			part.abort()
+		}
+	}()
+
+	start, reported := time.Now(), time.Time{}
+	for n := from; n <= to; n++ {
+		if err := ctx.Err(); err != nil {
+			if err := finishPartition(); err != nil {
+				return err
+			}
+			log.Warn("Export interrupted", "last", n-1, "exported", exported)
+			return err
+		}
+		block := gdb.GetBlock(n)
+		if block == nil {
+			return fmt.Errorf("block %d not found", n)
+		}
+		epoch := block.Atropos.Epoch()
+		if part != nil && part.epoch != epoch {
+			if err := finishPartition(); err != nil {
+				return err
+			}
+		}
+		if part == nil {
+			if es := gdb.GetHistoryEpochState(epoch); es != nil {
+				rules = es.Rules
+			}
+			part, err = openExportPartition(cfg.OutDir, cfg.Format, epoch, n)
+			if err != nil {
+				return err
+			}
+		}
+		if err := exportBlock(part, gdb, signer, n, block, prev, rules); err != nil {
+			return fmt.Errorf("failed to export block %d: %w", n, err)
+		}
+		part.last = n
+		prev = block.Atropos
+		exported++
+
+		if time.Since(reported) >= statsReportLimit {
+			log.Info("Exporting chain data", "block", n, "epoch", epoch, "last", to, "exported", exported, "elapsed", common.PrettyDuration(time.Since(start)))
+			reported = time.Now()
+		}
+	}
+	if err := finishPartition(); err != nil {
+		return err
+	}
+	log.Info("Exported chain data", "last", to, "exported", exported, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// exportBlock writes rows of the block, its transactions, receipts and logs
+func exportBlock(part *exportPartition, gdb *gossip.Store, signer types.Signer, n idx.Block, block *inter.Block, prev hash.Event, rules opera.Rules) error {
+	header := evmcore.ToEvmHeader(block, n, prev, rules)
+	txs := gdb.GetBlockTxs(n, block)
+	receipts := gdb.EvmStore().GetReceipts(n, signer, header.Hash, txs)
+	epoch := int64(part.epoch)
+	number := int64(n)
+
+	err := part.write(blocksTable,
+		epoch,
+		number,
+		header.Hash.Hex(),
+		header.ParentHash.Hex(),
+		header.Time.Unix(),
+		int64(header.Time),
+		header.Root.Hex(),
+		int64(header.GasUsed),
+		bigToString(header.BaseFee),
+		int64(len(txs)),
+		int64(len(block.Events)),
+	)
+	if err != nil {
+		return err
+	}
+
+	for i, tx := range txs {
+		from, _ := internaltx.Sender(signer, tx)
+		var maxFee, maxTip *big.Int
+		if tx.Type() == types.DynamicFeeTxType {
+			maxFee, maxTip = tx.GasFeeCap(), tx.GasTipCap()
+		}
+		err := part.write(transactionsTable,
+			epoch,
+			number,
+			int64(i),
+			tx.Hash().Hex(),
+			int64(tx.Type()),
+			from.Hex(),
+			addressToString(tx.To()),
+			int64(tx.Nonce()),
+			bigToString(tx.Value()),
+			int64(tx.Gas()),
+			bigToString(tx.GasPrice()),
+			bigToString(maxFee),
+			bigToString(maxTip),
+			hexutil.Encode(tx.Data()),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(receipts) != len(txs) {
+		if len(receipts) == 0 && n < gdb.EvmStore().GetHistoryFirstBlock() {
+			return nil // receipts of the block are pruned
+		}
+		return fmt.Errorf("block has %d receipts for %d txs", len(receipts), len(txs))
+	}
+	for i, r := range receipts {
+		var contract string
+		if r.ContractAddress != (common.Address{}) {
+			contract = r.ContractAddress.Hex()
+		}
+		err := part.write(receiptsTable,
+			epoch,
+			number,
+			int64(i),
+			r.TxHash.Hex(),
+			int64(r.Status),
+			int64(r.GasUsed),
+			int64(r.CumulativeGasUsed),
+			bigToString(effectiveGasPrice(txs[i], header.BaseFee)),
+			contract,
+			int64(len(r.Logs)),
+		)
+		if err != nil {
+			return err
+		}
+		for _, l := range r.Logs {
+			var topics [4]string
+			for j := 0; j < len(l.Topics) && j < len(topics); j++ {
+				topics[j] = l.Topics[j].Hex()
+			}
+			err := part.write(logsTable,
+				epoch,
+				number,
+				int64(i),
+				int64(l.Index),
+				l.TxHash.Hex(),
+				l.Address.Hex(),
+				topics[0],
+				topics[1],
+				topics[2],
+				topics[3],
+				hexutil.Encode(l.Data),
+			)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
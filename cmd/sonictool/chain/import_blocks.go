@@ -0,0 +1,173 @@
+package chain
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/utils/cachescale"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/status-im/keycard-go/hexutils"
+
+	"github.com/Fantom-foundation/go-opera/cmd/sonictool/db"
+	"github.com/Fantom-foundation/go-opera/evmcore"
+	"github.com/Fantom-foundation/go-opera/gossip"
+	"github.com/Fantom-foundation/go-opera/gossip/blockproc/evmmodule"
+	"github.com/Fantom-foundation/go-opera/inter/iblockproc"
+	"github.com/Fantom-foundation/go-opera/opera"
+	"github.com/Fantom-foundation/go-opera/utils/ioread"
+)
+
+// importChainReader provides headers of the imported blocks for the BLOCKHASH opcode
+type importChainReader struct {
+	gdb *gossip.Store
+}
+
+func (r importChainReader) GetHeader(_ common.Hash, n uint64) *evmcore.EvmHeader {
+	block := r.gdb.GetBlock(idx.Block(n))
+	if block == nil {
+		return nil
+	}
+	var prev hash.Event
+	if n != 0 {
+		if prevBlock := r.gdb.GetBlock(idx.Block(n - 1)); prevBlock != nil {
+			prev = prevBlock.Atropos
+		}
+	}
+	return evmcore.ToEvmHeader(block, idx.Block(n), prev, opera.Rules{})
+}
+
+func checkBlocksFileHeader(reader io.Reader) error {
+	headerAndVersion := make([]byte, len(blocksFileHeader)+len(blocksFileVersion))
+	err := ioread.ReadAll(reader, headerAndVersion)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(headerAndVersion[:len(blocksFileHeader)], blocksFileHeader) {
+		return errors.New("expected a blocks file, mismatched file header")
+	}
+	if !bytes.Equal(headerAndVersion[len(blocksFileHeader):], blocksFileVersion) {
+		got := hexutils.BytesToHex(headerAndVersion[len(blocksFileHeader):])
+		expected := hexutils.BytesToHex(blocksFileVersion)
+		return fmt.Errorf("wrong version of blocks file, got=%s, expected=%s", got, expected)
+	}
+	return nil
+}
+
+// ImportBlocks re-executes the exported blocks by the EVM on top of the live state of the database,
+// without the consensus processing. The database has to contain the state preceding the first
+// imported block, e.g. be initialized from the same genesis. Blocks which are already in the database
+// are skipped, so an interrupted import can be resumed.
+// State root of every executed block is checked against the exported one.
+// Only the blocks and the state are written, so the database is not usable for running the node.
+func ImportBlocks(ctx context.Context, r io.Reader, dataDir string, cacheRatio cachescale.Func) (err error) {
+	if err := checkBlocksFileHeader(r); err != nil {
+		return err
+	}
+
+	chaindataDir := filepath.Join(dataDir, "chaindata")
+	dbs, err := db.MakeDbProducer(chaindataDir, cacheRatio)
+	if err != nil {
+		return err
+	}
+	defer dbs.Close()
+
+	gdb, err := db.MakeGossipDb(dbs, dataDir, false, cacheRatio)
+	if err != nil {
+		return err
+	}
+	defer gdb.Close()
+
+	if err := gdb.EvmStore().Open(); err != nil {
+		return fmt.Errorf("failed to open EvmStore: %w", err)
+	}
+
+	bs, es := gdb.GetBlockEpochState()
+	statedb, err := gdb.EvmStore().GetLiveStateDb(bs.FinalizedStateRoot)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		gdb.SetBlockEpochState(bs, es)
+		if commitErr := gdb.Commit(); err == nil {
+			err = commitErr
+		}
+	}()
+
+	var (
+		stream    = rlp.NewStream(r, 0)
+		reader    = importChainReader{gdb}
+		evmModule = evmmodule.New()
+		epoch     *exportedEpoch
+		imported  int
+		txs       int
+	)
+	start, reported := time.Now(), time.Now()
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		record := new(exportedBlock)
+		err := stream.Decode(record)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if record.Epoch != nil {
+			epoch = record.Epoch
+		}
+		if record.Number <= bs.LastBlock.Idx {
+			continue
+		}
+		if record.Number != bs.LastBlock.Idx+1 {
+			return fmt.Errorf("missing blocks %d-%d", bs.LastBlock.Idx+1, record.Number-1)
+		}
+		if epoch == nil || epoch.Epoch != record.Block.Atropos.Epoch() {
+			return fmt.Errorf("rules of epoch %d are not found", record.Block.Atropos.Epoch())
+		}
+
+		blockCtx := iblockproc.BlockCtx{
+			Idx:     record.Number,
+			Time:    record.Block.Time,
+			Atropos: record.Block.Atropos,
+		}
+		evmCfg := epoch.Rules.EvmChainConfig(epoch.UpgradeHeights)
+		evmProcessor := evmModule.Start(blockCtx, statedb, reader, func(*types.Log) {}, epoch.Rules, evmCfg)
+		evmProcessor.Execute(record.Txs)
+		evmBlock, _, _ := evmProcessor.Finalize()
+		if root := hash.Hash(evmBlock.Root); root != record.Block.Root {
+			return fmt.Errorf("state root of block %d does not match (%s != %s)", record.Number, root.String(), record.Block.Root.String())
+		}
+
+		gdb.SetBlock(record.Number, record.Block)
+		gdb.SetBlockIndex(record.Block.Atropos, record.Number)
+		bs.LastBlock = blockCtx
+		bs.FinalizedStateRoot = record.Block.Root
+		imported++
+		txs += len(record.Txs)
+
+		if gdb.IsCommitNeeded() {
+			gdb.SetBlockEpochState(bs, es)
+			if err := gdb.Commit(); err != nil {
+				return err
+			}
+		}
+		if time.Since(reported) >= statsReportLimit {
+			log.Info("Importing blocks", "last", record.Number, "imported", imported, "txs", txs, "elapsed", common.PrettyDuration(time.Since(start)))
+			reported = time.Now()
+		}
+	}
+	log.Info("Blocks import is finished", "last", bs.LastBlock.Idx, "imported", imported, "txs", txs, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
@@ -36,6 +36,7 @@ func EventsImport(ctx *cli.Context, files ...string) error {
 	cfg.Opera.Protocol.EventsSemaphoreLimit.Num = math.MaxUint32
 	cfg.Emitter.Validator = emitter.ValidatorConfig{}
 	cfg.TxPool.Journal = ""
+	cfg.TxPool.Snapshot = ""
 	cfg.Node.IPCPath = ""
 	cfg.Node.HTTPHost = ""
 	cfg.Node.WSHost = ""
@@ -0,0 +1,49 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/Fantom-foundation/go-opera/cmd/sonictool/db"
+	"github.com/Fantom-foundation/go-opera/gossip"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/utils/cachescale"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// IndexTraces backfills the trace index for the given range of historic blocks.
+// If to is zero, blocks up to the beginning of the already indexed range
+// (or up to the latest block) are indexed.
+func IndexTraces(ctx context.Context, dataDir string, cacheRatio cachescale.Func, from, to idx.Block) error {
+	chaindataDir := filepath.Join(dataDir, "chaindata")
+	dbs, err := db.MakeDbProducer(chaindataDir, cacheRatio)
+	if err != nil {
+		return err
+	}
+	defer dbs.Close()
+
+	gdb, err := db.MakeGossipDb(dbs, dataDir, false, cacheRatio)
+	if err != nil {
+		return err
+	}
+	defer gdb.Close()
+
+	if err := gdb.EvmStore().Open(); err != nil {
+		return fmt.Errorf("failed to open EvmStore: %w", err)
+	}
+
+	if to == 0 {
+		to = gdb.GetLatestBlockIndex()
+		if first, _, ok := gdb.EvmStore().GetTraceIndexRange(); ok && first > 0 {
+			to = first - 1
+		}
+	}
+	if to < from {
+		log.Info("No blocks to index", "from", from, "to", to)
+		return nil
+	}
+
+	log.Info("Indexing traces", "from", from, "to", to)
+	return gossip.IndexTraces(ctx, gdb, from, to)
+}
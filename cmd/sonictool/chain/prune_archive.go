@@ -0,0 +1,59 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/utils/cachescale"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/Fantom-foundation/go-opera/cmd/sonictool/db"
+	"github.com/Fantom-foundation/go-opera/gossip"
+	"github.com/Fantom-foundation/go-opera/inter/state"
+)
+
+// PruneArchive prunes the archive to keep only the state of the given number of the latest blocks.
+// It's done offline, the running node never prunes the archive.
+func PruneArchive(ctx context.Context, dataDir string, cacheRatio cachescale.Func, retention uint64) error {
+	if retention == 0 {
+		return fmt.Errorf("the number of retained blocks must be positive")
+	}
+	chaindataDir := filepath.Join(dataDir, "chaindata")
+	dbs, err := db.MakeDbProducer(chaindataDir, cacheRatio)
+	if err != nil {
+		return err
+	}
+	defer dbs.Close()
+
+	gdb, err := db.MakeGossipDb(dbs, dataDir, false, cacheRatio)
+	if err != nil {
+		return err
+	}
+	defer gdb.Close()
+
+	last := gdb.GetLatestBlockIndex()
+	if uint64(last) < retention {
+		log.Info("No blocks to prune", "last", last, "retention", retention)
+		return nil
+	}
+	first := last - idx.Block(retention) + 1
+	if prunedFirst := gdb.EvmStore().GetArchiveFirstBlock(); first <= prunedFirst {
+		log.Info("Archive is already pruned", "first", prunedFirst)
+		return nil
+	}
+
+	log.Info("Pruning archive", "first", first, "last", last)
+	err = gdb.EvmStore().PruneArchive(ctx, first, func(statedb state.StateDB) error {
+		return gossip.ReplayBlocks(ctx, gdb, statedb, first+1, last)
+	})
+	if err != nil {
+		return err
+	}
+	if err := gdb.Commit(); err != nil {
+		return err
+	}
+	log.Info("Archive pruned", "first", first, "last", last)
+	return nil
+}
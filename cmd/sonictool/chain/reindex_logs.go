@@ -0,0 +1,45 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/Fantom-foundation/go-opera/cmd/sonictool/db"
+	"github.com/Fantom-foundation/go-opera/gossip"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/utils/cachescale"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// ReindexLogs rebuilds the logs index of the given range of historic blocks.
+// If to is zero, blocks up to the latest block are reindexed.
+func ReindexLogs(ctx context.Context, dataDir string, cacheRatio cachescale.Func, from, to idx.Block) error {
+	chaindataDir := filepath.Join(dataDir, "chaindata")
+	dbs, err := db.MakeDbProducer(chaindataDir, cacheRatio)
+	if err != nil {
+		return err
+	}
+	defer dbs.Close()
+
+	gdb, err := db.MakeGossipDb(dbs, dataDir, false, cacheRatio)
+	if err != nil {
+		return err
+	}
+	defer gdb.Close()
+
+	if err := gdb.EvmStore().Open(); err != nil {
+		return fmt.Errorf("failed to open EvmStore: %w", err)
+	}
+
+	if to == 0 {
+		to = gdb.GetLatestBlockIndex()
+	}
+	if to < from {
+		log.Info("No blocks to reindex", "from", from, "to", to)
+		return nil
+	}
+
+	log.Info("Reindexing logs", "from", from, "to", to)
+	return gossip.ReindexLogs(ctx, gdb, from, to)
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os/signal"
+	"syscall"
+
+	"github.com/Fantom-foundation/go-opera/cmd/sonictool/chain"
+	"github.com/Fantom-foundation/go-opera/config/flags"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func reindexLogs(ctx *cli.Context) error {
+	dataDir := ctx.GlobalString(flags.DataDirFlag.Name)
+	if dataDir == "" {
+		return fmt.Errorf("--%s need to be set", flags.DataDirFlag.Name)
+	}
+	cacheRatio, err := cacheScaler(ctx)
+	if err != nil {
+		return err
+	}
+
+	from, to, err := blockRangeArgs(ctx)
+	if err != nil {
+		return err
+	}
+
+	cancelCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	return chain.ReindexLogs(cancelCtx, dataDir, cacheRatio, from, to)
+}
@@ -192,6 +192,146 @@ Events are fully verified.`,
 			},
 		},
 
+		{
+			Name:     "chain",
+			Usage:    "Export/import the chain data",
+			Category: "MISCELLANEOUS COMMANDS",
+
+			Subcommands: []cli.Command{
+				{
+					Name:      "export-data",
+					Usage:     "Export blocks, transactions, receipts and logs into Parquet or CSV files",
+					ArgsUsage: "<dir> [<from> <to>]",
+					Action:    exportData,
+					Flags: []cli.Flag{
+						ExportFormatFlag,
+						ExportEpochsFlag,
+					},
+					Description: `
+    sonictool --datadir=<datadir> chain export-data <dir> [<from> <to>] [--format=csv] [--epochs]
+
+Exports blocks, transactions, receipts and logs from the database into
+the blocks, transactions, receipts and logs tables in the given directory.
+Files of the tables are partitioned by epochs as <table>/epoch=<N>/<table>-<first>-<last>.parquet,
+where first and last are the blocks range of the file.
+Optional second and third arguments control the first and last block to export,
+or the first and last epoch if --epochs is set. By default, all the blocks are exported.
+The progress is saved into the checkpoint.json file of the directory after every epoch,
+so a repeated command resumes an interrupted export after the last exported block.
+`,
+				},
+				{
+					Name:      "export-blocks",
+					Usage:     "Export blocks with their transactions for re-execution",
+					ArgsUsage: "<filename> [<blockFrom> <blockTo>]",
+					Action:    exportBlocks,
+					Description: `
+    sonictool --datadir=<datadir> chain export-blocks <filename> [<blockFrom> <blockTo>]
+
+Exports blocks together with all their executed transactions and the rules
+of their epochs as RLP records, so the blocks can be re-executed by import-blocks
+without the DAG events. Optional second and third arguments control the first
+and last block to export. If the file ends with .gz, the output will be gzipped.
+`,
+				},
+				{
+					Name:      "import-blocks",
+					Usage:     "Re-execute exported blocks on top of the live state",
+					ArgsUsage: "<filename> (<filename 2> ... <filename N>)",
+					Action:    importBlocks,
+					Description: `
+    sonictool --datadir=<datadir> chain import-blocks <filenames>
+
+Executes the blocks of the export-blocks files by the EVM on top of the live
+state, without the consensus processing. The datadir has to contain the state
+preceding the first imported block, e.g. be initialized from the same genesis.
+State root of every block is checked against the exported one, so the import
+stops at the first block whose execution diverges. Blocks which are already
+in the datadir are skipped. The resulting datadir is meant for the EVM execution
+benchmarks and checks only, it is not usable for running the node.
+`,
+				},
+			},
+		},
+
+		{
+			Name:     "trace",
+			Usage:    "Manage the transactions trace index",
+			Category: "MISCELLANEOUS COMMANDS",
+
+			Subcommands: []cli.Command{
+				{
+					Name:      "index",
+					Usage:     "Index calls of historic blocks transactions",
+					ArgsUsage: "[<blockFrom> <blockTo>]",
+					Action:    indexTraces,
+					Description: `
+    sonictool --datadir=<datadir> trace index [<blockFrom> <blockTo>]
+
+Replays historic blocks on top of the archive state and indexes calls
+of their transactions by from/to addresses, so trace_filter can be
+answered without replaying the whole blocks range.
+Optional first and second arguments control the first and last block
+to index. By default, blocks from the first one up to the beginning of
+the already indexed range are indexed.
+`,
+				},
+			},
+		},
+
+		{
+			Name:     "logs",
+			Usage:    "Manage the logs index",
+			Category: "MISCELLANEOUS COMMANDS",
+
+			Subcommands: []cli.Command{
+				{
+					Name:      "reindex",
+					Usage:     "Rebuild the logs index of historic blocks",
+					ArgsUsage: "[<blockFrom> <blockTo>]",
+					Action:    reindexLogs,
+					Description: `
+    sonictool --datadir=<datadir> logs reindex [<blockFrom> <blockTo>]
+
+Rebuilds the index of the logs by emitting contract addresses and topics
+from the stored receipts, e.g. for a datadir whose logs indexing was disabled.
+Optional first and second arguments control the first and last block
+to reindex. By default, all the blocks up to the latest one are reindexed.
+Blocks whose receipts are pruned are skipped.
+`,
+				},
+			},
+		},
+
+		{
+			Name:     "archive",
+			Usage:    "Manage the archive state database",
+			Category: "MISCELLANEOUS COMMANDS",
+
+			Subcommands: []cli.Command{
+				{
+					Name:      "prune",
+					Usage:     "Prune historic states older than the given number of latest blocks (offline)",
+					ArgsUsage: "<blocks>",
+					Action:    pruneArchive,
+					Description: `
+    sonictool --datadir=<datadir> archive prune <blocks>
+
+Rebuilds the archive state database to keep only the state of the given
+number of the latest blocks. The state of the first retained block is taken
+from the current archive and the following blocks are re-executed on top of it.
+Requests for the state of older blocks fail with the "state pruned" error.
+
+The pruning is offline only: the node has to be stopped, and the running node
+never prunes the archive, so it keeps growing until it's pruned again.
+The window is counted in blocks, windows of epochs aren't supported.
+The --archive.querywindow option of the node only limits the queryable states
+to the same window while the archive grows.
+`,
+				},
+			},
+		},
+
 		{
 			Action:      checkConfig,
 			Name:        "checkconfig",
@@ -365,6 +505,7 @@ Make sure you backup your keys regularly.
 						flags.DataDirFlag,
 						flags.KeyStoreDirFlag,
 						flags.PasswordFileFlag,
+						flags.ValidatorKeyTypeFlag,
 					},
 					Description: `
 Creates a new validator private key and prints the public key.
@@ -377,6 +518,10 @@ For non-interactive use the passphrase can be specified with the --validator.pas
 
 Note, this is meant to be used for testing only, it is a bad idea to save your
 password to file or expose in any other way.
+
+The key type is secp256k1 by default. An ed25519 key may be created with
+--validator.keytype=ed25519, but events signed by it are valid only after
+the Ed25519 network upgrade.
 `,
 				},
 				{
@@ -390,6 +535,35 @@ password to file or expose in any other way.
 					ArgsUsage: "<account address> <validator pubkey>",
 					Description: `
 Converts an account private key to a validator private key and saves in the validator keystore.
+`,
+				},
+				{
+					Name:      "export-protection",
+					Usage:     "Export the slashing protection DB",
+					Action:    validatorExportProtection,
+					Flags:     []cli.Flag{flags.DataDirFlag},
+					ArgsUsage: "<filename>",
+					Description: `
+Exports all the events signed by the validators of the node from the slashing
+protection DB (<DATADIR>/emitter/slashing-protection) into a JSON file.
+
+When the validator is migrated to another host, the exported file must be
+imported on the new host by the import-protection command before the
+validator is started there.
+`,
+				},
+				{
+					Name:      "import-protection",
+					Usage:     "Import the exported slashing protection records",
+					Action:    validatorImportProtection,
+					Flags:     []cli.Flag{flags.DataDirFlag},
+					ArgsUsage: "<filename>",
+					Description: `
+Imports the records exported by the export-protection command into the slashing
+protection DB. Records which are already in the DB are skipped.
+The emitter refuses to sign any event conflicting with the imported records.
+
+The node must be stopped during the import.
 `,
 				},
 			},
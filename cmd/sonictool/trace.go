@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"github.com/Fantom-foundation/go-opera/cmd/sonictool/chain"
+	"github.com/Fantom-foundation/go-opera/config/flags"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func indexTraces(ctx *cli.Context) error {
+	dataDir := ctx.GlobalString(flags.DataDirFlag.Name)
+	if dataDir == "" {
+		return fmt.Errorf("--%s need to be set", flags.DataDirFlag.Name)
+	}
+	cacheRatio, err := cacheScaler(ctx)
+	if err != nil {
+		return err
+	}
+
+	from, to, err := blockRangeArgs(ctx)
+	if err != nil {
+		return err
+	}
+
+	cancelCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	return chain.IndexTraces(cancelCtx, dataDir, cacheRatio, from, to)
+}
+
+// blockRangeArgs parses the optional first and last block arguments.
+// The first block is 1 and the last block is 0 if not specified.
+func blockRangeArgs(ctx *cli.Context) (from, to idx.Block, err error) {
+	from = idx.Block(1)
+	if len(ctx.Args()) > 0 {
+		n, err := strconv.ParseUint(ctx.Args().Get(0), 10, 64)
+		if err != nil {
+			return 0, 0, err
+		}
+		from = idx.Block(n)
+	}
+	if len(ctx.Args()) > 1 {
+		n, err := strconv.ParseUint(ctx.Args().Get(1), 10, 64)
+		if err != nil {
+			return 0, 0, err
+		}
+		to = idx.Block(n)
+	}
+	return from, to, nil
+}
@@ -2,13 +2,16 @@ package main
 
 import (
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/rand"
 	"errors"
 	"fmt"
 	"github.com/Fantom-foundation/go-opera/config"
+	"github.com/Fantom-foundation/go-opera/config/flags"
 	"github.com/Fantom-foundation/go-opera/inter/validatorpk"
 	"github.com/Fantom-foundation/go-opera/valkeystore"
 	"github.com/Fantom-foundation/go-opera/valkeystore/encryption"
+	"github.com/Fantom-foundation/go-opera/valkeystore/protection"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"gopkg.in/urfave/cli.v1"
@@ -37,15 +40,10 @@ func validatorKeyCreate(ctx *cli.Context) error {
 		return fmt.Errorf("failed to get passphrase: %w", err)
 	}
 
-	privateKeyECDSA, err := ecdsa.GenerateKey(crypto.S256(), rand.Reader)
+	privateKey, publicKey, err := generateValidatorKey(ctx.String(flags.ValidatorKeyTypeFlag.Name))
 	if err != nil {
 		return fmt.Errorf("failed to create account: %w", err)
 	}
-	privateKey := crypto.FromECDSA(privateKeyECDSA)
-	publicKey := validatorpk.PubKey{
-		Raw:  crypto.FromECDSAPub(&privateKeyECDSA.PublicKey),
-		Type: validatorpk.Types.Secp256k1,
-	}
 
 	_, _, keystoreDir, err := cfg.Node.AccountConfig()
 	if err != nil {
@@ -66,7 +64,10 @@ func validatorKeyCreate(ctx *cli.Context) error {
 
 	fmt.Printf("\nYour new key was generated\n\n")
 	fmt.Printf("Public key:                  %s\n", publicKey.String())
-	fmt.Printf("Public address of the key:   %s\n", crypto.PubkeyToAddress(privateKeyECDSA.PublicKey))
+	if publicKey.Type == validatorpk.Types.Secp256k1 {
+		pub, _ := crypto.UnmarshalPubkey(publicKey.Raw)
+		fmt.Printf("Public address of the key:   %s\n", crypto.PubkeyToAddress(*pub))
+	}
 	fmt.Printf("Path of the secret key file: %s\n\n", valKeystore.PathOf(publicKey))
 	fmt.Printf("- You can share your public key with anyone. Others need it to validate messages from you.\n")
 	fmt.Printf("- You must NEVER share the secret key with anyone! The key controls access to your validator!\n")
@@ -75,6 +76,31 @@ func validatorKeyCreate(ctx *cli.Context) error {
 	return nil
 }
 
+// generateValidatorKey generates a new validator private key of the given type.
+func generateValidatorKey(keyType string) ([]byte, validatorpk.PubKey, error) {
+	switch keyType {
+	case "secp256k1":
+		privateKeyECDSA, err := ecdsa.GenerateKey(crypto.S256(), rand.Reader)
+		if err != nil {
+			return nil, validatorpk.PubKey{}, err
+		}
+		return crypto.FromECDSA(privateKeyECDSA), validatorpk.PubKey{
+			Raw:  crypto.FromECDSAPub(&privateKeyECDSA.PublicKey),
+			Type: validatorpk.Types.Secp256k1,
+		}, nil
+	case "ed25519":
+		pub, priv, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, validatorpk.PubKey{}, err
+		}
+		return priv.Seed(), validatorpk.PubKey{
+			Raw:  pub,
+			Type: validatorpk.Types.Ed25519,
+		}, nil
+	}
+	return nil, validatorpk.PubKey{}, fmt.Errorf("unknown validator key type %q", keyType)
+}
+
 // validatorKeyConvert converts account key to validator key.
 func validatorKeyConvert(ctx *cli.Context) error {
 	if len(ctx.Args()) < 2 {
@@ -115,6 +141,68 @@ func validatorKeyConvert(ctx *cli.Context) error {
 	return nil
 }
 
+// openProtectionDB opens the slashing protection DB of the node defined by the CLI flags.
+func openProtectionDB(ctx *cli.Context) (*protection.DB, error) {
+	cfg, err := config.MakeAllConfigs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	file := cfg.Emitter.SlashingProtectionFile
+	db, err := protection.Open(file.Path, file.SyncMode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open slashing protection DB: %w", err)
+	}
+	return db, nil
+}
+
+// validatorExportProtection exports the slashing protection DB into a file.
+func validatorExportProtection(ctx *cli.Context) error {
+	if len(ctx.Args()) < 1 {
+		return fmt.Errorf("this command requires an argument")
+	}
+	db, err := openProtectionDB(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	fh, err := os.OpenFile(ctx.Args().First(), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+	n, err := db.Export(fh)
+	if err != nil {
+		return fmt.Errorf("failed to export slashing protection DB: %w", err)
+	}
+	fmt.Printf("Exported %d slashing protection records\n", n)
+	return nil
+}
+
+// validatorImportProtection imports the exported slashing protection records into the DB.
+func validatorImportProtection(ctx *cli.Context) error {
+	if len(ctx.Args()) < 1 {
+		return fmt.Errorf("this command requires an argument")
+	}
+	fh, err := os.Open(ctx.Args().First())
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	db, err := openProtectionDB(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	n, err := db.Import(fh)
+	if err != nil {
+		return fmt.Errorf("failed to import slashing protection records: %w", err)
+	}
+	fmt.Printf("Imported %d slashing protection records\n", n)
+	return nil
+}
+
 func findAccountKeypath(addr common.Address, keydir string) (keypath string, err error) {
 	addrStr := strings.ToLower(addr.String())[2:]
 	// find key path
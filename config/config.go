@@ -15,6 +15,7 @@ import (
 	"strings"
 
 	"github.com/Fantom-foundation/lachesis-base/abft"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
 	"github.com/Fantom-foundation/lachesis-base/utils/cachescale"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
@@ -135,6 +136,30 @@ func setTxPool(ctx *cli.Context, cfg *evmcore.TxPoolConfig) error {
 	if ctx.GlobalIsSet(flags.TxPoolRejournalFlag.Name) {
 		cfg.Rejournal = ctx.GlobalDuration(flags.TxPoolRejournalFlag.Name)
 	}
+	if ctx.GlobalIsSet(flags.TxPoolSnapshotFlag.Name) {
+		cfg.Snapshot = ctx.GlobalString(flags.TxPoolSnapshotFlag.Name)
+	}
+	if ctx.GlobalIsSet(flags.TxPoolSnapshotIntervalFlag.Name) {
+		cfg.SnapshotInterval = ctx.GlobalDuration(flags.TxPoolSnapshotIntervalFlag.Name)
+	}
+	if ctx.GlobalIsSet(flags.TxPoolSnapshotMaxTxsFlag.Name) {
+		cfg.SnapshotMaxTxs = ctx.GlobalInt(flags.TxPoolSnapshotMaxTxsFlag.Name)
+	}
+	if ctx.GlobalIsSet(flags.TxPoolSnapshotMaxAgeFlag.Name) {
+		cfg.SnapshotMaxAge = ctx.GlobalDuration(flags.TxPoolSnapshotMaxAgeFlag.Name)
+	}
+	if ctx.GlobalIsSet(flags.TxPoolPolicyFlag.Name) {
+		cfg.Policy = ctx.GlobalString(flags.TxPoolPolicyFlag.Name)
+	}
+	if ctx.GlobalIsSet(flags.TxPoolPolicySenderRateFlag.Name) {
+		cfg.PolicySenderRate = ctx.GlobalFloat64(flags.TxPoolPolicySenderRateFlag.Name)
+	}
+	if ctx.GlobalIsSet(flags.TxPoolPolicySenderBurstFlag.Name) {
+		cfg.PolicySenderBurst = ctx.GlobalInt(flags.TxPoolPolicySenderBurstFlag.Name)
+	}
+	if ctx.GlobalIsSet(flags.TxPoolHistoryFlag.Name) {
+		cfg.HistorySize = ctx.GlobalInt(flags.TxPoolHistoryFlag.Name)
+	}
 	if ctx.GlobalIsSet(flags.TxPoolPriceLimitFlag.Name) {
 		cfg.PriceLimit = ctx.GlobalUint64(flags.TxPoolPriceLimitFlag.Name)
 	}
@@ -174,6 +199,24 @@ func gossipConfigWithFlags(ctx *cli.Context, src gossip.Config) gossip.Config {
 	if ctx.GlobalIsSet(flags.RPCGlobalTimeoutFlag.Name) {
 		cfg.RPCTimeout = ctx.GlobalDuration(flags.RPCGlobalTimeoutFlag.Name)
 	}
+	if ctx.GlobalIsSet(flags.SyncModeFlag.Name) {
+		cfg.SyncMode = ctx.GlobalString(flags.SyncModeFlag.Name)
+	}
+	if ctx.GlobalIsSet(flags.SnapshotPeriodFlag.Name) {
+		cfg.Protocol.SnapSeeder.Period = idx.Epoch(ctx.GlobalUint64(flags.SnapshotPeriodFlag.Name))
+	}
+	if ctx.GlobalIsSet(flags.TxPoolPrivateFlag.Name) {
+		cfg.PrivateTxPool.Enabled = ctx.GlobalBool(flags.TxPoolPrivateFlag.Name)
+	}
+	if ctx.GlobalIsSet(flags.TxPoolPrivateAccountSlotsFlag.Name) {
+		cfg.PrivateTxPool.AccountSlots = ctx.GlobalInt(flags.TxPoolPrivateAccountSlotsFlag.Name)
+	}
+	if ctx.GlobalIsSet(flags.TxPoolPrivateLifetimeFlag.Name) {
+		cfg.PrivateTxPool.Lifetime = ctx.GlobalUint64(flags.TxPoolPrivateLifetimeFlag.Name)
+	}
+	if ctx.GlobalIsSet(flags.GPOStrategyFlag.Name) {
+		cfg.GPO.Strategy = ctx.GlobalString(flags.GPOStrategyFlag.Name)
+	}
 
 	return cfg
 }
@@ -198,6 +241,15 @@ func setEvmStore(ctx *cli.Context, datadir string, src  evmstore.StoreConfig) (e
 			cfg.DisableTxHashesIndexing = true
 		}
 	}
+	if ctx.GlobalIsSet(flags.TraceIndexFlag.Name) {
+		cfg.EnableTraceIndexing = ctx.GlobalBool(flags.TraceIndexFlag.Name)
+	}
+	if ctx.GlobalIsSet(flags.ArchiveQueryWindowFlag.Name) {
+		cfg.ArchiveQueryWindow = ctx.GlobalUint64(flags.ArchiveQueryWindowFlag.Name)
+	}
+	if ctx.GlobalIsSet(flags.HistoryRetentionFlag.Name) {
+		cfg.HistoryRetention = ctx.GlobalUint64(flags.HistoryRetentionFlag.Name)
+	}
 	return cfg, nil
 }
 
@@ -313,12 +365,25 @@ func MakeAllConfigsFromFile(ctx *cli.Context, configFile string) (*Config, error
 	if err != nil {
 		return nil, err
 	}
+	if len(cfg.Opera.Protocol.SnapSeeder.Dir) == 0 {
+		cfg.Opera.Protocol.SnapSeeder.Dir = path.Join(cfg.Node.DataDir, "snapshots")
+	}
+	if len(cfg.Opera.Protocol.SnapLeecher.Dir) == 0 {
+		cfg.Opera.Protocol.SnapLeecher.Dir = path.Join(cfg.Node.DataDir, "snapshots", "download")
+	}
+	if len(cfg.Emitter.SlashingProtectionFile.Path) == 0 {
+		cfg.Emitter.SlashingProtectionFile.Path = path.Join(cfg.Node.DataDir, "emitter", "slashing-protection")
+	}
 	if cfg.Emitter.Validator.ID != 0 && len(cfg.Emitter.PrevEmittedEventFile.Path) == 0 {
+		// the last emitted event file of previous versions is respected if exists
 		cfg.Emitter.PrevEmittedEventFile.Path = path.Join(cfg.Node.DataDir, "emitter", fmt.Sprintf("last-%d", cfg.Emitter.Validator.ID))
 	}
 	if err := setTxPool(ctx, &cfg.TxPool); err != nil {
 		return nil, err
 	}
+	if cfg.Opera.PrivateTxPool.Enabled && cfg.Emitter.Validator.ID == 0 {
+		return nil, errors.New("private transaction pool may be enabled only on a validator node")
+	}
 
 	// Process DBs defaults in the end because they are applied only in absence of config or flags
 	cfg, err = setDBConfig(cfg, cacheRatio)
@@ -17,7 +17,10 @@
 package flags
 
 import (
+	"github.com/Fantom-foundation/go-opera/evmcore"
 	"github.com/Fantom-foundation/go-opera/gossip"
+	"github.com/Fantom-foundation/go-opera/gossip/gasprice"
+	"github.com/Fantom-foundation/go-opera/valkeystore"
 	"github.com/Fantom-foundation/lachesis-base/utils/cachescale"
 	pcsclite "github.com/gballet/go-libpcsclite"
 	"gopkg.in/urfave/cli.v1"
@@ -85,6 +88,63 @@ var (
 		Usage: "Time interval to regenerate the local transaction journal",
 		Value: core.DefaultTxPoolConfig.Rejournal,
 	}
+	TxPoolSnapshotFlag = cli.StringFlag{
+		Name:  "txpool.snapshot",
+		Usage: "Disk snapshot of all pending and queued transactions, remote ones included, to survive node restarts (disabled if empty)",
+	}
+	TxPoolSnapshotIntervalFlag = cli.DurationFlag{
+		Name:  "txpool.snapshot.interval",
+		Usage: "Time interval to regenerate the transactions snapshot",
+		Value: evmcore.DefaultTxPoolConfig.SnapshotInterval,
+	}
+	TxPoolSnapshotMaxTxsFlag = cli.IntFlag{
+		Name:  "txpool.snapshot.maxtxs",
+		Usage: "Maximum number of transactions to keep in the transactions snapshot",
+		Value: evmcore.DefaultTxPoolConfig.SnapshotMaxTxs,
+	}
+	TxPoolSnapshotMaxAgeFlag = cli.DurationFlag{
+		Name:  "txpool.snapshot.maxage",
+		Usage: "Maximum age of the transactions snapshot to be loaded on startup",
+		Value: evmcore.DefaultTxPoolConfig.SnapshotMaxAge,
+	}
+	TxPoolPolicyFlag = cli.StringFlag{
+		Name:  "txpool.policy",
+		Usage: "JSON file of the transactions admission policy with sender/recipient allow/deny lists and blocked contract methods, reloaded on change (disabled if empty)",
+	}
+	TxPoolPolicySenderRateFlag = cli.Float64Flag{
+		Name:  "txpool.policy.senderrate",
+		Usage: "Maximum number of transactions per second admitted from a single sender (unlimited if 0)",
+		Value: evmcore.DefaultTxPoolConfig.PolicySenderRate,
+	}
+	TxPoolPolicySenderBurstFlag = cli.IntFlag{
+		Name:  "txpool.policy.senderburst",
+		Usage: "Maximum number of transactions admitted from a single sender at once",
+		Value: evmcore.DefaultTxPoolConfig.PolicySenderBurst,
+	}
+	TxPoolHistoryFlag = cli.IntFlag{
+		Name:  "txpool.history",
+		Usage: "Number of recent transaction lifecycle events kept in memory for txpool_txHistory and txpool_events (disabled if 0)",
+		Value: evmcore.DefaultTxPoolConfig.HistorySize,
+	}
+	TxPoolPrivateFlag = cli.BoolFlag{
+		Name:  "txpool.private",
+		Usage: "Accept private transactions by eth_sendPrivateTransaction, which are included only into the events of the local validator",
+	}
+	TxPoolPrivateAccountSlotsFlag = cli.IntFlag{
+		Name:  "txpool.private.accountslots",
+		Usage: "Maximum number of private transactions per account",
+		Value: evmcore.DefaultPrivateTxPoolConfig.AccountSlots,
+	}
+	TxPoolPrivateLifetimeFlag = cli.Uint64Flag{
+		Name:  "txpool.private.lifetime",
+		Usage: "Number of blocks after which a non-included private transaction is dropped",
+		Value: evmcore.DefaultPrivateTxPoolConfig.Lifetime,
+	}
+	GPOStrategyFlag = cli.StringFlag{
+		Name:  "gpo.strategy",
+		Usage: `Gas price oracle strategy ("model", "percentile" of the tips of the recent blocks or "blend" of both)`,
+		Value: gasprice.ModelStrategy,
+	}
 	TxPoolPriceLimitFlag = cli.Uint64Flag{
 		Name:  "txpool.pricelimit",
 		Usage: "Minimum gas price limit to enforce for acceptance into the pool",
@@ -212,6 +272,20 @@ var (
 		Usage: "HTTP path prefix on which JSON-RPC is served. Use '/' to serve on all paths.",
 		Value: "",
 	}
+	GraphQLEnabledFlag = cli.BoolFlag{
+		Name:  "graphql",
+		Usage: "Enable GraphQL on the HTTP-RPC server. Note that GraphQL can only be started if an HTTP server is started as well.",
+	}
+	GraphQLCORSDomainFlag = cli.StringFlag{
+		Name:  "graphql.corsdomain",
+		Usage: "Comma separated list of domains from which to accept cross origin requests (browser enforced)",
+		Value: "",
+	}
+	GraphQLVirtualHostsFlag = cli.StringFlag{
+		Name:  "graphql.vhosts",
+		Usage: "Comma separated list of virtual hostnames from which to accept requests (server enforced). Accepts '*' wildcard.",
+		Value: strings.Join(node.DefaultConfig.GraphQLVirtualHosts, ","),
+	}
 
 	// Network Settings
 	MaxPeersFlag = cli.IntFlag{
@@ -316,6 +390,31 @@ var (
 		Usage: `Mode of the node ("rpc" or "validator")`,
 		Value: "rpc",
 	}
+	TraceIndexFlag = cli.BoolFlag{
+		Name:  "trace.index",
+		Usage: "Enables indexing of transactions calls by from/to addresses to speed up trace_filter",
+	}
+	ArchiveQueryWindowFlag = cli.Uint64Flag{
+		Name:  "archive.querywindow",
+		Usage: "Number of recent blocks whose historic state may be queried (0 = all blocks). Only the queries are filtered: the node never prunes the archive, which keeps growing. To reclaim the disk space, stop the node and run 'sonictool archive prune <blocks>'. The window is counted in blocks, epochs aren't supported",
+	}
+	HistoryRetentionFlag = cli.Uint64Flag{
+		Name:  "history.retention",
+		Usage: "Number of recent blocks whose receipts, transactions positions and logs are kept (0 = all blocks)",
+	}
+	SyncModeFlag = cli.StringFlag{
+		Name:  "syncmode",
+		Usage: `Sync mode of the node ("dag" or "llr"), "llr" downloads the history verified by LLR votes instead of processing the historic events`,
+		Value: gossip.SyncModeDAG,
+	}
+	SyncSnapshotFlag = cli.StringFlag{
+		Name:  "syncmode.snapshot",
+		Usage: "Live state snapshot file to start the LLR sync from, it's imported only before the first block after the genesis is processed",
+	}
+	SnapshotPeriodFlag = cli.Uint64Flag{
+		Name:  "snapshot.period",
+		Usage: "Make a live state snapshot for peers every N epochs, requires the archive (0 to disable)",
+	}
 	ExitWhenAgeFlag = cli.DurationFlag{
 		Name:  "exitwhensynced.age",
 		Usage: "Exits after synchronisation reaches the required age",
@@ -341,4 +440,34 @@ var (
 		Usage: "Password to unlock validator private key",
 		Value: "",
 	}
+	ValidatorKeyTypeFlag = cli.StringFlag{
+		Name:  "validator.keytype",
+		Usage: "Type of a new validator key: secp256k1 or ed25519 (requires the Ed25519 network upgrade)",
+		Value: "secp256k1",
+	}
+	ValidatorSignerFlag = cli.StringFlag{
+		Name:  "validator.signer",
+		Usage: "URL of a remote signer to sign events by, instead of the validator key from the keystore",
+		Value: "",
+	}
+	ValidatorSignerCertFlag = cli.StringFlag{
+		Name:  "validator.signer.cert",
+		Usage: "Client certificate file for the remote signer TLS authentication",
+		Value: "",
+	}
+	ValidatorSignerKeyFlag = cli.StringFlag{
+		Name:  "validator.signer.key",
+		Usage: "Client private key file for the remote signer TLS authentication",
+		Value: "",
+	}
+	ValidatorSignerCAFlag = cli.StringFlag{
+		Name:  "validator.signer.ca",
+		Usage: "CA certificate file to verify the remote signer",
+		Value: "",
+	}
+	ValidatorSignerTimeoutFlag = cli.DurationFlag{
+		Name:  "validator.signer.timeout",
+		Usage: "Timeout of a remote signer request",
+		Value: valkeystore.DefaultRemoteSignerTimeout,
+	}
 )
@@ -6,9 +6,11 @@ import (
 	"github.com/Fantom-foundation/go-opera/evmcore"
 	"github.com/Fantom-foundation/go-opera/gossip"
 	"github.com/Fantom-foundation/go-opera/gossip/emitter"
+	"github.com/Fantom-foundation/go-opera/graphql"
 	"github.com/Fantom-foundation/go-opera/integration"
 	"github.com/Fantom-foundation/go-opera/utils/errlock"
 	"github.com/Fantom-foundation/go-opera/valkeystore"
+	"github.com/Fantom-foundation/go-opera/valkeystore/protection"
 	"github.com/Fantom-foundation/lachesis-base/inter/idx"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/node"
@@ -34,6 +36,15 @@ func MakeNode(ctx *cli.Context, cfg *Config) (*node.Node, *gossip.Service, func(
 		return nil, nil, nil, err
 	}
 
+	if snapshot := ctx.GlobalString(flags.SyncSnapshotFlag.Name); snapshot != "" {
+		if cfg.Opera.SyncMode != gossip.SyncModeLLR {
+			return nil, nil, nil, fmt.Errorf("--%s requires --%s=%s", flags.SyncSnapshotFlag.Name, flags.SyncModeFlag.Name, gossip.SyncModeLLR)
+		}
+		if err := integration.ImportStateSnapshot(path.Join(cfg.Node.DataDir, "chaindata"), cfg.AppConfigs(), snapshot); err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to import state snapshot: %w", err)
+		}
+	}
+
 	engine, dagIndex, gdb, cdb, blockProc, closeDBs, err := integration.MakeEngine(path.Join(cfg.Node.DataDir, "chaindata"), cfg.AppConfigs())
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("failed to make consensus engine: %w", err)
@@ -52,6 +63,20 @@ func MakeNode(ctx *cli.Context, cfg *Config) (*node.Node, *gossip.Service, func(
 		}
 	})
 
+	var protectionDB *protection.DB
+	if cfg.Emitter.Validator.ID != 0 {
+		protectionDB, err = protection.Open(cfg.Emitter.SlashingProtectionFile.Path, cfg.Emitter.SlashingProtectionFile.SyncMode)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to open slashing protection DB: %w", err)
+		}
+		// closed after the emitter is stopped together with the node
+		cleanup = append(cleanup, func() {
+			if err := protectionDB.Close(); err != nil {
+				log.Warn("Failed to close slashing protection DB", "err", err)
+			}
+		})
+	}
+
 	// substitute default bootnodes if requested
 	networkName := ""
 	if gdb.HasBlockEpochState() {
@@ -89,20 +114,22 @@ func MakeNode(ctx *cli.Context, cfg *Config) (*node.Node, *gossip.Service, func(
 		log.Info("Unlocked fake validator account", "address", coinbase.Address.Hex())
 	}
 
-	// unlock validator key
-	if !valPubkey.Empty() {
-		err := unlockValidatorKey(ctx, valPubkey, valKeystore)
-		if err != nil {
-			return nil, nil, nil, fmt.Errorf("failed to unlock validator key: %w", err)
-		}
+	signer, err := makeValidatorSigner(ctx, valPubkey, valKeystore)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if protectionDB != nil {
+		signer = valkeystore.NewProtectedSigner(signer, protectionDB)
 	}
-	signer := valkeystore.NewSigner(valKeystore)
 
 	// Create and register a gossip network service.
 	newTxPool := func(reader evmcore.StateReader) gossip.TxPool {
 		if cfg.TxPool.Journal != "" {
 			cfg.TxPool.Journal = path.Join(cfg.Node.DataDir, cfg.TxPool.Journal)
 		}
+		if cfg.TxPool.Snapshot != "" {
+			cfg.TxPool.Snapshot = path.Join(cfg.Node.DataDir, cfg.TxPool.Snapshot)
+		}
 		return evmcore.NewTxPool(cfg.TxPool, reader.Config(), reader)
 	}
 	haltCheck := func(oldEpoch, newEpoch idx.Epoch, age time.Time) bool {
@@ -137,13 +164,19 @@ func MakeNode(ctx *cli.Context, cfg *Config) (*node.Node, *gossip.Service, func(
 	}
 
 	if cfg.Emitter.Validator.ID != 0 {
-		svc.RegisterEmitter(emitter.NewEmitter(cfg.Emitter, svc.EmitterWorld(signer)))
+		svc.RegisterEmitter(emitter.NewEmitter(cfg.Emitter, svc.EmitterWorld(signer, protectionDB)))
 	}
 
 	stack.RegisterAPIs(svc.APIs())
 	stack.RegisterProtocols(svc.Protocols())
 	stack.RegisterLifecycle(svc)
 
+	if ctx.GlobalBool(flags.GraphQLEnabledFlag.Name) {
+		if err := graphql.New(stack, svc.EthAPI, cfg.Opera.FilterAPI, cfg.Node.GraphQLCors, cfg.Node.GraphQLVirtualHosts); err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to register the GraphQL service: %w", err)
+		}
+	}
+
 	success = true // skip cleanup in defer - keep it for the returned cleanup function
 	return stack, svc, func() {
 		for i := len(cleanup) - 1; i >= 0; i-- {
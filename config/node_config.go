@@ -27,6 +27,7 @@ func SetNodeConfig(ctx *cli.Context, cfg *node.Config) error {
 	}
 	setHTTP(ctx, cfg)
 	setWS(ctx, cfg)
+	setGraphQL(ctx, cfg)
 	setSmartCard(ctx, cfg)
 
 	if identity := ctx.GlobalString(flags.IdentityFlag.Name); len(identity) > 0 {
@@ -218,6 +219,17 @@ func setWS(ctx *cli.Context, cfg *node.Config) {
 	}
 }
 
+// setGraphQL applies the CORS and virtual hosts of the GraphQL endpoint
+// from the set command line flags.
+func setGraphQL(ctx *cli.Context, cfg *node.Config) {
+	if ctx.GlobalIsSet(flags.GraphQLCORSDomainFlag.Name) {
+		cfg.GraphQLCors = splitAndTrim(ctx.GlobalString(flags.GraphQLCORSDomainFlag.Name))
+	}
+	if ctx.GlobalIsSet(flags.GraphQLVirtualHostsFlag.Name) {
+		cfg.GraphQLVirtualHosts = splitAndTrim(ctx.GlobalString(flags.GraphQLVirtualHostsFlag.Name))
+	}
+}
+
 // setIPC creates an IPC path configuration from the set command line flags,
 // returning an empty string if IPC was explicitly disabled, or the set path.
 func setIPC(ctx *cli.Context, cfg *node.Config) error {
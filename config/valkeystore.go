@@ -73,3 +73,31 @@ func unlockValidatorKey(ctx *cli.Context, pubKey validatorpk.PubKey, valKeystore
 	// All trials expended to unlock account, bail out
 	return err
 }
+
+// makeValidatorSigner creates the remote signer if --validator.signer is set,
+// otherwise the validator key is unlocked in the keystore to sign events locally.
+func makeValidatorSigner(ctx *cli.Context, pubKey validatorpk.PubKey, valKeystore valkeystore.KeystoreI) (valkeystore.SignerI, error) {
+	if url := ctx.GlobalString(flags.ValidatorSignerFlag.Name); url != "" {
+		signer, err := valkeystore.NewRemoteSigner(valkeystore.RemoteSignerConfig{
+			URL:      url,
+			CertFile: ctx.GlobalString(flags.ValidatorSignerCertFlag.Name),
+			KeyFile:  ctx.GlobalString(flags.ValidatorSignerKeyFlag.Name),
+			CAFile:   ctx.GlobalString(flags.ValidatorSignerCAFlag.Name),
+			Timeout:  ctx.GlobalDuration(flags.ValidatorSignerTimeoutFlag.Name),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create remote signer: %w", err)
+		}
+		log.Info("Using remote validator signer", "url", url)
+		return signer, nil
+	}
+
+	// unlock validator key
+	if !pubKey.Empty() {
+		err := unlockValidatorKey(ctx, pubKey, valKeystore)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unlock validator key: %w", err)
+		}
+	}
+	return valkeystore.NewSigner(valKeystore), nil
+}
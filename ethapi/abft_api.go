@@ -6,6 +6,8 @@ import (
 	"github.com/Fantom-foundation/lachesis-base/inter/idx"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/Fantom-foundation/go-opera/inter/iblockproc"
 )
 
 // PublicAbftAPI provides an API to access consensus related information.
@@ -27,18 +29,57 @@ func (s *PublicAbftAPI) GetValidators(ctx context.Context, epoch rpc.BlockNumber
 	if es == nil {
 		return nil, nil
 	}
+	profiles := es.ValidatorProfiles
+	if epoch == rpc.PendingBlockNumber {
+		profiles = bs.NextValidatorProfiles
+	}
+	return rpcMarshalValidators(es.Validators.IDs(), profiles), nil
+}
+
+func rpcMarshalValidators(ids []idx.ValidatorID, profiles iblockproc.ValidatorProfiles) map[hexutil.Uint64]interface{} {
 	res := map[hexutil.Uint64]interface{}{}
-	for _, vid := range es.Validators.IDs() {
-		profiles := es.ValidatorProfiles
-		if epoch == rpc.PendingBlockNumber {
-			profiles = bs.NextValidatorProfiles
-		}
+	for _, vid := range ids {
 		res[hexutil.Uint64(vid)] = map[string]interface{}{
 			"weight": (*hexutil.Big)(profiles[vid].Weight),
 			"pubkey": profiles[vid].PubKey.String(),
 		}
 	}
-	return res, nil
+	return res
+}
+
+// NewEpoch creates a subscription that is triggered each time an epoch is sealed.
+// The notification contains the new epoch and its validators.
+func (s *PublicAbftAPI) NewEpoch(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		epochs := make(chan *iblockproc.EpochState, 16)
+		epochsSub := s.b.SubscribeNewEpochNotify(epochs)
+
+		for {
+			select {
+			case es := <-epochs:
+				_ = notifier.Notify(rpcSub.ID, map[string]interface{}{
+					"epoch":      hexutil.Uint64(es.Epoch),
+					"epochStart": hexutil.Uint64(es.EpochStart),
+					"validators": rpcMarshalValidators(es.Validators.IDs(), es.ValidatorProfiles),
+				})
+			case <-rpcSub.Err():
+				epochsSub.Unsubscribe()
+				return
+			case <-notifier.Closed():
+				epochsSub.Unsubscribe()
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
 }
 
 // GetDowntime returns validator's downtime.
@@ -23,7 +23,6 @@ import (
 	"fmt"
 	"math/big"
 	"runtime/debug"
-	"strings"
 	"time"
 
 	"github.com/Fantom-foundation/lachesis-base/hash"
@@ -51,8 +50,10 @@ import (
 
 	"github.com/Fantom-foundation/go-opera/evmcore"
 	"github.com/Fantom-foundation/go-opera/gossip/gasprice"
+	"github.com/Fantom-foundation/go-opera/inter"
 	"github.com/Fantom-foundation/go-opera/inter/state"
 	"github.com/Fantom-foundation/go-opera/opera"
+	"github.com/Fantom-foundation/go-opera/txtrace"
 	"github.com/Fantom-foundation/go-opera/utils/signers/gsignercache"
 	"github.com/Fantom-foundation/go-opera/utils/signers/internaltx"
 )
@@ -70,12 +71,13 @@ var (
 // PublicEthereumAPI provides an API to access Ethereum related information.
 // It offers only methods that operate on public data that is freely available to anyone.
 type PublicEthereumAPI struct {
-	b Backend
+	b    Backend
+	fees *feeHistoryCache
 }
 
 // NewPublicEthereumAPI creates a new Ethereum protocol API.
 func NewPublicEthereumAPI(b Backend) *PublicEthereumAPI {
-	return &PublicEthereumAPI{b}
+	return &PublicEthereumAPI{b, newFeeHistoryCache(b)}
 }
 
 // GasPrice returns a suggestion for a gas price for legacy transactions.
@@ -133,18 +135,35 @@ func (s *PublicEthereumAPI) FeeHistory(ctx context.Context, blockCount rpc.Decim
 		oldest = 0
 	}
 
-	baseFee := s.b.MinGasPrice()
-
-	tips := make([]*hexutil.Big, 0, len(rewardPercentiles))
-	for _, p := range rewardPercentiles {
-		tip := s.b.SuggestGasTipCap(ctx, uint64(gasprice.DecimalUnit*p/100.0))
-		tips = append(tips, (*hexutil.Big)(tip))
-	}
 	res.OldestBlock.ToInt().SetUint64(uint64(oldest))
-	for i := uint64(0); i < uint64(last-oldest+1); i++ {
-		res.Reward = append(res.Reward, tips)
-		res.BaseFee = append(res.BaseFee, (*hexutil.Big)(baseFee))
-		res.GasUsedRatio = append(res.GasUsedRatio, 0.99)
+	for n := oldest; n <= last; n++ {
+		fees, err := s.fees.get(ctx, uint64(n))
+		if err != nil {
+			return nil, err
+		}
+		if fees == nil {
+			return nil, fmt.Errorf("block #%d not found", n)
+		}
+		if len(rewardPercentiles) != 0 {
+			rewards := fees.rewards(rewardPercentiles)
+			row := make([]*hexutil.Big, len(rewards))
+			for i, reward := range rewards {
+				row[i] = (*hexutil.Big)(reward)
+			}
+			res.Reward = append(res.Reward, row)
+		}
+		res.BaseFee = append(res.BaseFee, (*hexutil.Big)(fees.baseFee))
+		res.GasUsedRatio = append(res.GasUsedRatio, fees.gasUsedRatio)
+	}
+	// the base fee of the next block is included as well
+	next, err := s.fees.get(ctx, uint64(last+1))
+	if err != nil {
+		return nil, err
+	}
+	if next != nil {
+		res.BaseFee = append(res.BaseFee, (*hexutil.Big)(next.baseFee))
+	} else {
+		res.BaseFee = append(res.BaseFee, (*hexutil.Big)(s.b.MinGasPrice()))
 	}
 	return res, nil
 }
@@ -169,6 +188,9 @@ func (s *PublicEthereumAPI) Syncing() (interface{}, error) {
 		"currentBlockTime": hexutil.Uint64(progress.CurrentBlockTime),
 		"highestBlock":     hexutil.Uint64(progress.HighestBlock),
 		"highestEpoch":     hexutil.Uint64(progress.HighestEpoch),
+		"syncMode":         progress.SyncMode,
+		"llrEpoch":         hexutil.Uint64(progress.LlrEpoch),
+		"llrBlock":         hexutil.Uint64(progress.LlrBlock),
 		"pulledStates":     hexutil.Uint64(0), // back-compatibility
 		"knownStates":      hexutil.Uint64(0), // back-compatibility
 	}, nil
@@ -212,6 +234,20 @@ func (s *PublicTxPoolAPI) Content() map[string]map[string]map[string]*RPCTransac
 	return content
 }
 
+// PrivateContent returns the transactions contained within the private transaction pool.
+func (s *PublicTxPoolAPI) PrivateContent() map[string]map[string]*RPCTransaction {
+	content := make(map[string]map[string]*RPCTransaction)
+	curHeader := s.b.CurrentBlock().Header()
+	for account, txs := range s.b.PrivateTxPoolContent() {
+		dump := make(map[string]*RPCTransaction)
+		for _, tx := range txs {
+			dump[fmt.Sprintf("%d", tx.Nonce())] = newRPCPendingTransaction(tx, curHeader.BaseFee)
+		}
+		content[account.Hex()] = dump
+	}
+	return content
+}
+
 // ContentFrom returns the transactions contained within the transaction pool.
 func (s *PublicTxPoolAPI) ContentFrom(addr common.Address) map[string]map[string]*RPCTransaction {
 	content := make(map[string]map[string]*RPCTransaction, 2)
@@ -279,6 +315,48 @@ func (s *PublicTxPoolAPI) Inspect() map[string]map[string]map[string]string {
 	return content
 }
 
+// TxHistory returns the recent lifecycle events of the transaction in the pool,
+// such as additions, replacements, inclusions into blocks and drops with their reasons.
+// An empty result means the transaction was never received or is forgotten.
+func (s *PublicTxPoolAPI) TxHistory(hash common.Hash) []evmcore.TxEvent {
+	events := s.b.TxPoolHistory(hash)
+	if events == nil {
+		return []evmcore.TxEvent{}
+	}
+	return events
+}
+
+// Events creates a subscription that is triggered on each lifecycle event of
+// the transactions in the pool.
+func (s *PublicTxPoolAPI) Events(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		events := make(chan evmcore.TxEvent, 128)
+		eventsSub := s.b.SubscribeTxPoolEvents(events)
+
+		for {
+			select {
+			case ev := <-events:
+				_ = notifier.Notify(rpcSub.ID, ev)
+			case <-rpcSub.Err():
+				eventsSub.Unsubscribe()
+				return
+			case <-notifier.Closed():
+				eventsSub.Unsubscribe()
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
 // PublicAccountAPI provides an API to access accounts managed by this node.
 // It offers only methods that can retrieve accounts.
 type PublicAccountAPI struct {
@@ -958,17 +1036,54 @@ func (diff *StateOverride) Apply(state state.StateDB) error {
 	return nil
 }
 
-func DoCall(ctx context.Context, b Backend, args TransactionArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *StateOverride, timeout time.Duration, globalGasCap uint64) (*evmcore.ExecutionResult, error) {
-	defer func(start time.Time) { log.Debug("Executing EVM call finished", "runtime", time.Since(start)) }(time.Now())
+// BlockOverrides is a set of header fields to override during the execution
+// of a message call.
+type BlockOverrides struct {
+	Number  *hexutil.Big    `json:"number"`
+	Time    *hexutil.Uint64 `json:"time"`
+	BaseFee *hexutil.Big    `json:"baseFee"`
+}
 
+// Apply returns a copy of the header with the overridden fields.
+func (diff *BlockOverrides) Apply(header *evmcore.EvmHeader) *evmcore.EvmHeader {
+	if diff == nil {
+		return header
+	}
+	overridden := *header
+	if diff.Number != nil {
+		overridden.Number = new(big.Int).Set(diff.Number.ToInt())
+	}
+	if diff.Time != nil {
+		overridden.Time = inter.FromUnix(int64(*diff.Time))
+	}
+	if diff.BaseFee != nil {
+		overridden.BaseFee = new(big.Int).Set(diff.BaseFee.ToInt())
+	}
+	return &overridden
+}
+
+// callState returns the state and the header of the given block for a message call execution,
+// with the state and block overrides applied. The returned state has to be released.
+func callState(ctx context.Context, b Backend, blockNrOrHash rpc.BlockNumberOrHash, overrides *StateOverride, blockOverrides *BlockOverrides) (state.StateDB, *evmcore.EvmHeader, error) {
 	state, header, err := b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
 	if state == nil || err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	defer state.Release()
 	if err := overrides.Apply(state); err != nil {
+		state.Release()
+		return nil, nil, err
+	}
+	return state, blockOverrides.Apply(header), nil
+}
+
+func DoCall(ctx context.Context, b Backend, args TransactionArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *StateOverride, timeout time.Duration, globalGasCap uint64) (*evmcore.ExecutionResult, error) {
+	defer func(start time.Time) { log.Debug("Executing EVM call finished", "runtime", time.Since(start)) }(time.Now())
+
+	state, header, err := callState(ctx, b, blockNrOrHash, overrides, nil)
+	if state == nil || err != nil {
 		return nil, err
 	}
+	defer state.Release()
 	// Setup context so it may be cancelled the call has completed
 	// or, in case of unmetered gas, setup a context with a timeout.
 	var cancel context.CancelFunc
@@ -1900,6 +2015,28 @@ func (s *PublicTransactionPoolAPI) SendRawTransaction(ctx context.Context, encod
 	return SubmitTransaction(ctx, s.b, tx)
 }
 
+// SendPrivateTransaction adds the signed transaction into the private transaction pool.
+// The transaction isn't broadcast to peers, it's included only into events of
+// the local validator and expires if it isn't included in time.
+// The transaction is rejected unless the node is a validator with the private pool enabled.
+func (s *PublicTransactionPoolAPI) SendPrivateTransaction(ctx context.Context, encodedTx hexutil.Bytes) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(encodedTx); err != nil {
+		return common.Hash{}, err
+	}
+	if err := checkTxFee(tx.GasPrice(), tx.Gas(), s.b.RPCTxFeeCap()); err != nil {
+		return common.Hash{}, err
+	}
+	if !s.b.UnprotectedAllowed() && !tx.Protected() {
+		return common.Hash{}, errors.New("only replay-protected (EIP-155) transactions allowed over RPC")
+	}
+	if err := s.b.SendPrivateTx(ctx, tx); err != nil {
+		return common.Hash{}, err
+	}
+	log.Debug("Submitted private transaction", "hash", tx.Hash().Hex(), "nonce", tx.Nonce())
+	return tx.Hash(), nil
+}
+
 // Sign calculates an ECDSA signature for:
 // keccack256("\x19Ethereum Signed Message:\n" + len(message) + message).
 //
@@ -2126,6 +2263,8 @@ type TraceConfig struct {
 	Tracer  *string
 	Timeout *string
 	Reexec  *uint64
+	// Config specific to given tracer. Only native tracers are configurable.
+	TracerConfig json.RawMessage
 }
 
 // TraceTransaction returns the structured logs created during the execution of EVM
@@ -2197,20 +2336,29 @@ func (api *PublicDebugAPI) traceTx(ctx context.Context, message evmcore.Message,
 		if rpcTimeout := api.b.RPCEVMTimeout(); rpcTimeout != 0 && rpcTimeout < timeout {
 			timeout = rpcTimeout
 		}
-		t, err := tracers.New(*config.Tracer, txctx)
-		if err != nil {
-			return nil, err
+		// Native tracers take precedence and don't count against the JS tracers limit
+		var stop func(error)
+		if native, ok, err := txtrace.NewNativeTracer(*config.Tracer, txctx, config.TracerConfig); ok {
+			if err != nil {
+				return nil, err
+			}
+			tracer, stop = native, native.Stop
+		} else {
+			t, err := tracers.New(*config.Tracer, txctx)
+			if err != nil {
+				return nil, err
+			}
+			defer t.Destroy()
+			tracer, stop = t, t.Stop
 		}
-		defer t.Destroy()
 		deadlineCtx, cancel := context.WithTimeout(ctx, timeout)
 		go func() {
 			<-deadlineCtx.Done()
 			if errors.Is(deadlineCtx.Err(), context.DeadlineExceeded) {
-				t.Stop(errors.New("execution timeout"))
+				stop(errors.New("execution timeout"))
 			}
 		}()
 		defer cancel()
-		tracer = t
 
 	default:
 		tracer = vm.NewStructLogger(config.LogConfig)
@@ -2265,32 +2413,9 @@ func (api *PublicDebugAPI) traceTx(ctx context.Context, message evmcore.Message,
 			Logs:        res,
 		}, nil
 
-	case *tracers.Tracer:
+	case txtrace.NativeTracer:
+		// JS tracers implement the native tracer interface as well
 		result, err := tracer.GetResult()
-		if err != nil && result == nil {
-			// Only for tracer called callTracer
-			if config.Tracer != nil && strings.Compare(*config.Tracer, "callTracer") == 0 {
-				if strings.Contains(err.Error(), "cannot read property 'toString' of undefined") {
-					log.Debug("error when debug with callTracer", "err", err.Error())
-					callTracer, errTracer := tracers.New(*config.Tracer, txctx)
-					if errTracer != nil {
-						return nil, errTracer
-					}
-					defer callTracer.Destroy()
-					if message == nil || vmenv == nil {
-						return nil, err
-					}
-					toAddress := message.To()
-					if toAddress == nil {
-						toAddress = &common.Address{}
-					}
-					callTracer.CaptureStart(vmenv, message.From(), *toAddress, false, message.Data(), message.Gas(), message.Value())
-					callTracer.CaptureEnd([]byte{}, message.Gas(), time.Duration(0), fmt.Errorf("execution reverted"))
-					return callTracer.GetResult()
-				}
-			}
-		}
-
 		if responseSizeLimit > 0 && len(result) > responseSizeLimit {
 			return nil, ErrMaxResponseSize
 		}
@@ -2301,6 +2426,77 @@ func (api *PublicDebugAPI) traceTx(ctx context.Context, message evmcore.Message,
 	}
 }
 
+// TraceCallConfig is the config for traceCall API. It holds extra fields
+// to override the state and the block context for tracing.
+type TraceCallConfig struct {
+	TraceConfig
+	StateOverrides *StateOverride
+	BlockOverrides *BlockOverrides
+}
+
+// TraceCall lets you trace a given eth_call. It collects the structured logs
+// created during the execution of EVM if the given transaction was added on
+// top of the provided block and returns them as a JSON object.
+func (api *PublicDebugAPI) TraceCall(ctx context.Context, args TransactionArgs, blockNrOrHash rpc.BlockNumberOrHash, config *TraceCallConfig) (interface{}, error) {
+	results, err := api.traceCalls(ctx, []TransactionArgs{args}, blockNrOrHash, config)
+	if err != nil {
+		return nil, err
+	}
+	return results[0], nil
+}
+
+// TraceCallMany traces a bundle of calls executed one after another on top of
+// the provided block. Each call sees state changes of the previous calls.
+func (api *PublicDebugAPI) TraceCallMany(ctx context.Context, args []TransactionArgs, blockNrOrHash rpc.BlockNumberOrHash, config *TraceCallConfig) ([]interface{}, error) {
+	if len(args) == 0 {
+		return nil, errors.New("empty calls bundle")
+	}
+	return api.traceCalls(ctx, args, blockNrOrHash, config)
+}
+
+// traceCalls executes the calls one after another on top of the provided block
+// and returns the results of the tracer for each call.
+func (api *PublicDebugAPI) traceCalls(ctx context.Context, args []TransactionArgs, blockNrOrHash rpc.BlockNumberOrHash, config *TraceCallConfig) ([]interface{}, error) {
+	var (
+		overrides      *StateOverride
+		blockOverrides *BlockOverrides
+		traceConfig    *TraceConfig
+	)
+	if config != nil {
+		overrides = config.StateOverrides
+		blockOverrides = config.BlockOverrides
+		traceConfig = &config.TraceConfig
+	}
+	statedb, header, err := callState(ctx, api.b, blockNrOrHash, overrides, blockOverrides)
+	if err != nil {
+		return nil, err
+	}
+	if statedb == nil {
+		return nil, errors.New("state not found")
+	}
+	defer statedb.Release()
+
+	results := make([]interface{}, len(args))
+	for i, callArgs := range args {
+		msg, err := callArgs.ToMessage(api.b.RPCGasCap(), header.BaseFee)
+		if err != nil {
+			return nil, fmt.Errorf("call %d: %w", i, err)
+		}
+		txctx := &tracers.Context{
+			BlockHash: header.Hash,
+			TxIndex:   i,
+		}
+		res, err := api.traceTx(ctx, msg, txctx, header, statedb, traceConfig)
+		if err != nil {
+			return nil, fmt.Errorf("call %d: %w", i, err)
+		}
+		results[i] = res
+		// Finalize the state so the next call sees the changes
+		statedb.Finalise()
+	}
+	return results, nil
+}
+
 // txTraceResult is the result of a single transaction trace.
 type txTraceResult struct {
 	TxHash common.Hash `json:"txHash"`           // transaction hash
@@ -0,0 +1,112 @@
+package ethapi
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var (
+	// blockCode returns the block number and the block time
+	blockCode = common.FromHex("0x436000524260205260406000f3")
+	blockAddr = common.HexToAddress("0xc4")
+)
+
+func traceCallReturn(t *testing.T, res interface{}) []byte {
+	t.Helper()
+	exec, ok := res.(*ExecutionResult)
+	if !ok {
+		t.Fatalf("unexpected trace result %T", res)
+	}
+	if exec.Failed {
+		t.Fatalf("traced call failed")
+	}
+	return common.FromHex(exec.ReturnValue)
+}
+
+func checkTraceCallWord(t *testing.T, ret []byte, i int, want uint64) {
+	t.Helper()
+	if len(ret) < (i+1)*32 {
+		t.Fatalf("return value is too short: %x", ret)
+	}
+	if have := new(big.Int).SetBytes(ret[i*32 : (i+1)*32]).Uint64(); have != want {
+		t.Fatalf("return word %d mismatch: have %d, want %d", i, have, want)
+	}
+}
+
+// Tests that each call of the bundle sees the state changes of the previous calls,
+// and the state changes and overrides don't leak into the later requests.
+func TestTraceCallManyStateChaining(t *testing.T) {
+	var (
+		api    = NewPublicDebugAPI(newCallTestBackend(t))
+		ctx    = context.Background()
+		latest = rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+		config = &TraceCallConfig{
+			StateOverrides: codeOverride(map[common.Address][]byte{counterAddr: counterCode}),
+		}
+	)
+	results, err := api.TraceCallMany(ctx, []TransactionArgs{callTo(counterAddr), callTo(counterAddr), callTo(counterAddr)}, latest, config)
+	if err != nil {
+		t.Fatalf("tracing failed: %v", err)
+	}
+	for i, res := range results {
+		checkTraceCallWord(t, traceCallReturn(t, res), 0, uint64(i+1))
+	}
+
+	// the next request starts from the original state
+	res, err := api.TraceCall(ctx, callTo(counterAddr), latest, config)
+	if err != nil {
+		t.Fatalf("tracing failed: %v", err)
+	}
+	checkTraceCallWord(t, traceCallReturn(t, res), 0, 1)
+
+	// the code override isn't kept
+	res, err = api.TraceCall(ctx, callTo(counterAddr), latest, nil)
+	if err != nil {
+		t.Fatalf("tracing failed: %v", err)
+	}
+	if ret := traceCallReturn(t, res); len(ret) != 0 {
+		t.Fatalf("overridden code is kept: returned %x", ret)
+	}
+
+	if _, err := api.TraceCallMany(ctx, nil, latest, config); err == nil {
+		t.Fatalf("empty bundle isn't refused")
+	}
+}
+
+// Tests that the block overrides are applied to all the calls of the bundle and don't leak into the later requests.
+func TestTraceCallBlockOverrides(t *testing.T) {
+	var (
+		backend = newCallTestBackend(t)
+		api     = NewPublicDebugAPI(backend)
+		ctx     = context.Background()
+		latest  = rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+		number  = hexutil.Big(*big.NewInt(100))
+		time    = hexutil.Uint64(5000)
+		code    = codeOverride(map[common.Address][]byte{blockAddr: blockCode})
+	)
+	results, err := api.TraceCallMany(ctx, []TransactionArgs{callTo(blockAddr), callTo(blockAddr)}, latest, &TraceCallConfig{
+		StateOverrides: code,
+		BlockOverrides: &BlockOverrides{Number: &number, Time: &time},
+	})
+	if err != nil {
+		t.Fatalf("tracing failed: %v", err)
+	}
+	for _, res := range results {
+		ret := traceCallReturn(t, res)
+		checkTraceCallWord(t, ret, 0, 100)
+		checkTraceCallWord(t, ret, 1, 5000)
+	}
+
+	res, err := api.TraceCall(ctx, callTo(blockAddr), latest, &TraceCallConfig{StateOverrides: code})
+	if err != nil {
+		t.Fatalf("tracing failed: %v", err)
+	}
+	ret := traceCallReturn(t, res)
+	checkTraceCallWord(t, ret, 0, 1)
+	checkTraceCallWord(t, ret, 1, uint64(backend.header.Time.Unix()))
+}
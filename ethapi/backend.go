@@ -46,6 +46,9 @@ type PeerProgress struct {
 	CurrentBlockTime inter.Timestamp
 	HighestBlock     idx.Block
 	HighestEpoch     idx.Epoch
+	SyncMode         string    // current sync mode, "dag" or "llr"
+	LlrEpoch         idx.Epoch // last epoch record filled by the LLR sync
+	LlrBlock         idx.Block // last block record filled by the LLR sync
 }
 
 // Backend interface provides the common API services (that are provided by
@@ -75,9 +78,11 @@ type Backend interface {
 	GetEVM(ctx context.Context, msg evmcore.Message, state vm.StateDB, header *evmcore.EvmHeader, vmConfig *vm.Config) (*vm.EVM, func() error, error)
 	MinGasPrice() *big.Int
 	MaxGasLimit() uint64
+	MaxBlockGasLimit() uint64
 
 	// Transaction pool API
 	SendTx(ctx context.Context, signedTx *types.Transaction) error
+	SendPrivateTx(ctx context.Context, signedTx *types.Transaction) error
 	GetTransaction(ctx context.Context, txHash common.Hash) (*types.Transaction, uint64, uint64, error)
 	GetPoolTransactions() (types.Transactions, error)
 	GetPoolTransaction(txHash common.Hash) *types.Transaction
@@ -85,7 +90,10 @@ type Backend interface {
 	Stats() (pending int, queued int)
 	TxPoolContent() (map[common.Address]types.Transactions, map[common.Address]types.Transactions)
 	TxPoolContentFrom(addr common.Address) (types.Transactions, types.Transactions)
+	PrivateTxPoolContent() map[common.Address]types.Transactions
 	SubscribeNewTxsNotify(chan<- evmcore.NewTxsNotify) notify.Subscription
+	TxPoolHistory(hash common.Hash) []evmcore.TxEvent
+	SubscribeTxPoolEvents(ch chan<- evmcore.TxEvent) notify.Subscription
 
 	ChainConfig() *params.ChainConfig
 	CurrentBlock() *evmcore.EvmBlock
@@ -96,12 +104,18 @@ type Backend interface {
 	GetHeads(ctx context.Context, epoch rpc.BlockNumber) (hash.Events, error)
 	CurrentEpoch(ctx context.Context) idx.Epoch
 	SealedEpochTiming(ctx context.Context) (start inter.Timestamp, end inter.Timestamp)
+	SubscribeNewEventNotify(ch chan<- *inter.EventPayload) notify.Subscription
 
 	// Lachesis aBFT API
 	GetEpochBlockState(ctx context.Context, epoch rpc.BlockNumber) (*iblockproc.BlockState, *iblockproc.EpochState, error)
 	GetDowntime(ctx context.Context, vid idx.ValidatorID) (idx.Block, inter.Timestamp, error)
 	GetUptime(ctx context.Context, vid idx.ValidatorID) (*big.Int, error)
 	GetOriginatedFee(ctx context.Context, vid idx.ValidatorID) (*big.Int, error)
+	SubscribeNewEpochNotify(ch chan<- *iblockproc.EpochState) notify.Subscription
+
+	// Trace index API
+	TraceIndexRange() (first idx.Block, last idx.Block, ok bool)
+	ForEachIndexedTrace(ctx context.Context, addr common.Address, callee bool, from, to idx.Block, onTrace func(block idx.Block, txIndex uint32, traceAddress []uint32) bool) error
 }
 
 func GetAPIs(apiBackend Backend) []rpc.API {
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math/big"
 
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rpc"
@@ -61,6 +62,50 @@ func (s *PublicDAGChainAPI) GetHeads(ctx context.Context, epoch rpc.BlockNumber)
 	return inter.EventIDsToHex(res), nil
 }
 
+// NewEvents creates a subscription that is triggered each time an event is connected into the DAG.
+// The notifications are limited to the events of the given creators, if any.
+func (s *PublicDAGChainAPI) NewEvents(ctx context.Context, creators *[]hexutil.Uint) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	var filter map[idx.ValidatorID]bool
+	if creators != nil && len(*creators) != 0 {
+		filter = make(map[idx.ValidatorID]bool, len(*creators))
+		for _, creator := range *creators {
+			filter[idx.ValidatorID(creator)] = true
+		}
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		events := make(chan *inter.EventPayload, 128)
+		eventsSub := s.b.SubscribeNewEventNotify(events)
+
+		for {
+			select {
+			case e := <-events:
+				if filter != nil && !filter[e.Creator()] {
+					continue
+				}
+				fields := inter.RPCMarshalEvent(e)
+				fields["txCount"] = hexutil.Uint64(len(e.Txs()))
+				_ = notifier.Notify(rpcSub.ID, fields)
+			case <-rpcSub.Err():
+				eventsSub.Unsubscribe()
+				return
+			case <-notifier.Closed():
+				eventsSub.Unsubscribe()
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
 // GetEpochStats returns epoch statistics.
 // * When epoch is -2 the statistics for latest epoch is returned.
 // * When epoch is -1 the statistics for latest sealed epoch is returned.
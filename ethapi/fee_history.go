@@ -0,0 +1,135 @@
+package ethapi
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+	lru "github.com/hashicorp/golang-lru"
+
+	"github.com/Fantom-foundation/go-opera/evmcore"
+)
+
+// feeCacheSize is the number of blocks whose fees are cached, enough for the
+// largest fee history request.
+const feeCacheSize = 2048
+
+// txGasAndReward is the gas used and the effective tip of a block transaction.
+type txGasAndReward struct {
+	gasUsed uint64
+	reward  *big.Int
+}
+
+// blockFees is the fee data of a block, independent of the requested percentiles.
+type blockFees struct {
+	baseFee      *big.Int
+	gasUsedRatio float64
+	gasUsed      uint64
+	txs          []txGasAndReward // sorted by reward ascending
+}
+
+// rewards returns the effective tips at the given percentiles of the block gas.
+func (f *blockFees) rewards(percentiles []float64) []*big.Int {
+	rewards := make([]*big.Int, len(percentiles))
+	if len(f.txs) == 0 {
+		// return an all zero row if there are no transactions to gather data from
+		for i := range rewards {
+			rewards[i] = new(big.Int)
+		}
+		return rewards
+	}
+	var txIndex int
+	sumGasUsed := f.txs[0].gasUsed
+	for i, p := range percentiles {
+		thresholdGasUsed := uint64(float64(f.gasUsed) * p / 100)
+		for sumGasUsed < thresholdGasUsed && txIndex < len(f.txs)-1 {
+			txIndex++
+			sumGasUsed += f.txs[txIndex].gasUsed
+		}
+		rewards[i] = f.txs[txIndex].reward
+	}
+	return rewards
+}
+
+// newBlockFees computes the fee data of the block from its transactions and receipts.
+// The transactions are weighted by their gas limits if the receipts are nil.
+// The block headers have no gas limit, so the gas used ratio is relative to
+// the block gas limit of the network rules.
+func newBlockFees(block *evmcore.EvmBlock, receipts types.Receipts, blockGasLimit uint64) (*blockFees, error) {
+	if receipts != nil && len(receipts) != len(block.Transactions) {
+		return nil, fmt.Errorf("receipts count mismatch at block %d: %d != %d", block.NumberU64(), len(receipts), len(block.Transactions))
+	}
+	f := &blockFees{
+		baseFee: new(big.Int),
+		txs:     make([]txGasAndReward, len(block.Transactions)),
+	}
+	if block.BaseFee != nil {
+		f.baseFee.Set(block.BaseFee)
+	}
+	for i, tx := range block.Transactions {
+		reward, _ := tx.EffectiveGasTip(block.BaseFee)
+		if reward.Sign() < 0 {
+			reward = new(big.Int)
+		}
+		gasUsed := tx.Gas()
+		if receipts != nil {
+			gasUsed = receipts[i].GasUsed
+		}
+		f.txs[i] = txGasAndReward{gasUsed: gasUsed, reward: reward}
+		f.gasUsed += gasUsed
+	}
+	sort.Slice(f.txs, func(i, j int) bool {
+		return f.txs[i].reward.Cmp(f.txs[j].reward) < 0
+	})
+	if blockGasLimit != 0 {
+		f.gasUsedRatio = float64(block.GasUsed) / float64(blockGasLimit)
+	}
+	if f.gasUsedRatio > 1 {
+		f.gasUsedRatio = 1
+	}
+	return f, nil
+}
+
+// feeHistoryCache caches the fee data of the recent requested blocks.
+type feeHistoryCache struct {
+	b     Backend
+	cache *lru.Cache
+}
+
+func newFeeHistoryCache(b Backend) *feeHistoryCache {
+	cache, _ := lru.New(feeCacheSize)
+	return &feeHistoryCache{
+		b:     b,
+		cache: cache,
+	}
+}
+
+// get returns the fee data of the block, or nil if the block doesn't exist.
+func (c *feeHistoryCache) get(ctx context.Context, number uint64) (*blockFees, error) {
+	if f, ok := c.cache.Get(number); ok {
+		return f.(*blockFees), nil
+	}
+	block, err := c.b.BlockByNumber(ctx, rpc.BlockNumber(number))
+	if block == nil || err != nil {
+		return nil, err
+	}
+	var receipts types.Receipts
+	if len(block.Transactions) != 0 {
+		receipts, err = c.b.GetReceiptsByNumber(ctx, rpc.BlockNumber(number))
+		if err != nil || len(receipts) != len(block.Transactions) {
+			// the receipts aren't indexed, weight the transactions by gas limits
+			log.Debug("Fee history without receipts", "block", number, "receipts", len(receipts), "err", err)
+			receipts = nil
+		}
+	}
+	f, err := newBlockFees(block, receipts, c.b.MaxBlockGasLimit())
+	if err != nil {
+		return nil, err
+	}
+	c.cache.Add(number, f)
+	return f, nil
+}
@@ -0,0 +1,105 @@
+package ethapi
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/Fantom-foundation/go-opera/evmcore"
+)
+
+func dynamicFeeTx(gas uint64, tip, feeCap int64) *types.Transaction {
+	return types.NewTx(&types.DynamicFeeTx{
+		Gas:       gas,
+		GasTipCap: big.NewInt(tip),
+		GasFeeCap: big.NewInt(feeCap),
+	})
+}
+
+func checkRewards(t *testing.T, have []*big.Int, want ...int64) {
+	t.Helper()
+	if len(have) != len(want) {
+		t.Fatalf("rewards count mismatch: have %d, want %d", len(have), len(want))
+	}
+	for i, reward := range have {
+		if reward.Cmp(big.NewInt(want[i])) != 0 {
+			t.Fatalf("reward %d mismatch: have %v, want %d", i, reward, want[i])
+		}
+	}
+}
+
+// Tests that the rewards are picked at the percentiles of the block gas.
+func TestBlockFeesRewards(t *testing.T) {
+	f := &blockFees{
+		gasUsed: 100,
+		txs: []txGasAndReward{
+			{gasUsed: 10, reward: big.NewInt(1)},
+			{gasUsed: 40, reward: big.NewInt(2)},
+			{gasUsed: 50, reward: big.NewInt(3)},
+		},
+	}
+	checkRewards(t, f.rewards([]float64{0, 10, 11, 50, 51, 100}), 1, 1, 2, 2, 3, 3)
+	checkRewards(t, f.rewards(nil))
+
+	// a block without transactions has zero rewards
+	empty := &blockFees{}
+	checkRewards(t, empty.rewards([]float64{0, 50, 100}), 0, 0, 0)
+}
+
+// Tests the fee data computed from the block transactions and receipts.
+func TestNewBlockFees(t *testing.T) {
+	block := &evmcore.EvmBlock{
+		EvmHeader: evmcore.EvmHeader{
+			Number:  big.NewInt(1),
+			GasUsed: 150,
+			BaseFee: big.NewInt(10),
+		},
+		Transactions: types.Transactions{
+			dynamicFeeTx(300, 5, 100), // tip 5
+			dynamicFeeTx(100, 5, 12),  // tip is capped by the fee cap: 2
+			dynamicFeeTx(200, 1, 100), // tip 1
+		},
+	}
+	receipts := types.Receipts{{GasUsed: 100}, {GasUsed: 20}, {GasUsed: 30}}
+
+	f, err := newBlockFees(block, receipts, 1000)
+	if err != nil {
+		t.Fatalf("failed to compute block fees: %v", err)
+	}
+	if f.baseFee.Cmp(block.BaseFee) != 0 {
+		t.Fatalf("base fee mismatch: have %v, want %v", f.baseFee, block.BaseFee)
+	}
+	if f.gasUsed != 150 {
+		t.Fatalf("gas used mismatch: have %d, want %d", f.gasUsed, 150)
+	}
+	// the ratio is relative to the block gas limit
+	if f.gasUsedRatio != 0.15 {
+		t.Fatalf("gas used ratio mismatch: have %f, want %f", f.gasUsedRatio, 0.15)
+	}
+	// the transactions are sorted by rewards and weighted by the used gas
+	checkRewards(t, f.rewards([]float64{0, 20, 21, 34, 100}), 1, 1, 2, 5, 5)
+
+	// without receipts, the transactions are weighted by their gas limits
+	f, err = newBlockFees(block, nil, 1000)
+	if err != nil {
+		t.Fatalf("failed to compute block fees: %v", err)
+	}
+	if f.gasUsed != 600 {
+		t.Fatalf("gas used mismatch: have %d, want %d", f.gasUsed, 600)
+	}
+	checkRewards(t, f.rewards([]float64{33, 34, 50, 51}), 1, 2, 2, 5)
+
+	// the ratio is capped
+	f, err = newBlockFees(block, receipts, 100)
+	if err != nil {
+		t.Fatalf("failed to compute block fees: %v", err)
+	}
+	if f.gasUsedRatio != 1 {
+		t.Fatalf("gas used ratio mismatch: have %f, want %f", f.gasUsedRatio, 1.0)
+	}
+
+	if _, err := newBlockFees(block, receipts[:2], 1000); err == nil {
+		t.Fatalf("receipts count mismatch isn't detected")
+	}
+}
@@ -0,0 +1,231 @@
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/Fantom-foundation/go-opera/evmcore"
+	"github.com/Fantom-foundation/go-opera/inter/state"
+	"github.com/Fantom-foundation/go-opera/opera"
+)
+
+const (
+	// maxSimulateBlocks is the maximum number of blocks simulated in one request
+	maxSimulateBlocks = 256
+	// errCodeVMError is the error code of a call failed for other reason than revert
+	errCodeVMError = -32015
+)
+
+// SimBlock is a batch of calls executed in a single simulated block
+type SimBlock struct {
+	BlockOverrides *BlockOverrides   `json:"blockOverrides"`
+	StateOverrides *StateOverride    `json:"stateOverrides"`
+	Calls          []TransactionArgs `json:"calls"`
+}
+
+// SimOpts are the inputs of eth_simulateV1
+type SimOpts struct {
+	BlockStateCalls []SimBlock `json:"blockStateCalls"`
+}
+
+// SimCallError describes the failure of a simulated call
+type SimCallError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    string `json:"data,omitempty"`
+}
+
+// SimCallResult is the result of a single simulated call
+type SimCallResult struct {
+	ReturnValue hexutil.Bytes  `json:"returnData"`
+	Logs        []*types.Log   `json:"logs"`
+	GasUsed     hexutil.Uint64 `json:"gasUsed"`
+	Status      hexutil.Uint64 `json:"status"`
+	Error       *SimCallError  `json:"error,omitempty"`
+}
+
+// SimBlockResult is the result of a single simulated block
+type SimBlockResult struct {
+	Number  *hexutil.Big     `json:"number"`
+	Time    hexutil.Uint64   `json:"timestamp"`
+	BaseFee *hexutil.Big     `json:"baseFeePerGas,omitempty"`
+	GasUsed hexutil.Uint64   `json:"gasUsed"`
+	Calls   []*SimCallResult `json:"calls"`
+}
+
+// SimulateV1 executes a series of blocks of calls on top of the given block.
+// Each call sees state changes of all the previous calls.
+// The total gas of all the calls is capped by the global RPC gas cap.
+func (s *PublicBlockChainAPI) SimulateV1(ctx context.Context, opts SimOpts, blockNrOrHash *rpc.BlockNumberOrHash) ([]*SimBlockResult, error) {
+	defer func(start time.Time) { log.Debug("Executing EVM simulation finished", "runtime", time.Since(start)) }(time.Now())
+
+	if len(opts.BlockStateCalls) == 0 {
+		return nil, errors.New("empty input")
+	}
+	if len(opts.BlockStateCalls) > maxSimulateBlocks {
+		return nil, fmt.Errorf("too many blocks (max %d)", maxSimulateBlocks)
+	}
+	if blockNrOrHash == nil {
+		latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+		blockNrOrHash = &latest
+	}
+
+	statedb, base, err := callState(ctx, s.b, *blockNrOrHash, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if statedb == nil {
+		return nil, errors.New("state not found")
+	}
+	defer statedb.Release()
+
+	// Setup context so it may be cancelled the simulation has completed
+	// or, in case of unmetered gas, setup a context with a timeout.
+	var cancel context.CancelFunc
+	timeout := s.b.RPCEVMTimeout()
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	gasCap := s.b.RPCGasCap()
+	if gasCap == 0 {
+		gasCap = math.MaxUint64 / 2
+	}
+
+	results := make([]*SimBlockResult, 0, len(opts.BlockStateCalls))
+	parent := base
+	for bi, block := range opts.BlockStateCalls {
+		header := nextSimHeader(parent)
+		header = block.BlockOverrides.Apply(header)
+		if header.Number.Cmp(parent.Number) <= 0 {
+			return nil, fmt.Errorf("block %d: block numbers must be increasing", bi)
+		}
+		if err := block.StateOverrides.Apply(statedb); err != nil {
+			return nil, fmt.Errorf("block %d: %w", bi, err)
+		}
+
+		blockResult := &SimBlockResult{
+			Number: (*hexutil.Big)(new(big.Int).Set(header.Number)),
+			Time:   hexutil.Uint64(header.Time.Unix()),
+			Calls:  make([]*SimCallResult, 0, len(block.Calls)),
+		}
+		if header.BaseFee != nil {
+			blockResult.BaseFee = (*hexutil.Big)(new(big.Int).Set(header.BaseFee))
+		}
+		for ci, args := range block.Calls {
+			if gasCap == 0 {
+				return nil, fmt.Errorf("block %d, call %d: gas cap exhausted", bi, ci)
+			}
+			res, err := s.simulateCall(ctx, statedb, header, args, len(blockResult.Calls), gasCap)
+			if err != nil {
+				return nil, fmt.Errorf("block %d, call %d: %w", bi, ci, err)
+			}
+			gasCap -= uint64(res.GasUsed)
+			blockResult.GasUsed += res.GasUsed
+			blockResult.Calls = append(blockResult.Calls, res)
+		}
+		header.GasUsed = uint64(blockResult.GasUsed)
+		results = append(results, blockResult)
+		parent = header
+	}
+	return results, nil
+}
+
+// nextSimHeader creates the header of a simulated block following the parent
+func nextSimHeader(parent *evmcore.EvmHeader) *evmcore.EvmHeader {
+	header := *parent
+	header.ParentHash = parent.Hash
+	header.Hash = common.Hash{}
+	header.Number = new(big.Int).Add(parent.Number, common.Big1)
+	header.Time = parent.Time + 1e9 // +1 second
+	header.GasUsed = 0
+	return &header
+}
+
+// simulateCall executes the call on top of the state and finalises the state afterwards
+func (s *PublicBlockChainAPI) simulateCall(ctx context.Context, statedb state.StateDB, header *evmcore.EvmHeader, args TransactionArgs, index int, gasCap uint64) (*SimCallResult, error) {
+	msg, err := args.ToMessage(gasCap, header.BaseFee)
+	if err != nil {
+		return nil, err
+	}
+	vmConfig := opera.DefaultVMConfig
+	vmConfig.NoBaseFee = true
+	evm, vmError, err := s.b.GetEVM(ctx, msg, statedb, header, &vmConfig)
+	if err != nil {
+		return nil, err
+	}
+	// Wait for the context to be done and cancel the evm. Even if the
+	// EVM has finished, cancelling may be done (repeatedly)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			evm.Cancel()
+		case <-done:
+		}
+	}()
+
+	// use a fake tx hash to collect logs of the call
+	txHash := common.BigToHash(new(big.Int).SetUint64(header.Number.Uint64()<<32 | uint64(index)))
+	statedb.Prepare(txHash, index)
+
+	result, err := evmcore.ApplyMessage(evm, msg, new(evmcore.GasPool).AddGas(math.MaxUint64))
+	if err := vmError(); err != nil {
+		return nil, err
+	}
+	if err := statedb.Error(); err != nil {
+		return nil, fmt.Errorf("StateDB error: %w", err)
+	}
+	if evm.Cancelled() {
+		return nil, fmt.Errorf("execution aborted (timeout = %v)", s.b.RPCEVMTimeout())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("err: %w (supplied gas %d)", err, msg.Gas())
+	}
+
+	logs := statedb.GetLogs(txHash, header.Hash)
+	for _, l := range logs {
+		l.BlockNumber = header.Number.Uint64()
+		l.TxHash = common.Hash{}
+	}
+	statedb.Finalise()
+
+	callResult := &SimCallResult{
+		ReturnValue: result.Return(),
+		Logs:        logs,
+		GasUsed:     hexutil.Uint64(result.UsedGas),
+		Status:      hexutil.Uint64(types.ReceiptStatusSuccessful),
+	}
+	if result.Failed() {
+		callResult.Status = hexutil.Uint64(types.ReceiptStatusFailed)
+		if len(result.Revert()) > 0 {
+			revertErr := newRevertError(result)
+			callResult.ReturnValue = result.Revert()
+			callResult.Error = &SimCallError{
+				Code:    revertErr.ErrorCode(),
+				Message: revertErr.Error(),
+				Data:    revertErr.reason,
+			}
+		} else {
+			callResult.Error = &SimCallError{
+				Code:    errCodeVMError,
+				Message: result.Err.Error(),
+			}
+		}
+	}
+	return callResult, nil
+}
@@ -0,0 +1,259 @@
+package ethapi
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	carmen "github.com/Fantom-foundation/Carmen/go/state"
+	"github.com/Fantom-foundation/lachesis-base/kvdb/memorydb"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/Fantom-foundation/go-opera/evmcore"
+	"github.com/Fantom-foundation/go-opera/gossip/evmstore"
+	"github.com/Fantom-foundation/go-opera/inter"
+	"github.com/Fantom-foundation/go-opera/inter/state"
+	"github.com/Fantom-foundation/go-opera/opera"
+)
+
+var (
+	// counterCode increments the storage slot 0 and returns its new value
+	counterCode = common.FromHex("0x6000546001018060005560005260206000f3")
+	// revertCode reverts with the 32 bytes word 0x2a
+	revertCode = common.FromHex("0x602a60005260206000fd")
+	// invalidCode fails on the invalid opcode
+	invalidCode = common.FromHex("0xfe")
+	// loopCode never stops
+	loopCode = common.FromHex("0x5b600056")
+
+	counterAddr = common.HexToAddress("0xc0")
+	revertAddr  = common.HexToAddress("0xc1")
+	invalidAddr = common.HexToAddress("0xc2")
+	loopAddr    = common.HexToAddress("0xc3")
+)
+
+// callTestBackend executes the calls on top of an empty state of the block 1.
+// The methods which aren't used by the calls aren't implemented.
+type callTestBackend struct {
+	Backend
+	store   *evmstore.Store
+	header  *evmcore.EvmHeader
+	gasCap  uint64
+	timeout time.Duration
+}
+
+func newCallTestBackend(t *testing.T) *callTestBackend {
+	cfg := evmstore.LiteStoreConfig()
+	cfg.StateDb.Directory = filepath.Join(t.TempDir(), "carmen")
+	cfg.StateDb.Archive = carmen.NoArchive
+	store := evmstore.NewStore(memorydb.New(), cfg)
+	if err := store.Open(); err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = store.Close()
+	})
+	return &callTestBackend{
+		store: store,
+		header: &evmcore.EvmHeader{
+			Number:   big.NewInt(1),
+			Hash:     common.Hash{1},
+			Time:     inter.FromUnix(1000),
+			GasLimit: 1_000_000_000,
+			BaseFee:  big.NewInt(0),
+		},
+		gasCap: 50_000_000,
+	}
+}
+
+func (b *callTestBackend) RPCGasCap() uint64 {
+	return b.gasCap
+}
+
+func (b *callTestBackend) RPCEVMTimeout() time.Duration {
+	return b.timeout
+}
+
+func (b *callTestBackend) ChainConfig() *params.ChainConfig {
+	rules := opera.FakeNetRules()
+	return rules.EvmChainConfig([]opera.UpgradeHeight{{Upgrades: rules.Upgrades, Height: 0}})
+}
+
+func (b *callTestBackend) GetHeader(common.Hash, uint64) *evmcore.EvmHeader {
+	return nil
+}
+
+func (b *callTestBackend) StateAndHeaderByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (state.StateDB, *evmcore.EvmHeader, error) {
+	statedb, err := b.store.GetTxPoolStateDB()
+	if err != nil {
+		return nil, nil, err
+	}
+	return statedb, b.header, nil
+}
+
+func (b *callTestBackend) GetEVM(ctx context.Context, msg evmcore.Message, state vm.StateDB, header *evmcore.EvmHeader, vmConfig *vm.Config) (*vm.EVM, func() error, error) {
+	txContext := evmcore.NewEVMTxContext(msg)
+	context := evmcore.NewEVMBlockContext(header, b, nil)
+	return vm.NewEVM(context, txContext, state, b.ChainConfig(), *vmConfig), func() error { return nil }, nil
+}
+
+func codeOverride(codes map[common.Address][]byte) *StateOverride {
+	overrides := make(StateOverride, len(codes))
+	for addr, code := range codes {
+		code := hexutil.Bytes(code)
+		overrides[addr] = OverrideAccount{Code: &code}
+	}
+	return &overrides
+}
+
+func callTo(addr common.Address) TransactionArgs {
+	return TransactionArgs{To: &addr}
+}
+
+func checkCounter(t *testing.T, res *SimCallResult, want uint64) {
+	t.Helper()
+	if res.Status != hexutil.Uint64(types.ReceiptStatusSuccessful) || res.Error != nil {
+		t.Fatalf("call failed: %+v", res.Error)
+	}
+	if have := new(big.Int).SetBytes(res.ReturnValue).Uint64(); have != want {
+		t.Fatalf("counter mismatch: have %d, want %d", have, want)
+	}
+}
+
+// Tests that each simulated call sees the state changes of the previous calls and blocks.
+func TestSimulateV1StateChaining(t *testing.T) {
+	api := NewPublicBlockChainAPI(newCallTestBackend(t))
+	results, err := api.SimulateV1(context.Background(), SimOpts{BlockStateCalls: []SimBlock{
+		{
+			StateOverrides: codeOverride(map[common.Address][]byte{counterAddr: counterCode}),
+			Calls:          []TransactionArgs{callTo(counterAddr), callTo(counterAddr)},
+		},
+		{
+			BlockOverrides: &BlockOverrides{Number: (*hexutil.Big)(big.NewInt(10))},
+			Calls:          []TransactionArgs{callTo(counterAddr)},
+		},
+	}}, nil)
+	if err != nil {
+		t.Fatalf("simulation failed: %v", err)
+	}
+	if len(results) != 2 || len(results[0].Calls) != 2 || len(results[1].Calls) != 1 {
+		t.Fatalf("unexpected results shape")
+	}
+	checkCounter(t, results[0].Calls[0], 1)
+	checkCounter(t, results[0].Calls[1], 2)
+	checkCounter(t, results[1].Calls[0], 3)
+
+	if results[0].Number.ToInt().Uint64() != 2 || results[1].Number.ToInt().Uint64() != 10 {
+		t.Fatalf("block numbers mismatch: have %v, %v", results[0].Number, results[1].Number)
+	}
+	if uint64(results[1].Time) != uint64(results[0].Time)+1 {
+		t.Fatalf("block time isn't increased: have %d, parent %d", results[1].Time, results[0].Time)
+	}
+	if results[0].GasUsed != results[0].Calls[0].GasUsed+results[0].Calls[1].GasUsed {
+		t.Fatalf("block gas used mismatch: have %d", results[0].GasUsed)
+	}
+
+	// the block numbers must be increasing
+	_, err = api.SimulateV1(context.Background(), SimOpts{BlockStateCalls: []SimBlock{
+		{BlockOverrides: &BlockOverrides{Number: (*hexutil.Big)(big.NewInt(1))}},
+	}}, nil)
+	if err == nil {
+		t.Fatalf("decreasing block number isn't refused")
+	}
+}
+
+// Tests that the reverted and failed calls are reported in their results and don't stop the simulation.
+func TestSimulateV1CallErrors(t *testing.T) {
+	invalidGas := hexutil.Uint64(100_000)
+	api := NewPublicBlockChainAPI(newCallTestBackend(t))
+	results, err := api.SimulateV1(context.Background(), SimOpts{BlockStateCalls: []SimBlock{{
+		StateOverrides: codeOverride(map[common.Address][]byte{
+			counterAddr: counterCode,
+			revertAddr:  revertCode,
+			invalidAddr: invalidCode,
+		}),
+		// the failed call consumes all its gas, which is limited not to exhaust the gas cap
+		Calls: []TransactionArgs{callTo(revertAddr), {To: &invalidAddr, Gas: &invalidGas}, callTo(counterAddr)},
+	}}}, nil)
+	if err != nil {
+		t.Fatalf("simulation failed: %v", err)
+	}
+	calls := results[0].Calls
+
+	reverted := calls[0]
+	if reverted.Status != hexutil.Uint64(types.ReceiptStatusFailed) || reverted.Error == nil {
+		t.Fatalf("reverted call isn't failed: %+v", reverted)
+	}
+	if reverted.Error.Code != 3 || new(big.Int).SetBytes(reverted.ReturnValue).Uint64() != 0x2a {
+		t.Fatalf("revert mismatch: code %d, data %x", reverted.Error.Code, reverted.ReturnValue)
+	}
+
+	invalid := calls[1]
+	if invalid.Status != hexutil.Uint64(types.ReceiptStatusFailed) || invalid.Error == nil {
+		t.Fatalf("invalid call isn't failed: %+v", invalid)
+	}
+	if invalid.Error.Code != errCodeVMError || !strings.Contains(invalid.Error.Message, "invalid opcode") {
+		t.Fatalf("error mismatch: %+v", invalid.Error)
+	}
+
+	checkCounter(t, calls[2], 1)
+}
+
+// Tests that the global gas cap is a budget of all the simulated calls.
+func TestSimulateV1GasCap(t *testing.T) {
+	backend := newCallTestBackend(t)
+	api := NewPublicBlockChainAPI(backend)
+	opts := SimOpts{BlockStateCalls: []SimBlock{
+		{
+			StateOverrides: codeOverride(map[common.Address][]byte{counterAddr: counterCode}),
+			Calls:          []TransactionArgs{callTo(counterAddr), callTo(counterAddr)},
+		},
+		{
+			Calls: []TransactionArgs{callTo(counterAddr), callTo(counterAddr)},
+		},
+	}}
+
+	results, err := api.SimulateV1(context.Background(), opts, nil)
+	if err != nil {
+		t.Fatalf("simulation failed: %v", err)
+	}
+	var gasUsed uint64
+	for _, block := range results {
+		gasUsed += uint64(block.GasUsed)
+	}
+
+	// the gas left for the last call is lower than its intrinsic gas
+	backend.gasCap = gasUsed - uint64(results[1].Calls[1].GasUsed) + params.TxGas - 1
+	_, err = api.SimulateV1(context.Background(), opts, nil)
+	if err == nil || !strings.Contains(err.Error(), "block 1, call 1") {
+		t.Fatalf("gas cap isn't exhausted by the last call: %v", err)
+	}
+}
+
+// Tests that the simulation is aborted on the RPC EVM timeout.
+func TestSimulateV1Timeout(t *testing.T) {
+	backend := newCallTestBackend(t)
+	backend.gasCap = 0 // unlimited
+	backend.timeout = 50 * time.Millisecond
+	api := NewPublicBlockChainAPI(backend)
+
+	start := time.Now()
+	_, err := api.SimulateV1(context.Background(), SimOpts{BlockStateCalls: []SimBlock{{
+		StateOverrides: codeOverride(map[common.Address][]byte{loopAddr: loopCode}),
+		Calls:          []TransactionArgs{callTo(loopAddr)},
+	}}}, nil)
+	if err == nil || !strings.Contains(err.Error(), "execution aborted") {
+		t.Fatalf("simulation isn't aborted: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Fatalf("simulation is aborted too late: %v", elapsed)
+	}
+}
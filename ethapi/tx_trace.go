@@ -162,45 +162,8 @@ func (s *PublicTxTraceAPI) replayBlock(ctx context.Context, block *evmcore.EvmBl
 			}
 
 		} else {
-
-			// Replay transaction without tracing to prepare state for next transaction
-			log.Debug("Replaying transaction without trace", "txHash", tx.Hash().String())
-			msg, err := evmcore.TxAsMessage(tx, signer, block.BaseFee)
-			if err != nil {
-				return nil, fmt.Errorf("cannot get message from transaction %s, error %s", tx.Hash().String(), err)
-			}
-
-			state.Prepare(tx.Hash(), i)
-			vmConfig := opera.DefaultVMConfig
-			vmConfig.NoBaseFee = true
-			vmConfig.Debug = false
-			vmConfig.Tracer = nil
-
-			vmenv, _, err := s.b.GetEVM(ctx, msg, state, block.Header(), &vmConfig)
-			if err != nil {
-				return nil, fmt.Errorf("cannot initialize vm for transaction %s, error: %s", tx.Hash().String(), err.Error())
-			}
-
-			res, err := evmcore.ApplyMessage(vmenv, msg, new(evmcore.GasPool).AddGas(msg.Gas()))
-			failed := false
-			if err != nil {
-				failed = true
-				log.Error("Cannot replay transaction", "txHash", tx.Hash().String(), "err", err.Error())
-			}
-			if err := state.Error(); err != nil {
-				return nil, fmt.Errorf("StateDB error when replaying tx %s: %w", tx.Hash().String(), err)
-			}
-
-			if res != nil && res.Err != nil {
-				failed = true
-				log.Debug("Error replaying transaction", "txHash", tx.Hash().String(), "err", res.Err.Error())
-			}
-
-			state.Finalise()
-
-			// Check correct replay status according to receipt data
-			if (failed && receipts[i].Status == 1) || (!failed && receipts[i].Status == 0) {
-				return nil, fmt.Errorf("invalid transaction replay state at %s", tx.Hash().String())
+			if err := s.replayTxWithoutTrace(ctx, block, state, signer, i, tx, receipts[i]); err != nil {
+				return nil, err
 			}
 		}
 	}
@@ -217,6 +180,58 @@ func (s *PublicTxTraceAPI) replayBlock(ctx context.Context, block *evmcore.EvmBl
 	return &callTrace.Actions, nil
 }
 
+// replayTxWithoutTrace replays transaction without tracing to prepare state for next transaction
+func (s *PublicTxTraceAPI) replayTxWithoutTrace(ctx context.Context, block *evmcore.EvmBlock, state state.StateDB, signer types.Signer, index int, tx *types.Transaction, receipt *types.Receipt) error {
+	log.Debug("Replaying transaction without trace", "txHash", tx.Hash().String())
+	msg, err := evmcore.TxAsMessage(tx, signer, block.BaseFee)
+	if err != nil {
+		return fmt.Errorf("cannot get message from transaction %s, error %s", tx.Hash().String(), err)
+	}
+
+	state.Prepare(tx.Hash(), index)
+	vmConfig := opera.DefaultVMConfig
+	vmConfig.NoBaseFee = true
+	vmConfig.Debug = false
+	vmConfig.Tracer = nil
+
+	vmenv, _, err := s.b.GetEVM(ctx, msg, state, block.Header(), &vmConfig)
+	if err != nil {
+		return fmt.Errorf("cannot initialize vm for transaction %s, error: %s", tx.Hash().String(), err.Error())
+	}
+
+	res, err := evmcore.ApplyMessage(vmenv, msg, new(evmcore.GasPool).AddGas(msg.Gas()))
+	failed := false
+	if err != nil {
+		failed = true
+		log.Error("Cannot replay transaction", "txHash", tx.Hash().String(), "err", err.Error())
+	}
+	if err := state.Error(); err != nil {
+		return fmt.Errorf("StateDB error when replaying tx %s: %w", tx.Hash().String(), err)
+	}
+
+	if res != nil && res.Err != nil {
+		failed = true
+		log.Debug("Error replaying transaction", "txHash", tx.Hash().String(), "err", res.Err.Error())
+	}
+
+	state.Finalise()
+
+	// Check correct replay status according to receipt data
+	if (failed && receipt.Status == 1) || (!failed && receipt.Status == 0) {
+		return fmt.Errorf("invalid transaction replay state at %s", tx.Hash().String())
+	}
+	return nil
+}
+
+// traceTimeout returns time limit for a single transaction replay
+func (s *PublicTxTraceAPI) traceTimeout() time.Duration {
+	var timeout time.Duration = 5 * time.Second
+	if s.b.RPCEVMTimeout() > 0 {
+		timeout = s.b.RPCEVMTimeout()
+	}
+	return timeout
+}
+
 // traceTx trace transaction with EVM replay and return processed result
 func (s *PublicTxTraceAPI) traceTx(
 	ctx context.Context, b Backend, header *evmcore.EvmHeader, msg types.Message,
@@ -232,12 +247,8 @@ func (s *PublicTxTraceAPI) traceTx(
 
 	// Setup context so it may be cancelled the call has completed
 	// or, in case of unmetered gas, setup a context with a timeout.
-	var timeout time.Duration = 5 * time.Second
-	if s.b.RPCEVMTimeout() > 0 {
-		timeout = s.b.RPCEVMTimeout()
-	}
 	var cancel context.CancelFunc
-	ctx, cancel = context.WithTimeout(ctx, timeout)
+	ctx, cancel = context.WithTimeout(ctx, s.traceTimeout())
 
 	// Make sure the context is cancelled when the call has completed
 	// this makes sure resources are cleaned up.
@@ -336,6 +347,11 @@ func (s *PublicTxTraceAPI) Filter(ctx context.Context, args FilterArgs) (json.Ra
 		log.Debug("Executing trace_filter call finished", data...)
 	}(time.Now())
 
+	// use trace index when it covers the requested blocks range
+	if res, ok, err := filterIndexed(ctx, s, args); ok {
+		return res, err
+	}
+
 	if args.Count == 0 && args.After == 0 {
 		// count and order of traces doesn't matter so filter blocks in parallel
 		return filterBlocksInParallel(ctx, s, args)
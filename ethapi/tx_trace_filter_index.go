@@ -0,0 +1,189 @@
+package ethapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/Fantom-foundation/go-opera/txtrace"
+)
+
+// indexedTx identifies a transaction found in the trace index
+type indexedTx struct {
+	block   idx.Block
+	txIndex uint32
+}
+
+// filterIndexed answers trace_filter using the trace index.
+// Only transactions found in the index are replayed instead of the whole blocks range.
+// Returns false if the index can't be used for the request.
+func filterIndexed(ctx context.Context, s *PublicTxTraceAPI, args FilterArgs) (json.RawMessage, bool, error) {
+	fromBlock, toBlock, fromAddresses, toAddresses := parseFilterArguments(s.b, args)
+	if len(fromAddresses) == 0 && len(toAddresses) == 0 {
+		return nil, false, nil
+	}
+	first, last, ok := s.b.TraceIndexRange()
+	if !ok || fromBlock < 0 || toBlock < fromBlock || idx.Block(fromBlock) < first || idx.Block(toBlock) > last {
+		return nil, false, nil
+	}
+
+	txs, err := findIndexedTxs(ctx, s.b, idx.Block(fromBlock), idx.Block(toBlock), fromAddresses, toAddresses)
+	if err != nil {
+		return nil, true, err
+	}
+
+	var traceAdded, traceCount uint
+
+	// resultBuffer is buffer for collecting result traces
+	resultBuffer, err := NewJsonResultBuffer()
+	if err != nil {
+		return nil, true, err
+	}
+
+	for i := 0; i < len(txs); {
+		// group transactions of the same block
+		j := i + 1
+		for j < len(txs) && txs[j].block == txs[i].block {
+			j++
+		}
+		traces, err := getIndexedTracesForBlock(ctx, s, txs[i:j], fromAddresses, toAddresses)
+		if err != nil {
+			return nil, true, err
+		}
+		i = j
+
+		// check if traces have to be added
+		for _, trace := range traces {
+
+			if traceCount >= args.After {
+				err := resultBuffer.AddObject(&trace)
+				if err != nil {
+					return nil, true, err
+				}
+				traceAdded++
+			}
+			if args.Count != 0 && traceAdded >= args.Count {
+				res, err := resultBuffer.GetResult()
+				return res, true, err
+			}
+			traceCount++
+		}
+
+		// when context ended return error
+		if ctx.Err() != nil {
+			return nil, true, ctx.Err()
+		}
+	}
+	res, err := resultBuffer.GetResult()
+	return res, true, err
+}
+
+// findIndexedTxs returns sorted list of transactions containing calls of the requested addresses
+func findIndexedTxs(ctx context.Context, b Backend, from, to idx.Block, fromAddresses, toAddresses map[common.Address]struct{}) ([]indexedTx, error) {
+	collect := func(addresses map[common.Address]struct{}, callee bool) (map[indexedTx]struct{}, error) {
+		found := make(map[indexedTx]struct{})
+		for addr := range addresses {
+			err := b.ForEachIndexedTrace(ctx, addr, callee, from, to, func(block idx.Block, txIndex uint32, _ []uint32) bool {
+				found[indexedTx{block, txIndex}] = struct{}{}
+				return true
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+		return found, nil
+	}
+
+	var found map[indexedTx]struct{}
+	if len(fromAddresses) > 0 {
+		fromTxs, err := collect(fromAddresses, false)
+		if err != nil {
+			return nil, err
+		}
+		found = fromTxs
+	}
+	if len(toAddresses) > 0 {
+		toTxs, err := collect(toAddresses, true)
+		if err != nil {
+			return nil, err
+		}
+		if found == nil {
+			found = toTxs
+		} else {
+			// both caller and callee have to match
+			for tx := range found {
+				if _, ok := toTxs[tx]; !ok {
+					delete(found, tx)
+				}
+			}
+		}
+	}
+
+	txs := make([]indexedTx, 0, len(found))
+	for tx := range found {
+		txs = append(txs, tx)
+	}
+	sort.Slice(txs, func(i, j int) bool {
+		if txs[i].block != txs[j].block {
+			return txs[i].block < txs[j].block
+		}
+		return txs[i].txIndex < txs[j].txIndex
+	})
+	return txs, nil
+}
+
+// getIndexedTracesForBlock replays given transactions of a single block and filters out useable traces
+func getIndexedTracesForBlock(
+	ctx context.Context,
+	s *PublicTxTraceAPI,
+	txs []indexedTx,
+	fromAddresses map[common.Address]struct{},
+	toAddresses map[common.Address]struct{},
+) (
+	[]txtrace.ActionTrace,
+	error,
+) {
+	blockNumber := rpc.BlockNumber(txs[0].block)
+	block, err := s.b.BlockByNumber(ctx, blockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get block from db %v, error:%v", blockNumber.Int64(), err.Error())
+	}
+	if block == nil {
+		return nil, fmt.Errorf("cannot get block from db %v", blockNumber.Int64())
+	}
+
+	positions := make(map[uint64]struct{}, len(txs))
+	for _, tx := range txs {
+		positions[uint64(tx.txIndex)] = struct{}{}
+	}
+
+	// replay only the transaction if it is the only one, otherwise the whole block
+	var txHash *common.Hash
+	if len(txs) == 1 {
+		if int(txs[0].txIndex) >= len(block.Transactions) {
+			return nil, fmt.Errorf("transaction %d not found in block %v", txs[0].txIndex, blockNumber.Int64())
+		}
+		hash := block.Transactions[txs[0].txIndex].Hash()
+		txHash = &hash
+	}
+	traces, err := s.replayBlock(ctx, block, txHash, nil)
+	if err != nil || traces == nil {
+		return nil, err
+	}
+
+	resultTraces := make([]txtrace.ActionTrace, 0)
+	for _, trace := range *traces {
+		if _, ok := positions[trace.TransactionPosition]; !ok {
+			continue
+		}
+		if trace.Action != nil && containsAddress(trace.Action.From, trace.Action.To, fromAddresses, toAddresses) {
+			resultTraces = append(resultTraces, trace)
+		}
+	}
+	return resultTraces, nil
+}
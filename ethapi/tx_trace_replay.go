@@ -0,0 +1,356 @@
+package ethapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/Fantom-foundation/go-opera/evmcore"
+	"github.com/Fantom-foundation/go-opera/inter/state"
+	"github.com/Fantom-foundation/go-opera/opera"
+	"github.com/Fantom-foundation/go-opera/txtrace"
+	"github.com/Fantom-foundation/go-opera/utils/signers/gsignercache"
+)
+
+const (
+	traceTypeTrace     = "trace"
+	traceTypeStateDiff = "stateDiff"
+	traceTypeVmTrace   = "vmTrace"
+)
+
+// traceOptions holds kinds of traces requested by trace_replay* and trace_call* methods
+type traceOptions struct {
+	trace     bool
+	stateDiff bool
+	vmTrace   bool
+}
+
+// parseTraceTypes parses trace types from the rpc call arguments
+func parseTraceTypes(names []string) (traceOptions, error) {
+	var res traceOptions
+	for _, t := range names {
+		switch t {
+		case traceTypeTrace:
+			res.trace = true
+		case traceTypeStateDiff:
+			res.stateDiff = true
+		case traceTypeVmTrace:
+			res.vmTrace = true
+		default:
+			return res, fmt.Errorf("unrecognized trace type: %s", t)
+		}
+	}
+	return res, nil
+}
+
+// TraceResults holds the results of the transaction replay in the parity format
+type TraceResults struct {
+	Output          hexutil.Bytes         `json:"output"`
+	StateDiff       txtrace.StateDiff     `json:"stateDiff"`
+	Trace           []txtrace.ReplayTrace `json:"trace"`
+	VmTrace         *txtrace.VmTrace      `json:"vmTrace"`
+	TransactionHash *common.Hash          `json:"transactionHash,omitempty"`
+}
+
+// TraceCallParam is a single call of the trace_callMany method,
+// which is encoded as [callArgs, traceTypes] array
+type TraceCallParam struct {
+	Args       TransactionArgs
+	TraceTypes []string
+}
+
+// UnmarshalJSON decodes the call from [callArgs, traceTypes] array
+func (p *TraceCallParam) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if len(raw) != 2 {
+		return fmt.Errorf("expected [callArgs, traceTypes], got %d items", len(raw))
+	}
+	if err := json.Unmarshal(raw[0], &p.Args); err != nil {
+		return err
+	}
+	return json.Unmarshal(raw[1], &p.TraceTypes)
+}
+
+// ReplayTransaction - trace_replayTransaction function replays transaction
+// and returns requested traces
+func (s *PublicTxTraceAPI) ReplayTransaction(ctx context.Context, hash common.Hash, traceTypes []string) (*TraceResults, error) {
+	defer func(start time.Time) {
+		log.Debug("Executing trace_replayTransaction call finished", "txHash", hash.String(), "runtime", time.Since(start))
+	}(time.Now())
+
+	opts, err := parseTraceTypes(traceTypes)
+	if err != nil {
+		return nil, err
+	}
+	tx, blockNumber, _, err := s.b.GetTransaction(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if tx == nil {
+		return nil, fmt.Errorf("transaction %s not found", hash.Hex())
+	}
+	block, err := s.b.BlockByNumber(ctx, rpc.BlockNumber(blockNumber))
+	if err != nil {
+		return nil, fmt.Errorf("cannot get block from db %v, error:%v", blockNumber, err.Error())
+	}
+
+	results, err := s.replayBlockTransactions(ctx, block, &hash, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("transaction %s not found in block %v", hash.Hex(), blockNumber)
+	}
+	// transaction hash is not part of the single transaction result
+	results[0].TransactionHash = nil
+	return results[0], nil
+}
+
+// ReplayBlockTransactions - trace_replayBlockTransactions function replays
+// all transactions in the block and returns requested traces
+func (s *PublicTxTraceAPI) ReplayBlockTransactions(ctx context.Context, numberOrHash rpc.BlockNumberOrHash, traceTypes []string) ([]*TraceResults, error) {
+	opts, err := parseTraceTypes(traceTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	var block *evmcore.EvmBlock
+	if hash, ok := numberOrHash.Hash(); ok {
+		block, err = s.b.BlockByHash(ctx, hash)
+	} else {
+		blockNumber, _ := numberOrHash.Number()
+		if blockNumber == rpc.PendingBlockNumber {
+			return nil, fmt.Errorf("cannot trace pending block")
+		}
+		block, err = s.b.BlockByNumber(ctx, blockNumber)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot get block from db, error: %v", err.Error())
+	}
+	if block == nil {
+		return nil, fmt.Errorf("block not found")
+	}
+
+	defer func(start time.Time) {
+		log.Debug("Executing trace_replayBlockTransactions call finished", "block", block.NumberU64(), "runtime", time.Since(start))
+	}(time.Now())
+
+	return s.replayBlockTransactions(ctx, block, nil, opts)
+}
+
+// Call - trace_call function executes the call on top of the given block state
+// and returns requested traces
+func (s *PublicTxTraceAPI) Call(ctx context.Context, args TransactionArgs, traceTypes []string, blockNrOrHash *rpc.BlockNumberOrHash) (*TraceResults, error) {
+	results, err := s.CallMany(ctx, []TraceCallParam{{Args: args, TraceTypes: traceTypes}}, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	return results[0], nil
+}
+
+// CallMany - trace_callMany function executes a sequence of calls on top of the given
+// block state, where each call is executed on the state modified by the previous calls
+func (s *PublicTxTraceAPI) CallMany(ctx context.Context, calls []TraceCallParam, blockNrOrHash *rpc.BlockNumberOrHash) ([]*TraceResults, error) {
+	defer func(start time.Time) {
+		log.Debug("Executing trace_callMany call finished", "calls", len(calls), "runtime", time.Since(start))
+	}(time.Now())
+
+	bNrOrHash := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+	if blockNrOrHash != nil {
+		bNrOrHash = *blockNrOrHash
+	}
+	if len(calls) == 0 {
+		return nil, errors.New("no calls specified")
+	}
+
+	statedb, header, err := s.b.StateAndHeaderByNumberOrHash(ctx, bNrOrHash)
+	if statedb == nil || err != nil {
+		return nil, err
+	}
+	defer statedb.Release()
+
+	results := make([]*TraceResults, 0, len(calls))
+	for i, call := range calls {
+		opts, err := parseTraceTypes(call.TraceTypes)
+		if err != nil {
+			return nil, err
+		}
+		msg, err := call.Args.ToMessage(s.b.RPCGasCap(), header.BaseFee)
+		if err != nil {
+			return nil, err
+		}
+		var actionTracer *txtrace.TraceStructLogger
+		if opts.trace {
+			actionTracer = txtrace.NewCallTraceStructLogger(header, msg, uint(i))
+		}
+		res, _, err := s.replayTx(ctx, header, msg, statedb, common.Hash{}, i, actionTracer, opts)
+		if err != nil {
+			return nil, fmt.Errorf("cannot trace call %d: %w", i, err)
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+// replayBlockTransactions replays transactions of the block and collects requested traces
+//
+// txHash
+//   - if is nil, all transaction traces in the block are collected
+//   - is value, then only trace for that transaction is returned
+func (s *PublicTxTraceAPI) replayBlockTransactions(ctx context.Context, block *evmcore.EvmBlock, txHash *common.Hash, opts traceOptions) ([]*TraceResults, error) {
+	if block == nil {
+		return nil, fmt.Errorf("invalid block for tracing")
+	}
+	if block.NumberU64() == 0 {
+		return nil, fmt.Errorf("genesis block is not traceable")
+	}
+
+	blockNumber := block.Number.Int64()
+	parentBlockNr := rpc.BlockNumber(blockNumber - 1)
+	signer := gsignercache.Wrap(types.MakeSigner(s.b.ChainConfig(), block.Number))
+
+	statedb, _, err := s.b.StateAndHeaderByNumberOrHash(ctx, rpc.BlockNumberOrHash{BlockNumber: &parentBlockNr})
+	if err != nil {
+		return nil, fmt.Errorf("cannot get state for block %v, error: %v", block.NumberU64(), err.Error())
+	}
+	defer statedb.Release()
+
+	receipts, err := s.b.GetReceiptsByNumber(ctx, rpc.BlockNumber(blockNumber))
+	if err != nil {
+		return nil, fmt.Errorf("cannot get receipts for block %v, error: %v", block.NumberU64(), err.Error())
+	}
+
+	results := make([]*TraceResults, 0, len(block.Transactions))
+	for i, tx := range block.Transactions {
+		if len(receipts) <= i || receipts[i] == nil {
+			return nil, fmt.Errorf("no receipt found for transaction %s", tx.Hash().String())
+		}
+
+		if txHash != nil && *txHash != tx.Hash() {
+			if err := s.replayTxWithoutTrace(ctx, block, statedb, signer, i, tx, receipts[i]); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		msg, err := evmcore.TxAsMessage(tx, signer, block.BaseFee)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get message from transaction %s, error %s", tx.Hash().String(), err)
+		}
+		var actionTracer *txtrace.TraceStructLogger
+		if opts.trace {
+			actionTracer = txtrace.NewTraceStructLogger(block, tx, msg, uint(i), receipts[i].GasUsed)
+		}
+		res, result, err := s.replayTx(ctx, block.Header(), msg, statedb, tx.Hash(), i, actionTracer, opts)
+		if err != nil {
+			return nil, fmt.Errorf("cannot replay transaction %s, error %s", tx.Hash().String(), err)
+		}
+		// check correct replay state according to receipt data
+		if result.Failed() != (receipts[i].Status == 0) {
+			return nil, fmt.Errorf("invalid transaction replay state at %s", tx.Hash().String())
+		}
+		hash := tx.Hash()
+		res.TransactionHash = &hash
+		results = append(results, res)
+
+		// already replayed specified transaction so end loop
+		if txHash != nil {
+			break
+		}
+	}
+	return results, nil
+}
+
+// replayTx executes the message on the given state with tracers requested by trace types.
+// State is finalised after the execution, so the next message can be replayed on top of it.
+func (s *PublicTxTraceAPI) replayTx(
+	ctx context.Context, header *evmcore.EvmHeader, msg types.Message, statedb state.StateDB,
+	txHash common.Hash, index int, actionTracer *txtrace.TraceStructLogger, opts traceOptions,
+) (*TraceResults, *evmcore.ExecutionResult, error) {
+
+	var (
+		tracers  []vm.Tracer
+		vmTracer *txtrace.VmTraceLogger
+		recorder *txtrace.StateDiffRecorder
+		vmState  = statedb
+	)
+	if actionTracer != nil {
+		tracers = append(tracers, actionTracer)
+	}
+	if opts.vmTrace {
+		vmTracer = txtrace.NewVmTraceLogger()
+		tracers = append(tracers, vmTracer)
+	}
+	if opts.stateDiff {
+		recorder = txtrace.NewStateDiffRecorder(statedb)
+		vmState = recorder
+	}
+
+	cfg := opera.DefaultVMConfig
+	cfg.NoBaseFee = true
+	if len(tracers) > 0 {
+		cfg.Debug = true
+		cfg.Tracer = txtrace.NewMuxTracer(tracers...)
+	}
+	if vmTracer != nil {
+		cfg.InterpreterImpl = "geth" // use always geth, as lfvm does not support instruction tracing
+	}
+
+	// Setup context so it may be cancelled the call has completed
+	// or, in case of unmetered gas, setup a context with a timeout.
+	ctx, cancel := context.WithTimeout(ctx, s.traceTimeout())
+	defer cancel()
+
+	vmenv, _, err := s.b.GetEVM(ctx, msg, vmState, header, &cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot initialize vm, error: %s", err.Error())
+	}
+
+	// Wait for the context to be done and cancel the evm. Even if the
+	// EVM has finished, cancelling may be done (repeatedly)
+	go func() {
+		<-ctx.Done()
+		vmenv.Cancel()
+	}()
+
+	statedb.Prepare(txHash, index)
+	result, err := evmcore.ApplyMessage(vmenv, msg, new(evmcore.GasPool).AddGas(msg.Gas()))
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := statedb.Error(); err != nil {
+		return nil, nil, fmt.Errorf("StateDB error: %w", err)
+	}
+	// If the timer caused an abort, return an appropriate error message
+	if vmenv.Cancelled() {
+		return nil, nil, fmt.Errorf("EVM was cancelled when replaying tx")
+	}
+	statedb.Finalise()
+
+	res := &TraceResults{
+		Output: common.CopyBytes(result.ReturnData),
+		Trace:  make([]txtrace.ReplayTrace, 0),
+	}
+	if actionTracer != nil {
+		res.Trace = txtrace.NewReplayTraces(actionTracer.GetResult())
+	}
+	if vmTracer != nil {
+		res.VmTrace = vmTracer.GetResult()
+	}
+	if recorder != nil {
+		res.StateDiff = recorder.GetDiff()
+	}
+	return res, result, nil
+}
@@ -2,6 +2,7 @@ package heavycheck
 
 import (
 	"bytes"
+	"crypto/ed25519"
 	"errors"
 	"runtime"
 	"sync"
@@ -141,10 +142,16 @@ func (v *Checker) EnqueueEV(ev inter.LlrSignedEpochVote, onValidated func(error)
 
 // verifySignature checks the signature against e.Creator.
 func verifySignature(signedHash hash.Hash, sig inter.Signature, pubkey validatorpk.PubKey) bool {
-	if pubkey.Type != validatorpk.Types.Secp256k1 {
-		return false
+	switch pubkey.Type {
+	case validatorpk.Types.Secp256k1:
+		return crypto.VerifySignature(pubkey.Raw, signedHash.Bytes(), sig.Bytes())
+	case validatorpk.Types.Ed25519:
+		if len(pubkey.Raw) != ed25519.PublicKeySize {
+			return false
+		}
+		return ed25519.Verify(pubkey.Raw, signedHash.Bytes(), sig.Bytes())
 	}
-	return crypto.VerifySignature(pubkey.Raw, signedHash.Bytes(), sig.Bytes())
+	return false
 }
 
 func (v *Checker) ValidateEventLocator(e inter.SignedEventLocator, authEpoch idx.Epoch, authErr error, checkPayload func() bool) error {
@@ -0,0 +1,212 @@
+package evmcore
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	notify "github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// txEventsQueueSize is the number of lifecycle events buffered for the subscribers.
+const txEventsQueueSize = 1024
+
+var lostTxEventsMeter = metrics.GetOrRegisterMeter("txpool/history/lost", nil) // Not delivered to subscribers due to overflow
+
+// TxEventType is a stage of the transaction lifecycle in the pool.
+type TxEventType string
+
+const (
+	// TxEventAdded is recorded when a transaction enters the pool.
+	TxEventAdded TxEventType = "added"
+	// TxEventRejected is recorded when a transaction isn't accepted into the pool.
+	TxEventRejected TxEventType = "rejected"
+	// TxEventPromoted is recorded when a transaction becomes executable.
+	TxEventPromoted TxEventType = "promoted"
+	// TxEventDemoted is recorded when a transaction is moved back into the future queue.
+	TxEventDemoted TxEventType = "demoted"
+	// TxEventReplaced is recorded when a transaction is replaced by another one with the same nonce.
+	TxEventReplaced TxEventType = "replaced"
+	// TxEventDropped is recorded when a transaction is removed from the pool.
+	TxEventDropped TxEventType = "dropped"
+	// TxEventIncluded is recorded when a transaction is included into a connected event.
+	TxEventIncluded TxEventType = "included"
+	// TxEventMined is recorded when a transaction leaves the pool as it's included into a block.
+	TxEventMined TxEventType = "mined"
+)
+
+// TxEvent is a lifecycle event of a transaction in the pool.
+type TxEvent struct {
+	Hash       common.Hash     `json:"hash"`
+	Type       TxEventType     `json:"type"`
+	Time       time.Time       `json:"time"`
+	Reason     string          `json:"reason,omitempty"`
+	ReplacedBy *common.Hash    `json:"replacedBy,omitempty"`
+	Event      *common.Hash    `json:"event,omitempty"`
+	Block      *hexutil.Uint64 `json:"block,omitempty"`
+}
+
+// txHistory is a bounded ring of the recent transaction lifecycle events.
+// A nil history records nothing.
+type txHistory struct {
+	mu     sync.Mutex
+	ring   []common.Hash // hashes of the recorded events, in order of recording
+	head   int           // position of the oldest event
+	count  int
+	events map[common.Hash][]TxEvent
+
+	feed  notify.Feed
+	scope notify.SubscriptionScope
+	queue chan TxEvent
+	quit  chan struct{}
+	wg    sync.WaitGroup
+}
+
+// newTxHistory creates a history of the given number of events and starts
+// the notification loop.
+func newTxHistory(size int) *txHistory {
+	h := &txHistory{
+		ring:   make([]common.Hash, size),
+		events: make(map[common.Hash][]TxEvent),
+		queue:  make(chan TxEvent, txEventsQueueSize),
+		quit:   make(chan struct{}),
+	}
+	h.wg.Add(1)
+	go h.loop()
+	return h
+}
+
+// loop delivers the events to the subscribers without blocking the pool.
+func (h *txHistory) loop() {
+	defer h.wg.Done()
+	for {
+		select {
+		case ev := <-h.queue:
+			h.feed.Send(ev)
+		case <-h.quit:
+			return
+		}
+	}
+}
+
+func (h *txHistory) stop() {
+	if h == nil {
+		return
+	}
+	h.scope.Close()
+	close(h.quit)
+	h.wg.Wait()
+}
+
+func (h *txHistory) record(ev TxEvent) {
+	if h == nil {
+		return
+	}
+	ev.Time = time.Now()
+
+	h.mu.Lock()
+	if h.count == len(h.ring) {
+		// evict the oldest event, which is the first one of its transaction
+		oldest := h.ring[h.head]
+		if evs := h.events[oldest]; len(evs) > 1 {
+			h.events[oldest] = evs[1:]
+		} else {
+			delete(h.events, oldest)
+		}
+		h.head = (h.head + 1) % len(h.ring)
+		h.count--
+	}
+	h.ring[(h.head+h.count)%len(h.ring)] = ev.Hash
+	h.count++
+	h.events[ev.Hash] = append(h.events[ev.Hash], ev)
+	h.mu.Unlock()
+
+	select {
+	case h.queue <- ev:
+	default:
+		lostTxEventsMeter.Mark(1)
+	}
+}
+
+func (h *txHistory) add(hash common.Hash, typ TxEventType, reason string) {
+	h.record(TxEvent{Hash: hash, Type: typ, Reason: reason})
+}
+
+func (h *txHistory) replaced(old, by common.Hash) {
+	h.record(TxEvent{Hash: old, Type: TxEventReplaced, ReplacedBy: &by})
+}
+
+func (h *txHistory) dropped(txs types.Transactions, reason string) {
+	for _, tx := range txs {
+		h.add(tx.Hash(), TxEventDropped, reason)
+	}
+}
+
+// stale records the transactions which are removed as their nonces are used by the blocks.
+// The transactions included into the recent blocks are recorded as mined, the rest are
+// recorded as dropped since the same nonces are used by other transactions.
+func (h *txHistory) stale(txs types.Transactions, mined map[common.Hash]uint64) {
+	for _, tx := range txs {
+		if block, ok := mined[tx.Hash()]; ok {
+			n := hexutil.Uint64(block)
+			h.record(TxEvent{Hash: tx.Hash(), Type: TxEventMined, Block: &n})
+		} else {
+			h.add(tx.Hash(), TxEventDropped, ErrNonceTooLow.Error())
+		}
+	}
+}
+
+// included records the inclusion of the known transactions into the event.
+func (h *txHistory) included(event hash.Event, txs types.Transactions) {
+	if h == nil {
+		return
+	}
+	id := common.Hash(event)
+	for _, tx := range txs {
+		h.mu.Lock()
+		_, known := h.events[tx.Hash()]
+		h.mu.Unlock()
+		if known {
+			h.record(TxEvent{Hash: tx.Hash(), Type: TxEventIncluded, Event: &id})
+		}
+	}
+}
+
+// get returns the recorded events of the transaction, from the oldest to the newest.
+func (h *txHistory) get(hash common.Hash) []TxEvent {
+	if h == nil {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]TxEvent(nil), h.events[hash]...)
+}
+
+func (h *txHistory) subscribe(ch chan<- TxEvent) notify.Subscription {
+	if h == nil {
+		return notify.NewSubscription(func(quit <-chan struct{}) error {
+			<-quit
+			return nil
+		})
+	}
+	return h.scope.Track(h.feed.Subscribe(ch))
+}
+
+// TxHistory returns the recent lifecycle events of the transaction, from the oldest to the newest.
+func (pool *TxPool) TxHistory(hash common.Hash) []TxEvent {
+	return pool.history.get(hash)
+}
+
+// SubscribeTxEvents registers a subscription of the transaction lifecycle events.
+func (pool *TxPool) SubscribeTxEvents(ch chan<- TxEvent) notify.Subscription {
+	return pool.history.subscribe(ch)
+}
+
+// NotifyIncluded records the inclusion of the transactions, which were seen by the pool, into the event.
+func (pool *TxPool) NotifyIncluded(event hash.Event, txs types.Transactions) {
+	pool.history.included(event, txs)
+}
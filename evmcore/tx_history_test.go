@@ -0,0 +1,165 @@
+package evmcore
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func checkTxEvents(t *testing.T, have []TxEvent, want ...TxEventType) {
+	t.Helper()
+	if len(have) != len(want) {
+		t.Fatalf("events count mismatch: have %d, want %d", len(have), len(want))
+	}
+	for i, ev := range have {
+		if ev.Type != want[i] {
+			t.Fatalf("event %d: type mismatch: have %s, want %s", i, ev.Type, want[i])
+		}
+	}
+}
+
+// Tests that the history forgets the oldest events when it's full.
+func TestTxHistoryEviction(t *testing.T) {
+	t.Parallel()
+
+	h := newTxHistory(3)
+	defer h.stop()
+
+	a, b := common.Hash{1}, common.Hash{2}
+	h.add(a, TxEventAdded, "")
+	h.add(b, TxEventAdded, "")
+	h.add(a, TxEventPromoted, "")
+	checkTxEvents(t, h.get(a), TxEventAdded, TxEventPromoted)
+
+	h.add(b, TxEventDropped, "lifetime")
+	checkTxEvents(t, h.get(a), TxEventPromoted)
+	checkTxEvents(t, h.get(b), TxEventAdded, TxEventDropped)
+
+	h.add(b, TxEventIncluded, "")
+	h.add(b, TxEventIncluded, "")
+	checkTxEvents(t, h.get(a))
+	checkTxEvents(t, h.get(b), TxEventDropped, TxEventIncluded, TxEventIncluded)
+}
+
+// Tests that the pool records the lifecycle of its transactions and notifies the subscribers.
+func TestTransactionHistory(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupTxPool()
+	defer pool.Stop()
+
+	events := make(chan TxEvent, 16)
+	sub := pool.SubscribeTxEvents(events)
+	defer sub.Unsubscribe()
+
+	testAddBalance(pool, crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000000))
+
+	tx := pricedTransaction(0, 100000, big.NewInt(1), key)
+	if err := pool.addRemoteSync(tx); err != nil {
+		t.Fatalf("failed to add transaction: %v", err)
+	}
+	checkTxEvents(t, pool.TxHistory(tx.Hash()), TxEventAdded, TxEventPromoted)
+
+	underpriced := pricedTransaction(0, 100001, big.NewInt(1), key)
+	if err := pool.addRemoteSync(underpriced); err != ErrReplaceUnderpriced {
+		t.Fatalf("error mismatch: have %v, want %v", err, ErrReplaceUnderpriced)
+	}
+	checkTxEvents(t, pool.TxHistory(underpriced.Hash()), TxEventRejected)
+
+	replacement := pricedTransaction(0, 100000, big.NewInt(2), key)
+	if err := pool.addRemoteSync(replacement); err != nil {
+		t.Fatalf("failed to replace transaction: %v", err)
+	}
+	history := pool.TxHistory(tx.Hash())
+	checkTxEvents(t, history, TxEventAdded, TxEventPromoted, TxEventReplaced)
+	if *history[2].ReplacedBy != replacement.Hash() {
+		t.Fatalf("replacement mismatch: have %s, want %s", history[2].ReplacedBy.Hex(), replacement.Hash().Hex())
+	}
+
+	// Only the known transactions are recorded on inclusion
+	unknown := pricedTransaction(1, 100000, big.NewInt(1), key)
+	pool.NotifyIncluded(hash.Event{3}, types.Transactions{replacement, unknown})
+	history = pool.TxHistory(replacement.Hash())
+	checkTxEvents(t, history, TxEventAdded, TxEventIncluded)
+	if *history[1].Event != (common.Hash{3}) {
+		t.Fatalf("event mismatch: have %s", history[1].Event.Hex())
+	}
+	checkTxEvents(t, pool.TxHistory(unknown.Hash()))
+
+	// The subscriber receives all the events in order
+	want := []TxEventType{TxEventAdded, TxEventPromoted, TxEventRejected, TxEventReplaced, TxEventAdded, TxEventIncluded}
+	for i, typ := range want {
+		select {
+		case ev := <-events:
+			if ev.Type != typ {
+				t.Fatalf("notification %d: type mismatch: have %s, want %s", i, ev.Type, typ)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("notification %d timeout", i)
+		}
+	}
+}
+
+// minedTestChain is a test chain which returns the block with the mined transactions
+type minedTestChain struct {
+	*testBlockChain
+	block *EvmBlock
+}
+
+func (bc *minedTestChain) GetBlock(hash common.Hash, number uint64) *EvmBlock {
+	if bc.block != nil && number == bc.block.NumberU64() {
+		return bc.block
+	}
+	return nil
+}
+
+// Tests that the transactions included into a block are recorded as mined, and the
+// transactions whose nonces are used by other transactions are recorded as dropped.
+func TestTransactionHistoryMined(t *testing.T) {
+	t.Parallel()
+
+	blockchain := &minedTestChain{testBlockChain: &testBlockChain{newTestTxPoolStateDb(), 10000000, new(event.Feed)}}
+	pool := NewTxPool(testTxPoolConfig, params.TestChainConfig, blockchain)
+	defer pool.Stop()
+
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	testAddBalance(pool, addr, big.NewInt(1000000000))
+
+	mined := pricedTransaction(0, 100000, big.NewInt(1), key)
+	conflicting := pricedTransaction(1, 100000, big.NewInt(1), key)
+	queued := pricedTransaction(3, 100000, big.NewInt(1), key)
+	for _, tx := range []*types.Transaction{mined, conflicting, queued} {
+		if err := pool.addRemoteSync(tx); err != nil {
+			t.Fatalf("failed to add transaction: %v", err)
+		}
+	}
+
+	// The block includes the first transaction and another transaction with the second nonce
+	oldHead := blockchain.CurrentBlock().Header()
+	blockchain.block = &EvmBlock{
+		EvmHeader: EvmHeader{
+			Number:     big.NewInt(2),
+			Hash:       common.Hash{2},
+			ParentHash: oldHead.Hash,
+		},
+		Transactions: types.Transactions{mined, pricedTransaction(1, 100000, big.NewInt(2), key)},
+	}
+	blockchain.statedb.nonces[addr] = 4
+	<-pool.requestReset(oldHead, blockchain.block.Header())
+
+	history := pool.TxHistory(mined.Hash())
+	checkTxEvents(t, history, TxEventAdded, TxEventPromoted, TxEventMined)
+	if uint64(*history[2].Block) != 2 {
+		t.Fatalf("block mismatch: have %d, want %d", *history[2].Block, 2)
+	}
+	checkTxEvents(t, pool.TxHistory(conflicting.Hash()), TxEventAdded, TxEventPromoted, TxEventDropped)
+	checkTxEvents(t, pool.TxHistory(queued.Hash()), TxEventAdded, TxEventDropped)
+}
@@ -0,0 +1,265 @@
+package evmcore
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+
+	"github.com/Fantom-foundation/go-opera/utils/rate"
+)
+
+// PolicyError is returned if a transaction is rejected by the admission policy.
+// It carries a distinct JSON-RPC error code for every rejection reason.
+type PolicyError struct {
+	Code    int
+	Message string
+}
+
+func (e *PolicyError) Error() string {
+	return e.Message
+}
+
+// ErrorCode returns the JSON-RPC error code of the rejection.
+func (e *PolicyError) ErrorCode() int {
+	return e.Code
+}
+
+var (
+	// ErrSenderDenied is returned if the transaction sender isn't admitted by the policy.
+	ErrSenderDenied = &PolicyError{-32010, "sender is denied by txpool policy"}
+
+	// ErrRecipientDenied is returned if the transaction recipient isn't admitted by the policy.
+	ErrRecipientDenied = &PolicyError{-32011, "recipient is denied by txpool policy"}
+
+	// ErrSenderRateLimited is returned if the sender has exceeded its transactions rate.
+	ErrSenderRateLimited = &PolicyError{-32012, "sender is rate limited by txpool policy"}
+
+	// ErrMethodBlocked is returned if the called contract method is blocked by the policy.
+	ErrMethodBlocked = &PolicyError{-32013, "contract method is blocked by txpool policy"}
+)
+
+var (
+	policyReloadInterval = 5 * time.Second // Time interval to check the policy file for changes
+	policyMaxLimiters    = 65536           // Maximum number of tracked sender rate limiters
+
+	senderDeniedMeter       = metrics.GetOrRegisterMeter("txpool/policy/sender/denied", nil)
+	recipientDeniedMeter    = metrics.GetOrRegisterMeter("txpool/policy/recipient/denied", nil)
+	senderRateLimitedMeter  = metrics.GetOrRegisterMeter("txpool/policy/sender/ratelimited", nil)
+	methodBlockedMeter      = metrics.GetOrRegisterMeter("txpool/policy/method/blocked", nil)
+	policyReloadFailedMeter = metrics.GetOrRegisterMeter("txpool/policy/reload/failed", nil)
+)
+
+// TxPolicy decides whether a transaction is admitted into the pool.
+type TxPolicy interface {
+	// Admit returns an error if the transaction of the given sender must be rejected.
+	Admit(tx *types.Transaction, from common.Address) error
+}
+
+// addressLists is a pair of allow and deny lists. A non-empty allow list
+// admits only the listed addresses.
+type addressLists struct {
+	Allow []common.Address `json:"allow"`
+	Deny  []common.Address `json:"deny"`
+}
+
+// txPolicyFile is the JSON content of the policy file.
+type txPolicyFile struct {
+	Senders    addressLists                       `json:"senders"`
+	Recipients addressLists                       `json:"recipients"`
+	Methods    map[common.Address][]hexutil.Bytes `json:"methods"` // blocked 4-byte selectors by contract
+}
+
+type addressFilter struct {
+	allow map[common.Address]bool
+	deny  map[common.Address]bool
+}
+
+func newAddressFilter(lists addressLists) addressFilter {
+	f := addressFilter{
+		allow: make(map[common.Address]bool, len(lists.Allow)),
+		deny:  make(map[common.Address]bool, len(lists.Deny)),
+	}
+	for _, addr := range lists.Allow {
+		f.allow[addr] = true
+	}
+	for _, addr := range lists.Deny {
+		f.deny[addr] = true
+	}
+	return f
+}
+
+func (f addressFilter) admits(addr common.Address) bool {
+	if f.deny[addr] {
+		return false
+	}
+	return len(f.allow) == 0 || f.allow[addr]
+}
+
+// txPolicyRules are the parsed rules of the policy file.
+type txPolicyRules struct {
+	senders    addressFilter
+	recipients addressFilter
+	methods    map[common.Address]map[[4]byte]bool
+}
+
+func loadTxPolicyRules(path string) (*txPolicyRules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file txPolicyFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	rules := &txPolicyRules{
+		senders:    newAddressFilter(file.Senders),
+		recipients: newAddressFilter(file.Recipients),
+		methods:    make(map[common.Address]map[[4]byte]bool, len(file.Methods)),
+	}
+	for contract, selectors := range file.Methods {
+		rules.methods[contract] = make(map[[4]byte]bool, len(selectors))
+		for _, selector := range selectors {
+			if len(selector) != 4 {
+				return nil, fmt.Errorf("invalid method selector %s of contract %s", selector, contract.Hex())
+			}
+			var s [4]byte
+			copy(s[:], selector)
+			rules.methods[contract][s] = true
+		}
+	}
+	return rules, nil
+}
+
+// accountPolicy is the default admission policy with the sender and recipient
+// allow/deny lists and the blocked contract methods, loaded from a file which
+// is reloaded on change, and with per-sender rate limits.
+type accountPolicy struct {
+	path        string
+	senderRate  float64
+	burst       int
+	maxLimiters int
+
+	mu       sync.Mutex
+	rules    *txPolicyRules
+	modTime  time.Time
+	checked  time.Time
+	limiters map[common.Address]*rate.Limiter
+
+	now func() time.Time
+}
+
+// newAccountPolicy creates the default admission policy. The rules file is
+// disabled if path is empty, the rate limits are disabled if senderRate is zero.
+func newAccountPolicy(path string, senderRate float64, burst int) *accountPolicy {
+	p := &accountPolicy{
+		path:        path,
+		senderRate:  senderRate,
+		burst:       burst,
+		maxLimiters: policyMaxLimiters,
+		limiters:    make(map[common.Address]*rate.Limiter),
+		now:         time.Now,
+	}
+	p.reload(p.now())
+	return p
+}
+
+// evictLimiters forgets the limiters which are equal to new ones. If there are still
+// too many of them, arbitrary limiters are forgotten, p.mu must be locked
+func (p *accountPolicy) evictLimiters(now time.Time) {
+	for addr, limiter := range p.limiters {
+		if limiter.Full(now) {
+			delete(p.limiters, addr)
+		}
+	}
+	for addr := range p.limiters {
+		if len(p.limiters) < p.maxLimiters {
+			break
+		}
+		delete(p.limiters, addr)
+	}
+}
+
+// reload re-reads the rules file if it was modified, p.mu must be locked if the policy is in use
+func (p *accountPolicy) reload(now time.Time) {
+	p.checked = now
+	p.evictLimiters(now)
+	if p.path == "" {
+		return
+	}
+	info, err := os.Stat(p.path)
+	if err != nil {
+		policyReloadFailedMeter.Mark(1)
+		log.Warn("Failed to check txpool policy", "path", p.path, "err", err)
+		return
+	}
+	if p.rules != nil && info.ModTime().Equal(p.modTime) {
+		return
+	}
+	rules, err := loadTxPolicyRules(p.path)
+	if err != nil {
+		// keep the previous rules
+		policyReloadFailedMeter.Mark(1)
+		log.Error("Failed to load txpool policy", "path", p.path, "err", err)
+		return
+	}
+	p.rules = rules
+	p.modTime = info.ModTime()
+	log.Info("Loaded txpool policy", "path", p.path, "contracts", len(rules.methods))
+}
+
+// Admit implements TxPolicy.
+func (p *accountPolicy) Admit(tx *types.Transaction, from common.Address) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	if now.Sub(p.checked) >= policyReloadInterval {
+		p.reload(now)
+	}
+	if rules := p.rules; rules != nil {
+		if !rules.senders.admits(from) {
+			senderDeniedMeter.Mark(1)
+			return ErrSenderDenied
+		}
+		// contract creations are treated as the zero recipient
+		to := common.Address{}
+		if tx.To() != nil {
+			to = *tx.To()
+		}
+		if !rules.recipients.admits(to) {
+			recipientDeniedMeter.Mark(1)
+			return ErrRecipientDenied
+		}
+		if blocked := rules.methods[to]; blocked != nil && len(tx.Data()) >= 4 {
+			var selector [4]byte
+			copy(selector[:], tx.Data())
+			if blocked[selector] {
+				methodBlockedMeter.Mark(1)
+				return ErrMethodBlocked
+			}
+		}
+	}
+	if p.senderRate > 0 {
+		limiter := p.limiters[from]
+		if limiter == nil {
+			if len(p.limiters) >= p.maxLimiters {
+				p.evictLimiters(now)
+			}
+			limiter = rate.NewLimiter(p.senderRate, p.burst, now)
+			p.limiters[from] = limiter
+		}
+		if !limiter.Allow(now) {
+			senderRateLimitedMeter.Mark(1)
+			return ErrSenderRateLimited
+		}
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package evmcore
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Tests that the admission policy applies the allow/deny lists, the blocked
+// methods and the sender rate limits, and that the rules are hot reloaded.
+func TestAccountPolicy(t *testing.T) {
+	t.Parallel()
+
+	var (
+		path     = filepath.Join(t.TempDir(), "policy.json")
+		sender   = common.HexToAddress("0x01")
+		spammer  = common.HexToAddress("0x02")
+		contract = common.HexToAddress("0x03")
+		other    = common.HexToAddress("0x04")
+	)
+	rules := `{
+		"senders": {"deny": ["` + spammer.Hex() + `"]},
+		"recipients": {"allow": ["` + contract.Hex() + `"]},
+		"methods": {"` + contract.Hex() + `": ["0xa9059cbb"]}
+	}`
+	if err := os.WriteFile(path, []byte(rules), 0644); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	policy := newAccountPolicy(path, 1, 2)
+	policy.now = func() time.Time { return now }
+
+	call := func(to common.Address, data []byte) *types.Transaction {
+		return types.NewTransaction(0, to, big.NewInt(0), 100000, big.NewInt(1), data)
+	}
+	tests := []struct {
+		tx   *types.Transaction
+		from common.Address
+		err  error
+	}{
+		{call(contract, nil), spammer, ErrSenderDenied},
+		{call(other, nil), sender, ErrRecipientDenied},
+		{types.NewContractCreation(0, big.NewInt(0), 100000, big.NewInt(1), nil), sender, ErrRecipientDenied},
+		{call(contract, common.FromHex("0xa9059cbb00")), sender, ErrMethodBlocked},
+		{call(contract, common.FromHex("0x095ea7b3")), sender, nil},
+		{call(contract, nil), sender, nil},
+		{call(contract, nil), sender, ErrSenderRateLimited},
+	}
+	for i, tt := range tests {
+		if err := policy.Admit(tt.tx, tt.from); err != tt.err {
+			t.Fatalf("test %d: error mismatch: have %v, want %v", i, err, tt.err)
+		}
+	}
+	// The rate limit is restored over time
+	now = now.Add(time.Second)
+	if err := policy.Admit(call(contract, nil), sender); err != nil {
+		t.Fatalf("failed to admit after the rate limit: %v", err)
+	}
+	// Lift the restrictions and ensure the changed file is picked up
+	if err := os.WriteFile(path, []byte(`{}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, now, now.Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(policyReloadInterval)
+	if err := policy.Admit(call(other, nil), spammer); err != nil {
+		t.Fatalf("failed to admit after the policy reload: %v", err)
+	}
+	// A broken file doesn't reset the rules
+	if err := os.WriteFile(path, []byte(`{"senders": {"deny": [`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, now, now.Add(2*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(policyReloadInterval)
+	if err := policy.Admit(call(other, nil), sender); err != nil {
+		t.Fatalf("failed to admit after a broken policy reload: %v", err)
+	}
+}
+
+// Tests that the transactions rejected by the admission policy don't enter the pool.
+func TestTransactionPolicy(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupTxPool()
+	defer pool.Stop()
+
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	testAddBalance(pool, from, big.NewInt(1000000000))
+
+	policy := newAccountPolicy("", 1, 1)
+	pool.SetPolicy(policy)
+
+	if err := pool.addRemoteSync(transaction(0, 100000, key)); err != nil {
+		t.Fatalf("failed to add transaction: %v", err)
+	}
+	if err := pool.addRemoteSync(transaction(1, 100000, key)); err != ErrSenderRateLimited {
+		t.Fatalf("error mismatch: have %v, want %v", err, ErrSenderRateLimited)
+	}
+	if pending, _ := pool.Stats(); pending != 1 {
+		t.Fatalf("pending transactions mismatched: have %d, want %d", pending, 1)
+	}
+	// The transactions restored by the pool itself aren't limited
+	if errs := pool.addTxs([]*types.Transaction{transaction(1, 100000, key)}, false, true, false); errs[0] != nil {
+		t.Fatalf("failed to add restored transaction: %v", errs[0])
+	}
+	pool.SetPolicy(nil)
+	if err := pool.addRemoteSync(transaction(2, 100000, key)); err != nil {
+		t.Fatalf("failed to add transaction without policy: %v", err)
+	}
+}
+
+// Tests that the number of the tracked sender rate limiters is bounded.
+func TestAccountPolicyLimiters(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	policy := newAccountPolicy("", 1, 2)
+	policy.now = func() time.Time { return now }
+	policy.maxLimiters = 2
+
+	tx := types.NewTransaction(0, common.Address{}, big.NewInt(0), 100000, big.NewInt(1), nil)
+	for i := 1; i <= 10; i++ {
+		if err := policy.Admit(tx, common.BigToAddress(big.NewInt(int64(i)))); err != nil {
+			t.Fatalf("failed to admit sender %d: %v", i, err)
+		}
+		if len(policy.limiters) > policy.maxLimiters {
+			t.Fatalf("limiters mismatch: have %d, want at most %d", len(policy.limiters), policy.maxLimiters)
+		}
+	}
+	// The limiters are forgotten once they are refilled
+	now = now.Add(policyReloadInterval)
+	if err := policy.Admit(tx, common.Address{1}); err != nil {
+		t.Fatalf("failed to admit sender: %v", err)
+	}
+	if len(policy.limiters) != 1 {
+		t.Fatalf("limiters mismatch: have %d, want %d", len(policy.limiters), 1)
+	}
+}
@@ -168,6 +168,17 @@ type TxPoolConfig struct {
 	Journal   string           // Journal of local transactions to survive node restarts
 	Rejournal time.Duration    // Time interval to regenerate the local transaction journal
 
+	Snapshot         string        // Snapshot of all pending and queued transactions to survive node restarts
+	SnapshotInterval time.Duration // Time interval to regenerate the transactions snapshot
+	SnapshotMaxTxs   int           // Maximum number of transactions to keep in the snapshot
+	SnapshotMaxAge   time.Duration // Maximum age of the snapshot to be loaded on startup
+
+	Policy            string  // Admission policy file with allow/deny lists and blocked methods, reloaded on change (disabled if empty)
+	PolicySenderRate  float64 // Maximum number of transactions per second admitted from a sender (unlimited if zero)
+	PolicySenderBurst int     // Maximum number of transactions admitted from a sender at once
+
+	HistorySize int // Number of recent transaction lifecycle events kept in memory (disabled if zero)
+
 	PriceLimit uint64 // Minimum gas price to enforce for acceptance into the pool
 	PriceBump  uint64 // Minimum price bump percentage to replace an already existing transaction (nonce)
 
@@ -185,6 +196,14 @@ var DefaultTxPoolConfig = TxPoolConfig{
 	Journal:   "transactions.rlp",
 	Rejournal: time.Hour,
 
+	SnapshotInterval: 5 * time.Minute,
+	SnapshotMaxTxs:   10000,
+	SnapshotMaxAge:   time.Hour,
+
+	PolicySenderBurst: 16,
+
+	HistorySize: 16384,
+
 	PriceLimit: 1,
 	PriceBump:  10,
 
@@ -204,6 +223,26 @@ func (config *TxPoolConfig) sanitize() TxPoolConfig {
 		log.Warn("Sanitizing invalid txpool journal time", "provided", conf.Rejournal, "updated", time.Second)
 		conf.Rejournal = time.Second
 	}
+	if conf.SnapshotInterval < time.Second {
+		log.Warn("Sanitizing invalid txpool snapshot interval", "provided", conf.SnapshotInterval, "updated", time.Second)
+		conf.SnapshotInterval = time.Second
+	}
+	if conf.SnapshotMaxTxs < 1 {
+		log.Warn("Sanitizing invalid txpool snapshot size", "provided", conf.SnapshotMaxTxs, "updated", DefaultTxPoolConfig.SnapshotMaxTxs)
+		conf.SnapshotMaxTxs = DefaultTxPoolConfig.SnapshotMaxTxs
+	}
+	if conf.PolicySenderRate < 0 {
+		log.Warn("Sanitizing invalid txpool policy sender rate", "provided", conf.PolicySenderRate, "updated", 0)
+		conf.PolicySenderRate = 0
+	}
+	if conf.PolicySenderBurst < 1 {
+		log.Warn("Sanitizing invalid txpool policy sender burst", "provided", conf.PolicySenderBurst, "updated", DefaultTxPoolConfig.PolicySenderBurst)
+		conf.PolicySenderBurst = DefaultTxPoolConfig.PolicySenderBurst
+	}
+	if conf.HistorySize < 0 {
+		log.Warn("Sanitizing invalid txpool history size", "provided", conf.HistorySize, "updated", 0)
+		conf.HistorySize = 0
+	}
 	if conf.PriceLimit < 1 {
 		log.Warn("Sanitizing invalid txpool price limit", "provided", conf.PriceLimit, "updated", DefaultTxPoolConfig.PriceLimit)
 		conf.PriceLimit = DefaultTxPoolConfig.PriceLimit
@@ -263,6 +302,13 @@ type TxPool struct {
 	locals  *accountSet // Set of local transaction to exempt from eviction rules
 	journal *txJournal  // Journal of local transaction to back up to disk
 
+	snapshot *txSnapshot // Snapshot of all transactions to back up to disk
+
+	policy TxPolicy // Admission policy of transactions, may be nil
+
+	history *txHistory             // Recent lifecycle events of transactions, may be nil
+	mined   map[common.Hash]uint64 // Transactions of the blocks connected by the last reset, only if history is recorded
+
 	pending map[common.Address]*txList   // All currently processable transactions
 	queue   map[common.Address]*txList   // Queued but non-processable transactions
 	beats   map[common.Address]time.Time // Last heartbeat from each known account
@@ -313,6 +359,12 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain State
 		pool.locals.add(addr)
 	}
 	pool.priced = newTxPricedList(pool.all)
+	if config.HistorySize > 0 {
+		pool.history = newTxHistory(config.HistorySize)
+	}
+	if config.Policy != "" || config.PolicySenderRate > 0 {
+		pool.policy = newAccountPolicy(config.Policy, config.PolicySenderRate, config.PolicySenderBurst)
+	}
 	pool.reset(nil, chain.CurrentBlock().Header())
 
 	// Start the reorg loop early so it can handle requests generated during journal loading.
@@ -323,13 +375,26 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain State
 	if !config.NoLocals && config.Journal != "" {
 		pool.journal = newTxJournal(config.Journal)
 
-		if err := pool.journal.load(pool.AddLocals); err != nil {
+		// the journaled transactions were admitted by the policy before
+		if err := pool.journal.load(func(txs []*types.Transaction) []error {
+			return pool.addTxs(txs, !pool.config.NoLocals, true, false)
+		}); err != nil {
 			log.Warn("Failed to load transaction journal", "err", err)
 		}
 		if err := pool.journal.rotate(pool.local()); err != nil {
 			log.Warn("Failed to rotate transaction journal", "err", err)
 		}
 	}
+	// If the snapshot is enabled, restore the remote transactions from disk
+	if config.Snapshot != "" {
+		pool.snapshot = newTxSnapshot(config.Snapshot, config.SnapshotMaxTxs, config.SnapshotMaxAge)
+
+		if err := pool.snapshot.load(func(txs []*types.Transaction) []error {
+			return pool.addTxs(txs, false, false, false)
+		}); err != nil {
+			log.Warn("Failed to load transactions snapshot", "err", err)
+		}
+	}
 
 	// Subscribe events from blockchain and start the main event loop.
 	pool.chainHeadSub = pool.chain.SubscribeNewBlock(pool.chainHeadCh)
@@ -348,15 +413,17 @@ func (pool *TxPool) loop() {
 	var (
 		prevPending, prevQueued, prevStales int
 		// Start the stats reporting and transaction eviction tickers
-		report  = time.NewTicker(statsReportInterval)
-		evict   = time.NewTicker(evictionInterval)
-		journal = time.NewTicker(pool.config.Rejournal)
+		report   = time.NewTicker(statsReportInterval)
+		evict    = time.NewTicker(evictionInterval)
+		journal  = time.NewTicker(pool.config.Rejournal)
+		snapshot = time.NewTicker(pool.config.SnapshotInterval)
 		// Track the previous head headers for transaction reorgs
 		head = pool.chain.CurrentBlock()
 	)
 	defer report.Stop()
 	defer evict.Stop()
 	defer journal.Stop()
+	defer snapshot.Stop()
 
 	for {
 		select {
@@ -395,6 +462,7 @@ func (pool *TxPool) loop() {
 				// Any non-locals old enough should be removed
 				if time.Since(pool.beats[addr]) > pool.config.Lifetime {
 					list := pool.queue[addr].Flatten()
+					pool.history.dropped(list, "lifetime")
 					for _, tx := range list {
 						pool.removeTx(tx.Hash(), true)
 					}
@@ -412,6 +480,14 @@ func (pool *TxPool) loop() {
 				}
 				pool.mu.Unlock()
 			}
+
+		// Handle transactions snapshot regeneration
+		case <-snapshot.C:
+			if pool.snapshot != nil {
+				if err := pool.snapshot.write(pool.Content()); err != nil {
+					log.Warn("Failed to write transactions snapshot", "err", err)
+				}
+			}
 		}
 	}
 }
@@ -424,10 +500,16 @@ func (pool *TxPool) Stop() {
 	// Unsubscribe subscriptions registered from blockchain
 	pool.chainHeadSub.Unsubscribe()
 	pool.wg.Wait()
+	pool.history.stop()
 
 	if pool.journal != nil {
 		pool.journal.close()
 	}
+	if pool.snapshot != nil {
+		if err := pool.snapshot.write(pool.Content()); err != nil {
+			log.Warn("Failed to write transactions snapshot", "err", err)
+		}
+	}
 	log.Info("Transaction pool stopped")
 }
 
@@ -457,6 +539,7 @@ func (pool *TxPool) SetGasPrice(price *big.Int) {
 	if price.Cmp(old) > 0 {
 		// pool.priced is sorted by GasFeeCap, so we have to iterate through pool.all instead
 		drop := pool.all.RemotesBelowTip(price)
+		pool.history.dropped(drop, "gas price limit")
 		for _, tx := range drop {
 			pool.removeTx(tx.Hash(), true)
 		}
@@ -466,6 +549,14 @@ func (pool *TxPool) SetGasPrice(price *big.Int) {
 	log.Info("Transaction pool price threshold updated", "price", price)
 }
 
+// SetPolicy replaces the admission policy of new transactions, nil disables it.
+func (pool *TxPool) SetPolicy(policy TxPolicy) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	pool.policy = policy
+}
+
 // Nonce returns the next nonce of an account, with all transactions executable
 // by the pool already applied on top.
 func (pool *TxPool) Nonce(addr common.Address) uint64 {
@@ -644,7 +735,9 @@ func (pool *TxPool) local() map[common.Address]types.Transactions {
 
 // validateTx checks whether a transaction is valid according to the consensus
 // rules and adheres to some heuristic limits of the local node (price and size).
-func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
+// The admission policy is applied only if admit is set, i.e. for the transactions
+// which are submitted to the node, but not for the ones restored by the node itself.
+func (pool *TxPool) validateTx(tx *types.Transaction, local, admit bool) error {
 	// Accept only legacy transactions until EIP-2718/2930 activates.
 	if !pool.eip2718 && tx.Type() != types.LegacyTxType {
 		return ErrTxTypeNotSupported
@@ -682,6 +775,12 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 	if err != nil {
 		return ErrInvalidSender
 	}
+	// Apply the account-level admission policy
+	if admit && pool.policy != nil {
+		if err := pool.policy.Admit(tx, from); err != nil {
+			return err
+		}
+	}
 	// Drop non-local transactions under our own minimal accepted gas price or tip
 	local = local || pool.locals.contains(from) // account may be local even if the transaction arrived from the network
 	if !local && tx.GasTipCapIntCmp(pool.gasPrice) < 0 {
@@ -726,7 +825,7 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 // If a newly added transaction is marked as local, its sending account will be
 // be added to the allowlist, preventing any associated transaction from being dropped
 // out of the pool due to pricing constraints.
-func (pool *TxPool) add(tx *types.Transaction, local bool) (replaced bool, err error) {
+func (pool *TxPool) add(tx *types.Transaction, local, admit bool) (replaced bool, err error) {
 	// If the transaction is already known, discard it
 	hash := tx.Hash()
 	if pool.all.Get(hash) != nil {
@@ -738,9 +837,10 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (replaced bool, err e
 	isLocal := local || pool.locals.containsTx(tx)
 
 	// If the transaction fails basic validation, discard it
-	if err := pool.validateTx(tx, isLocal); err != nil {
+	if err := pool.validateTx(tx, isLocal, admit); err != nil {
 		log.Trace("Discarding invalid transaction", "hash", hash, "err", err)
 		invalidTxMeter.Mark(1)
+		pool.history.add(hash, TxEventRejected, err.Error())
 		return false, err
 	}
 
@@ -753,6 +853,7 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (replaced bool, err e
 		if !isLocal && pool.priced.Underpriced(tx) {
 			log.Trace("Discarding underpriced transaction", "hash", hash, "gasTipCap", tx.GasTipCap(), "gasFeeCap", tx.GasFeeCap())
 			underpricedTxMeter.Mark(1)
+			pool.history.add(hash, TxEventRejected, ErrUnderpriced.Error())
 			return false, ErrUnderpriced
 		}
 		// New transaction is better than our worse ones, make room for it.
@@ -764,12 +865,14 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (replaced bool, err e
 		if !isLocal && !success {
 			log.Trace("Discarding overflown transaction", "hash", hash)
 			overflowedTxMeter.Mark(1)
+			pool.history.add(hash, TxEventRejected, ErrTxPoolOverflow.Error())
 			return false, ErrTxPoolOverflow
 		}
 		// Kick out the underpriced remote transactions.
 		for _, tx := range drop {
 			log.Trace("Discarding freshly underpriced transaction", "hash", tx.Hash(), "gasTipCap", tx.GasTipCap(), "gasFeeCap", tx.GasFeeCap())
 			underpricedTxMeter.Mark(1)
+			pool.history.add(tx.Hash(), TxEventDropped, "underpriced")
 			pool.removeTx(tx.Hash(), false) // don't remove from priced, already removed by Discard
 		}
 	}
@@ -780,6 +883,7 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (replaced bool, err e
 		inserted, old := list.Add(tx, pool.config.PriceBump)
 		if !inserted {
 			pendingDiscardMeter.Mark(1)
+			pool.history.add(hash, TxEventRejected, ErrReplaceUnderpriced.Error())
 			return false, ErrReplaceUnderpriced
 		}
 		// New transaction is better, replace old one
@@ -787,11 +891,13 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (replaced bool, err e
 			pool.all.Remove(old.Hash())
 			pool.priced.Removed(1)
 			pendingReplaceMeter.Mark(1)
+			pool.history.replaced(old.Hash(), hash)
 		}
 		pool.all.Add(tx, isLocal)
 		pool.priced.Put(tx, isLocal)
 		pool.journalTx(from, tx)
 		pool.queueTxEvent(tx)
+		pool.history.add(hash, TxEventAdded, "pending")
 		log.Trace("Pooled new executable transaction", "hash", hash, "from", from, "to", tx.To())
 
 		// Successful promotion, bump the heartbeat
@@ -801,8 +907,10 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (replaced bool, err e
 	// New transaction isn't replacing a pending one, push into queue
 	replaced, err = pool.enqueueTx(hash, tx, isLocal, true)
 	if err != nil {
+		pool.history.add(hash, TxEventRejected, err.Error())
 		return false, err
 	}
+	pool.history.add(hash, TxEventAdded, "queued")
 	// Mark local addresses and journal local transactions
 	if local && !pool.locals.contains(from) {
 		log.Debug("Setting new local account", "address", from)
@@ -849,6 +957,7 @@ func (pool *TxPool) enqueueTx(hash common.Hash, tx *types.Transaction, local boo
 		pool.all.Remove(old.Hash())
 		pool.priced.Removed(1)
 		queuedReplaceMeter.Mark(1)
+		pool.history.replaced(old.Hash(), hash)
 	} else {
 		// Nothing was replaced, bump the queued counter
 		queuedGauge.Inc(1)
@@ -898,6 +1007,7 @@ func (pool *TxPool) promoteTx(addr common.Address, hash common.Hash, tx *types.T
 		pool.all.Remove(hash)
 		pool.priced.Removed(1)
 		pendingDiscardMeter.Mark(1)
+		pool.history.add(hash, TxEventDropped, ErrReplaceUnderpriced.Error())
 		return false
 	}
 	// Otherwise discard any previous transaction and mark this
@@ -905,6 +1015,7 @@ func (pool *TxPool) promoteTx(addr common.Address, hash common.Hash, tx *types.T
 		pool.all.Remove(old.Hash())
 		pool.priced.Removed(1)
 		pendingReplaceMeter.Mark(1)
+		pool.history.replaced(old.Hash(), hash)
 	} else {
 		// Nothing was replaced, bump the pending counter
 		pendingGauge.Inc(1)
@@ -914,6 +1025,7 @@ func (pool *TxPool) promoteTx(addr common.Address, hash common.Hash, tx *types.T
 
 	// Successful promotion, bump the heartbeat
 	pool.beats[addr] = time.Now()
+	pool.history.add(hash, TxEventPromoted, "")
 	return true
 }
 
@@ -923,7 +1035,7 @@ func (pool *TxPool) promoteTx(addr common.Address, hash common.Hash, tx *types.T
 // This method is used to add transactions from the RPC API and performs synchronous pool
 // reorganization and event propagation.
 func (pool *TxPool) AddLocals(txs []*types.Transaction) []error {
-	return pool.addTxs(txs, !pool.config.NoLocals, true)
+	return pool.addTxs(txs, !pool.config.NoLocals, true, true)
 }
 
 // AddLocal enqueues a single local transaction into the pool if it is valid. This is
@@ -939,12 +1051,12 @@ func (pool *TxPool) AddLocal(tx *types.Transaction) error {
 // This method is used to add transactions from the p2p network and does not wait for pool
 // reorganization and internal event propagation.
 func (pool *TxPool) AddRemotes(txs []*types.Transaction) []error {
-	return pool.addTxs(txs, false, false)
+	return pool.addTxs(txs, false, false, true)
 }
 
 // This is like AddRemotes, but waits for pool reorganization. Tests use this method.
 func (pool *TxPool) AddRemotesSync(txs []*types.Transaction) []error {
-	return pool.addTxs(txs, false, true)
+	return pool.addTxs(txs, false, true, true)
 }
 
 // This is like AddRemotes with a single transaction, but waits for pool reorganization. Tests use this method.
@@ -963,7 +1075,8 @@ func (pool *TxPool) AddRemote(tx *types.Transaction) error {
 }
 
 // addTxs attempts to queue a batch of transactions if they are valid.
-func (pool *TxPool) addTxs(txs []*types.Transaction, local, sync bool) []error {
+// The admission policy is applied if admit is set.
+func (pool *TxPool) addTxs(txs []*types.Transaction, local, sync, admit bool) []error {
 	arrivedAt := time.Now()
 	// Filter out known ones without obtaining the pool lock or recovering signatures
 	var (
@@ -994,7 +1107,7 @@ func (pool *TxPool) addTxs(txs []*types.Transaction, local, sync bool) []error {
 
 	// Process all the new transaction and merge any errors into the original slice
 	pool.mu.Lock()
-	newErrs, dirtyAddrs := pool.addTxsLocked(news, local)
+	newErrs, dirtyAddrs := pool.addTxsLocked(news, local, admit)
 	pool.mu.Unlock()
 
 	// memorize tx time of validated transactions
@@ -1022,11 +1135,11 @@ func (pool *TxPool) addTxs(txs []*types.Transaction, local, sync bool) []error {
 
 // addTxsLocked attempts to queue a batch of transactions if they are valid.
 // The transaction pool lock must be held.
-func (pool *TxPool) addTxsLocked(txs []*types.Transaction, local bool) ([]error, *accountSet) {
+func (pool *TxPool) addTxsLocked(txs []*types.Transaction, local, admit bool) ([]error, *accountSet) {
 	dirty := newAccountSet(pool.signer)
 	errs := make([]error, len(txs))
 	for i, tx := range txs {
-		replaced, err := pool.add(tx, local)
+		replaced, err := pool.add(tx, local, admit)
 		errs[i] = err
 		if err == nil && !replaced {
 			dirty.addTx(tx)
@@ -1101,6 +1214,7 @@ func (pool *TxPool) removeTx(hash common.Hash, removeFromPriced bool) {
 			}
 			// Postpone any invalidated transactions
 			for _, tx := range invalids {
+				pool.history.add(tx.Hash(), TxEventDemoted, "nonce gap")
 				// Internal shuffle shouldn't touch the lookup set.
 				_,_ = pool.enqueueTx(tx.Hash(), tx, false, false)
 				// err should not occur, as it cannot be replacing of existing tx in queue
@@ -1391,11 +1505,12 @@ func (pool *TxPool) reset(oldHead, newHead *EvmHeader) {
 	pool.currentState = statedb
 	pool.pendingNonces = newTxNoncer(statedb)
 	pool.currentMaxGas = pool.chain.MaxGasLimit()
+	pool.mined = pool.minedTxs(oldHead, newHead)
 
 	// Inject any transactions discarded due to reorgs
 	log.Debug("Reinjecting stale transactions", "count", len(reinject))
 	senderCacher.recover(pool.signer, reinject)
-	pool.addTxsLocked(reinject, false)
+	pool.addTxsLocked(reinject, false, false)
 
 	// Update all fork indicator by next pending block number.
 	next := new(big.Int).Add(newHead.Number, big.NewInt(1))
@@ -1404,6 +1519,35 @@ func (pool *TxPool) reset(oldHead, newHead *EvmHeader) {
 	pool.eip1559 = pool.chainconfig.IsLondon(next)
 }
 
+// minedTxs returns the transactions of the blocks connected after the old head,
+// indexed by their block numbers. It's used only to record the transactions history.
+func (pool *TxPool) minedTxs(oldHead, newHead *EvmHeader) map[common.Hash]uint64 {
+	if pool.history == nil {
+		return nil
+	}
+	newNum := newHead.Number.Uint64()
+	oldNum := newNum - 1
+	if oldHead != nil && oldHead.Number.Uint64() < newNum {
+		oldNum = oldHead.Number.Uint64()
+	}
+	if newNum-oldNum > 64 {
+		oldNum = newNum - 64
+	}
+	mined := make(map[common.Hash]uint64)
+	blockHash := newHead.Hash
+	for n := newNum; n > oldNum; n-- {
+		block := pool.chain.GetBlock(blockHash, n)
+		if block == nil {
+			break
+		}
+		for _, tx := range block.Transactions {
+			mined[tx.Hash()] = n
+		}
+		blockHash = block.ParentHash
+	}
+	return mined
+}
+
 // promoteExecutables moves transactions that have become processable from the
 // future queue to the set of pending transactions. During this process, all
 // invalidated transactions (low nonce, low balance) are deleted.
@@ -1423,6 +1567,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) []*types.Trans
 			hash := tx.Hash()
 			pool.all.Remove(hash)
 		}
+		pool.history.stale(forwards, pool.mined)
 		log.Trace("Removed old queued transactions", "count", len(forwards))
 		// Drop all transactions that are too costly (low balance or out of gas)
 		drops, _ := list.Filter(pool.currentState.GetBalance(addr), pool.currentMaxGas)
@@ -1430,6 +1575,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) []*types.Trans
 			hash := tx.Hash()
 			pool.all.Remove(hash)
 		}
+		pool.history.dropped(drops, "unpayable")
 		log.Trace("Removed unpayable queued transactions", "count", len(drops))
 		queuedNofundsMeter.Mark(int64(len(drops)))
 
@@ -1453,6 +1599,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) []*types.Trans
 				pool.all.Remove(hash)
 				log.Trace("Removed cap-exceeding queued transaction", "hash", hash)
 			}
+			pool.history.dropped(caps, "account queue limit")
 			queuedRateLimitMeter.Mark(int64(len(caps)))
 		}
 		// Mark all the items dropped as removed
@@ -1519,6 +1666,7 @@ func (pool *TxPool) truncatePending() {
 						pool.pendingNonces.setIfLower(offenders[i], tx.Nonce())
 						log.Trace("Removed fairness-exceeding pending transaction", "hash", hash)
 					}
+					pool.history.dropped(caps, "pending limit")
 					pool.priced.Removed(len(caps))
 					pendingGauge.Dec(int64(len(caps)))
 					if pool.locals.contains(offenders[i]) {
@@ -1546,6 +1694,7 @@ func (pool *TxPool) truncatePending() {
 					pool.pendingNonces.setIfLower(addr, tx.Nonce())
 					log.Trace("Removed fairness-exceeding pending transaction", "hash", hash)
 				}
+				pool.history.dropped(caps, "pending limit")
 				pool.priced.Removed(len(caps))
 				pendingGauge.Dec(int64(len(caps)))
 				if pool.locals.contains(addr) {
@@ -1587,6 +1736,7 @@ func (pool *TxPool) truncateQueue() {
 		// Drop all transactions if they are less than the overflow
 		if size := uint64(list.Len()); size <= drop {
 			for _, tx := range list.Flatten() {
+				pool.history.add(tx.Hash(), TxEventDropped, "queue limit")
 				pool.removeTx(tx.Hash(), true)
 			}
 			drop -= size
@@ -1596,6 +1746,7 @@ func (pool *TxPool) truncateQueue() {
 		// Otherwise drop only last few transactions
 		txs := list.Flatten()
 		for i := len(txs) - 1; i >= 0 && drop > 0; i-- {
+			pool.history.add(txs[i].Hash(), TxEventDropped, "queue limit")
 			pool.removeTx(txs[i].Hash(), true)
 			drop--
 			queuedRateLimitMeter.Mark(1)
@@ -1622,6 +1773,7 @@ func (pool *TxPool) demoteUnexecutables() {
 			pool.all.Remove(hash)
 			log.Trace("Removed old pending transaction", "hash", hash)
 		}
+		pool.history.stale(olds, pool.mined)
 		// Drop all transactions that are too costly (low balance or out of gas), and queue any invalids back for later
 		drops, invalids := list.Filter(pool.currentState.GetBalance(addr), pool.currentMaxGas)
 		for _, tx := range drops {
@@ -1629,11 +1781,13 @@ func (pool *TxPool) demoteUnexecutables() {
 			log.Trace("Removed unpayable pending transaction", "hash", hash)
 			pool.all.Remove(hash)
 		}
+		pool.history.dropped(drops, "unpayable")
 		pendingNofundsMeter.Mark(int64(len(drops)))
 
 		for _, tx := range invalids {
 			hash := tx.Hash()
 			log.Trace("Demoting pending transaction", "hash", hash)
+			pool.history.add(hash, TxEventDemoted, "unexecutable")
 
 			// Internal shuffle shouldn't touch the lookup set.
 			pool.enqueueTx(hash, tx, false, false)
@@ -1649,6 +1803,7 @@ func (pool *TxPool) demoteUnexecutables() {
 			for _, tx := range gapped {
 				hash := tx.Hash()
 				log.Error("Demoting invalidated transaction", "hash", hash)
+				pool.history.add(hash, TxEventDemoted, "nonce gap")
 
 				// Internal shuffle shouldn't touch the lookup set.
 				pool.enqueueTx(hash, tx, false, false)
@@ -24,6 +24,7 @@ import (
 	"math/big"
 	"math/rand"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -464,14 +465,14 @@ func TestTransactionChainFork(t *testing.T) {
 	resetState()
 
 	tx := transaction(0, 100000, key)
-	if _, err := pool.add(tx, false); err != nil {
+	if _, err := pool.add(tx, false, true); err != nil {
 		t.Error("didn't expect error", err)
 	}
 	pool.removeTx(tx.Hash(), true)
 
 	// reset the pool's internal state
 	resetState()
-	if _, err := pool.add(tx, false); err != nil {
+	if _, err := pool.add(tx, false, true); err != nil {
 		t.Error("didn't expect error", err)
 	}
 }
@@ -498,10 +499,10 @@ func TestTransactionDoubleNonce(t *testing.T) {
 	tx3, _ := types.SignTx(types.NewTransaction(0, common.Address{}, big.NewInt(100), 1000000, big.NewInt(1), nil), signer, key)
 
 	// Add the first two transaction, ensure higher priced stays only
-	if replace, err := pool.add(tx1, false); err != nil || replace {
+	if replace, err := pool.add(tx1, false, true); err != nil || replace {
 		t.Errorf("first transaction insert failed (%v) or reported replacement (%v)", err, replace)
 	}
-	if replace, err := pool.add(tx2, false); err != nil || !replace {
+	if replace, err := pool.add(tx2, false, true); err != nil || !replace {
 		t.Errorf("second transaction insert failed (%v) or not reported replacement (%v)", err, replace)
 	}
 	<-pool.requestPromoteExecutables(newAccountSet(signer, addr))
@@ -513,7 +514,7 @@ func TestTransactionDoubleNonce(t *testing.T) {
 	}
 
 	// Add the third transaction and ensure it's not saved (smaller price)
-	pool.add(tx3, false)
+	pool.add(tx3, false, true)
 	<-pool.requestPromoteExecutables(newAccountSet(signer, addr))
 	if pool.pending[addr].Len() != 1 {
 		t.Error("expected 1 pending transactions, got", pool.pending[addr].Len())
@@ -536,7 +537,7 @@ func TestTransactionMissingNonce(t *testing.T) {
 	addr := crypto.PubkeyToAddress(key.PublicKey)
 	testAddBalance(pool, addr, big.NewInt(100000000000000))
 	tx := transaction(1, 100000, key)
-	if _, err := pool.add(tx, false); err != nil {
+	if _, err := pool.add(tx, false, true); err != nil {
 		t.Error("didn't expect error", err)
 	}
 	if len(pool.pending) != 0 {
@@ -2438,6 +2439,70 @@ func testTransactionJournaling(t *testing.T, nolocals bool) {
 	pool.Stop()
 }
 
+// TestTransactionSnapshot tests that remote transactions are restored from the
+// snapshot after a restart and revalidated against the current state.
+func TestTransactionSnapshot(t *testing.T) {
+	t.Parallel()
+
+	snapshot := filepath.Join(t.TempDir(), "txpool.rlp")
+
+	// Create the original pool to inject remote transactions into the snapshot
+	statedb := newTestTxPoolStateDb()
+	blockchain := &testBlockChain{statedb, 1000000, new(event.Feed)}
+
+	config := testTxPoolConfig
+	config.Snapshot = snapshot
+
+	pool := NewTxPool(config, params.TestChainConfig, blockchain)
+
+	remote, _ := crypto.GenerateKey()
+	testAddBalance(pool, crypto.PubkeyToAddress(remote.PublicKey), big.NewInt(1000000000))
+
+	// Add two pending and a queued remote transactions
+	for _, nonce := range []uint64{0, 1, 3} {
+		if err := pool.addRemoteSync(pricedTransaction(nonce, 100000, big.NewInt(1), remote)); err != nil {
+			t.Fatalf("failed to add remote transaction: %v", err)
+		}
+	}
+	pending, queued := pool.Stats()
+	if pending != 2 || queued != 1 {
+		t.Fatalf("transactions mismatched: have %d/%d, want %d/%d", pending, queued, 2, 1)
+	}
+	// Terminate the old pool, bump the nonce, create a new pool and ensure the valid transactions survive
+	pool.Stop()
+	statedb.nonces[crypto.PubkeyToAddress(remote.PublicKey)] = 1
+	blockchain = &testBlockChain{statedb, 1000000, new(event.Feed)}
+
+	pool = NewTxPool(config, params.TestChainConfig, blockchain)
+	<-pool.requestPromoteExecutables(newAccountSet(pool.signer, crypto.PubkeyToAddress(remote.PublicKey)))
+
+	pending, queued = pool.Stats()
+	if pending != 1 || queued != 1 {
+		t.Fatalf("transactions mismatched: have %d/%d, want %d/%d", pending, queued, 1, 1)
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+	pool.Stop()
+
+	// Ensure the snapshot size is bounded
+	config.SnapshotMaxTxs = 1
+	pool = NewTxPool(config, params.TestChainConfig, blockchain)
+	<-pool.requestPromoteExecutables(newAccountSet(pool.signer, crypto.PubkeyToAddress(remote.PublicKey)))
+	if pending, queued = pool.Stats(); pending+queued != 1 {
+		t.Fatalf("transactions mismatched: have %d, want %d", pending+queued, 1)
+	}
+	pool.Stop()
+
+	// Ensure an outdated snapshot is skipped
+	config.SnapshotMaxAge = 0
+	pool = NewTxPool(config, params.TestChainConfig, blockchain)
+	if pending, queued = pool.Stats(); pending+queued != 0 {
+		t.Fatalf("transactions mismatched: have %d, want %d", pending+queued, 0)
+	}
+	pool.Stop()
+}
+
 // TestTransactionStatusCheck tests that the pool can correctly retrieve the
 // pending status of individual transactions.
 func TestTransactionStatusCheck(t *testing.T) {
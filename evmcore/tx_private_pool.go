@@ -0,0 +1,261 @@
+package evmcore
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+var (
+	// ErrPrivatePoolDisabled is returned if the private transaction pool isn't enabled.
+	ErrPrivatePoolDisabled = errors.New("private transaction pool is disabled")
+
+	// ErrPrivatePoolFull is returned if the private transaction pool has reached its limit.
+	ErrPrivatePoolFull = errors.New("private transaction pool is full")
+
+	// ErrPrivateAccountFull is returned if the private transaction pool has reached
+	// its limit of the transactions of the sender.
+	ErrPrivateAccountFull = errors.New("private transaction pool is full for the sender")
+)
+
+// PrivateTxPoolConfig are the configuration parameters of the private transaction pool.
+type PrivateTxPoolConfig struct {
+	Enabled      bool   // Whether the private transactions are accepted, only validators may enable it
+	Lifetime     uint64 // Number of blocks after which a non-included private transaction expires
+	AccountSlots int    // Maximum number of private transactions per account
+	MaxTxs       int    // Maximum number of private transactions
+}
+
+// DefaultPrivateTxPoolConfig contains the default configurations for the private
+// transaction pool.
+var DefaultPrivateTxPoolConfig = PrivateTxPoolConfig{
+	Enabled:      false,
+	Lifetime:     100,
+	AccountSlots: 16,
+	MaxTxs:       1024,
+}
+
+type privateTx struct {
+	tx    *types.Transaction
+	from  common.Address
+	block uint64 // block number at the submission
+}
+
+// PrivateTxPool contains the transactions which are included only into the
+// events of the local emitter. The transactions are never broadcast to peers,
+// they leave the pool when they are included in the blockchain or expired.
+type PrivateTxPool struct {
+	config PrivateTxPoolConfig
+	chain  StateReader
+	signer types.Signer
+
+	mu  sync.RWMutex
+	txs map[common.Hash]*privateTx
+}
+
+// NewPrivateTxPool creates a new private transaction pool.
+func NewPrivateTxPool(config PrivateTxPoolConfig, chain StateReader, signer types.Signer) *PrivateTxPool {
+	return &PrivateTxPool{
+		config: config,
+		chain:  chain,
+		signer: signer,
+		txs:    make(map[common.Hash]*privateTx),
+	}
+}
+
+// validateTx checks the transaction against the consensus rules and the current state.
+func (pool *PrivateTxPool) validateTx(tx *types.Transaction, state TxPoolStateDB) (common.Address, error) {
+	if uint64(tx.Size()) > txMaxSize {
+		return common.Address{}, ErrOversizedData
+	}
+	if tx.Value().Sign() < 0 {
+		return common.Address{}, ErrNegativeValue
+	}
+	if pool.chain.MaxGasLimit() < tx.Gas() {
+		return common.Address{}, ErrGasLimit
+	}
+	if tx.GasFeeCap().BitLen() > 256 {
+		return common.Address{}, ErrFeeCapVeryHigh
+	}
+	if tx.GasTipCap().BitLen() > 256 {
+		return common.Address{}, ErrTipVeryHigh
+	}
+	if tx.GasFeeCapIntCmp(tx.GasTipCap()) < 0 {
+		return common.Address{}, ErrTipAboveFeeCap
+	}
+	from, err := types.Sender(pool.signer, tx)
+	if err != nil {
+		return common.Address{}, ErrInvalidSender
+	}
+	if recommendedGasTip, minPrice := pool.chain.EffectiveMinTip(), pool.chain.MinGasPrice(); recommendedGasTip != nil && minPrice != nil {
+		if tx.GasTipCapIntCmp(recommendedGasTip) < 0 || tx.GasFeeCapIntCmp(new(big.Int).Add(recommendedGasTip, minPrice)) < 0 {
+			return common.Address{}, ErrUnderpriced
+		}
+	}
+	if state.GetNonce(from) > tx.Nonce() {
+		return common.Address{}, ErrNonceTooLow
+	}
+	if state.GetBalance(from).Cmp(tx.Cost()) < 0 {
+		return common.Address{}, ErrInsufficientFunds
+	}
+	intrGas, err := IntrinsicGas(tx.Data(), tx.AccessList(), tx.To() == nil)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if tx.Gas() < intrGas {
+		return common.Address{}, ErrIntrinsicGas
+	}
+	return from, nil
+}
+
+// Add validates the transaction and inserts it into the pool. A transaction
+// with the same sender and nonce is replaced.
+func (pool *PrivateTxPool) Add(tx *types.Transaction) error {
+	if !pool.config.Enabled {
+		return ErrPrivatePoolDisabled
+	}
+	state, err := pool.chain.GetTxPoolStateDB()
+	if err != nil {
+		return err
+	}
+	defer state.Release()
+	from, err := pool.validateTx(tx, state)
+	if err != nil {
+		return err
+	}
+
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	if pool.txs[tx.Hash()] != nil {
+		return ErrAlreadyKnown
+	}
+	var replaced common.Hash
+	senderTxs := 0
+	for hash, ptx := range pool.txs {
+		if ptx.from == from {
+			if ptx.tx.Nonce() == tx.Nonce() {
+				replaced = hash
+			} else {
+				senderTxs++
+			}
+		}
+	}
+	if replaced != (common.Hash{}) {
+		delete(pool.txs, replaced)
+	} else if senderTxs >= pool.config.AccountSlots {
+		return ErrPrivateAccountFull
+	} else if len(pool.txs) >= pool.config.MaxTxs {
+		return ErrPrivatePoolFull
+	}
+	pool.txs[tx.Hash()] = &privateTx{
+		tx:    tx,
+		from:  from,
+		block: pool.chain.CurrentBlock().NumberU64(),
+	}
+	log.Debug("Added private transaction", "hash", tx.Hash(), "from", from, "nonce", tx.Nonce())
+	return nil
+}
+
+// prune removes the included and expired transactions, pool.mu must be locked.
+func (pool *PrivateTxPool) prune() {
+	if len(pool.txs) == 0 {
+		return
+	}
+	state, err := pool.chain.GetTxPoolStateDB()
+	if err != nil {
+		log.Warn("Failed to prune private transactions", "err", err)
+		return
+	}
+	defer state.Release()
+	current := pool.chain.CurrentBlock().NumberU64()
+	for hash, ptx := range pool.txs {
+		if state.GetNonce(ptx.from) > ptx.tx.Nonce() {
+			delete(pool.txs, hash)
+		} else if current >= ptx.block+pool.config.Lifetime {
+			log.Debug("Private transaction expired", "hash", hash, "block", ptx.block)
+			delete(pool.txs, hash)
+		}
+	}
+}
+
+// Has returns an indicator whether the pool has a transaction with the given hash.
+func (pool *PrivateTxPool) Has(hash common.Hash) bool {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+	return pool.txs[hash] != nil
+}
+
+// Count returns the number of the private transactions.
+func (pool *PrivateTxPool) Count() int {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+	return len(pool.txs)
+}
+
+// sorted groups the stored transactions by origin account and sorts them by nonce,
+// pool.mu must be locked.
+func (pool *PrivateTxPool) sorted() map[common.Address]types.Transactions {
+	txs := make(map[common.Address]types.Transactions)
+	for _, ptx := range pool.txs {
+		txs[ptx.from] = append(txs[ptx.from], ptx.tx)
+	}
+	for _, list := range txs {
+		sort.Sort(types.TxByNonce(list))
+	}
+	return txs
+}
+
+// Pending retrieves the processable private transactions, grouped by origin
+// account and sorted by nonce. Only the transactions with nonces contiguous to
+// the current account nonce are returned. The returned map is modifiable by the caller.
+//
+// The enforceTips parameter can be used to cap the lists at the transactions
+// paying less than the current minimal tip, which may be raised after the
+// transactions are accepted.
+func (pool *PrivateTxPool) Pending(enforceTips bool) (map[common.Address]types.Transactions, error) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	pool.prune()
+	if len(pool.txs) == 0 {
+		return map[common.Address]types.Transactions{}, nil
+	}
+
+	state, err := pool.chain.GetTxPoolStateDB()
+	if err != nil {
+		return nil, err
+	}
+	defer state.Release()
+	minTip := pool.chain.EffectiveMinTip()
+	baseFee := pool.chain.CurrentBlock().BaseFee
+
+	pending := pool.sorted()
+	for addr, txs := range pending {
+		nonce := state.GetNonce(addr)
+		for i, tx := range txs {
+			if tx.Nonce() != nonce+uint64(i) || (enforceTips && minTip != nil && tx.EffectiveGasTipIntCmp(minTip, baseFee) < 0) {
+				txs = txs[:i]
+				break
+			}
+		}
+		if len(txs) > 0 {
+			pending[addr] = txs
+		} else {
+			delete(pending, addr)
+		}
+	}
+	return pending, nil
+}
+
+// Content retrieves all the private transactions, including the non-processable
+// ones, grouped by origin account and sorted by nonce.
+func (pool *PrivateTxPool) Content() map[common.Address]types.Transactions {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	pool.prune()
+	return pool.sorted()
+}
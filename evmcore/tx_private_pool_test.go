@@ -0,0 +1,183 @@
+package evmcore
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Tests that private transactions are validated, replaced by nonce and pruned
+// once included or expired.
+func TestPrivateTxPool(t *testing.T) {
+	t.Parallel()
+
+	blockchain := &testBlockChain{newTestTxPoolStateDb(), 10000000, new(event.Feed)}
+	pool := NewPrivateTxPool(PrivateTxPoolConfig{Enabled: true, Lifetime: 1, AccountSlots: 4, MaxTxs: 3}, blockchain, types.HomesteadSigner{})
+
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	blockchain.statedb.balances[addr] = big.NewInt(1000000000)
+
+	if err := pool.Add(transaction(1, 100000, key)); err != nil {
+		t.Fatalf("failed to add private transaction: %v", err)
+	}
+	if err := pool.Add(transaction(0, 100000, key)); err != nil {
+		t.Fatalf("failed to add private transaction: %v", err)
+	}
+	if err := pool.Add(transaction(0, 100000, key)); !errors.Is(err, ErrAlreadyKnown) {
+		t.Fatalf("duplicate error mismatch: have %v, want %v", err, ErrAlreadyKnown)
+	}
+	if err := pool.Add(transaction(2, 1000, key)); !errors.Is(err, ErrIntrinsicGas) {
+		t.Fatalf("intrinsic gas error mismatch: have %v, want %v", err, ErrIntrinsicGas)
+	}
+	// Replace a transaction by nonce and fill the pool
+	replacement := pricedTransaction(1, 100000, big.NewInt(2), key)
+	if err := pool.Add(replacement); err != nil {
+		t.Fatalf("failed to replace private transaction: %v", err)
+	}
+	if err := pool.Add(transaction(2, 100000, key)); err != nil {
+		t.Fatalf("failed to add private transaction: %v", err)
+	}
+	if err := pool.Add(transaction(3, 100000, key)); !errors.Is(err, ErrPrivatePoolFull) {
+		t.Fatalf("pool limit error mismatch: have %v, want %v", err, ErrPrivatePoolFull)
+	}
+	pending, _ := pool.Pending(false)
+	if len(pending[addr]) != 3 {
+		t.Fatalf("pending transactions mismatched: have %d, want %d", len(pending[addr]), 3)
+	}
+	for i, tx := range pending[addr] {
+		if tx.Nonce() != uint64(i) {
+			t.Fatalf("transaction %d: nonce mismatch: have %d, want %d", i, tx.Nonce(), i)
+		}
+	}
+	if pending[addr][1].Hash() != replacement.Hash() {
+		t.Fatalf("transaction wasn't replaced")
+	}
+	// Bump the nonce to ensure the included transaction is dropped
+	blockchain.statedb.nonces[addr] = 1
+	if content := pool.Content(); len(content[addr]) != 2 {
+		t.Fatalf("transactions mismatched after inclusion: have %d, want %d", len(content[addr]), 2)
+	}
+	if err := pool.Add(transaction(0, 100000, key)); !errors.Is(err, ErrNonceTooLow) {
+		t.Fatalf("nonce error mismatch: have %v, want %v", err, ErrNonceTooLow)
+	}
+	// Age a transaction to ensure it expires
+	pool.txs[replacement.Hash()].block = 0
+	if content := pool.Content(); len(content[addr]) != 1 {
+		t.Fatalf("transactions mismatched after expiration: have %d, want %d", len(content[addr]), 1)
+	}
+	if pool.Has(replacement.Hash()) {
+		t.Fatalf("expired transaction is still in the pool")
+	}
+}
+
+// Tests that the private transactions are accepted only if the pool is enabled,
+// and that a single account can't fill the whole pool.
+func TestPrivateTxPoolLimits(t *testing.T) {
+	t.Parallel()
+
+	blockchain := &testBlockChain{newTestTxPoolStateDb(), 10000000, new(event.Feed)}
+	key1, _ := crypto.GenerateKey()
+	key2, _ := crypto.GenerateKey()
+	blockchain.statedb.balances[crypto.PubkeyToAddress(key1.PublicKey)] = big.NewInt(1000000000)
+	blockchain.statedb.balances[crypto.PubkeyToAddress(key2.PublicKey)] = big.NewInt(1000000000)
+
+	disabled := NewPrivateTxPool(DefaultPrivateTxPoolConfig, blockchain, types.HomesteadSigner{})
+	if err := disabled.Add(transaction(0, 100000, key1)); !errors.Is(err, ErrPrivatePoolDisabled) {
+		t.Fatalf("disabled pool error mismatch: have %v, want %v", err, ErrPrivatePoolDisabled)
+	}
+
+	pool := NewPrivateTxPool(PrivateTxPoolConfig{Enabled: true, Lifetime: 10, AccountSlots: 2, MaxTxs: 3}, blockchain, types.HomesteadSigner{})
+	for nonce := uint64(0); nonce < 2; nonce++ {
+		if err := pool.Add(transaction(nonce, 100000, key1)); err != nil {
+			t.Fatalf("failed to add private transaction: %v", err)
+		}
+	}
+	if err := pool.Add(transaction(2, 100000, key1)); !errors.Is(err, ErrPrivateAccountFull) {
+		t.Fatalf("account limit error mismatch: have %v, want %v", err, ErrPrivateAccountFull)
+	}
+	// Replacements are accepted for a full account
+	if err := pool.Add(pricedTransaction(1, 100000, big.NewInt(2), key1)); err != nil {
+		t.Fatalf("failed to replace private transaction: %v", err)
+	}
+	// Other accounts are limited only by the pool size
+	if err := pool.Add(transaction(0, 100000, key2)); err != nil {
+		t.Fatalf("failed to add private transaction: %v", err)
+	}
+	if err := pool.Add(transaction(1, 100000, key2)); !errors.Is(err, ErrPrivatePoolFull) {
+		t.Fatalf("pool limit error mismatch: have %v, want %v", err, ErrPrivatePoolFull)
+	}
+}
+
+// minTipBlockChain overrides the minimal tip of the test chain.
+type minTipBlockChain struct {
+	*testBlockChain
+	minTip *big.Int
+}
+
+func (bc *minTipBlockChain) EffectiveMinTip() *big.Int {
+	return bc.minTip
+}
+
+// Tests that only the processable private transactions are pending: the nonce
+// gaps cut the lists and the underpriced transactions are dropped on demand.
+func TestPrivateTxPoolPending(t *testing.T) {
+	t.Parallel()
+
+	blockchain := &minTipBlockChain{testBlockChain: &testBlockChain{newTestTxPoolStateDb(), 10000000, new(event.Feed)}}
+	pool := NewPrivateTxPool(PrivateTxPoolConfig{Enabled: true, Lifetime: 10, AccountSlots: 4, MaxTxs: 8}, blockchain, types.HomesteadSigner{})
+
+	key1, _ := crypto.GenerateKey()
+	key2, _ := crypto.GenerateKey()
+	addr1, addr2 := crypto.PubkeyToAddress(key1.PublicKey), crypto.PubkeyToAddress(key2.PublicKey)
+	blockchain.statedb.balances[addr1] = big.NewInt(1000000000)
+	blockchain.statedb.balances[addr2] = big.NewInt(1000000000)
+	blockchain.statedb.nonces[addr2] = 5
+
+	txs := types.Transactions{
+		pricedTransaction(0, 100000, big.NewInt(1), key1),
+		pricedTransaction(1, 100000, big.NewInt(3), key1),
+		pricedTransaction(3, 100000, big.NewInt(3), key1),
+		pricedTransaction(6, 100000, big.NewInt(3), key2),
+	}
+	for _, tx := range txs {
+		if err := pool.Add(tx); err != nil {
+			t.Fatalf("failed to add private transaction: %v", err)
+		}
+	}
+	pending, err := pool.Pending(false)
+	if err != nil {
+		t.Fatalf("failed to retrieve pending transactions: %v", err)
+	}
+	if len(pending) != 1 || len(pending[addr1]) != 2 {
+		t.Fatalf("pending transactions mismatched: have %v", pending)
+	}
+	if content := pool.Content(); len(content[addr1]) != 3 || len(content[addr2]) != 1 {
+		t.Fatalf("content mismatched: have %v", content)
+	}
+
+	// Filling the gap makes the following transactions pending
+	if err := pool.Add(pricedTransaction(5, 100000, big.NewInt(3), key2)); err != nil {
+		t.Fatalf("failed to add private transaction: %v", err)
+	}
+	if pending, _ = pool.Pending(false); len(pending[addr2]) != 2 {
+		t.Fatalf("pending transactions mismatched after filling the gap: have %d, want %d", len(pending[addr2]), 2)
+	}
+
+	// The raised minimal tip caps the lists only if the tips are enforced
+	blockchain.minTip = big.NewInt(2)
+	if pending, _ = pool.Pending(false); len(pending[addr1]) != 2 {
+		t.Fatalf("pending transactions mismatched without tips enforcement: have %d, want %d", len(pending[addr1]), 2)
+	}
+	pending, _ = pool.Pending(true)
+	if _, ok := pending[addr1]; ok {
+		t.Fatalf("underpriced transactions are pending with tips enforcement")
+	}
+	if len(pending[addr2]) != 2 {
+		t.Fatalf("pending transactions mismatched with tips enforcement: have %d, want %d", len(pending[addr2]), 2)
+	}
+}
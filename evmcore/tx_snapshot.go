@@ -0,0 +1,153 @@
+package evmcore
+
+import (
+	"errors"
+	"io"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+const txSnapshotVersion = 1
+
+var errTxSnapshotVersion = errors.New("unsupported transactions snapshot version")
+
+// txSnapshotHeader precedes the transactions in the snapshot file.
+type txSnapshotHeader struct {
+	Version uint
+	Time    uint64 // unix time of the snapshot writing, in seconds
+}
+
+// txSnapshot is a dump of all the pending and queued transactions, remote ones
+// included, to allow the pool content to survive node restarts.
+type txSnapshot struct {
+	path   string        // Filesystem path to store the transactions at
+	maxTxs int           // Maximum number of transactions to write and load
+	maxAge time.Duration // Maximum age of the snapshot to be loaded
+}
+
+// newTxSnapshot creates a new transactions snapshot.
+func newTxSnapshot(path string, maxTxs int, maxAge time.Duration) *txSnapshot {
+	return &txSnapshot{
+		path:   path,
+		maxTxs: maxTxs,
+		maxAge: maxAge,
+	}
+}
+
+// load parses a transactions snapshot from disk, adding its contents into the
+// specified pool. The transactions are revalidated by the pool against the
+// current state.
+func (snapshot *txSnapshot) load(add func([]*types.Transaction) []error) error {
+	input, err := os.Open(snapshot.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer input.Close()
+
+	stream := rlp.NewStream(input, 0)
+	var header txSnapshotHeader
+	if err := stream.Decode(&header); err != nil {
+		return err
+	}
+	if header.Version != txSnapshotVersion {
+		return errTxSnapshotVersion
+	}
+	age := time.Since(time.Unix(int64(header.Time), 0))
+	if age > snapshot.maxAge {
+		log.Info("Skipped outdated transactions snapshot", "age", common.PrettyDuration(age))
+		return nil
+	}
+
+	var (
+		failure error
+		txs     types.Transactions
+	)
+	for len(txs) < snapshot.maxTxs {
+		tx := new(types.Transaction)
+		if err = stream.Decode(tx); err != nil {
+			if err != io.EOF {
+				failure = err
+			}
+			break
+		}
+		txs = append(txs, tx)
+	}
+	dropped := 0
+	for _, err := range add(txs) {
+		if err != nil {
+			log.Trace("Failed to add snapshotted transaction", "err", err)
+			dropped++
+		}
+	}
+	log.Info("Loaded transactions snapshot", "transactions", len(txs), "dropped", dropped, "age", common.PrettyDuration(age))
+
+	return failure
+}
+
+// write regenerates the transactions snapshot from the pending and queued
+// transactions of the pool.
+func (snapshot *txSnapshot) write(pending, queued map[common.Address]types.Transactions) error {
+	replacement, err := os.OpenFile(snapshot.path+".new", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	header := txSnapshotHeader{
+		Version: txSnapshotVersion,
+		Time:    uint64(time.Now().Unix()),
+	}
+	if err := rlp.Encode(replacement, &header); err != nil {
+		replacement.Close()
+		return err
+	}
+	written := 0
+	encode := func(txs types.Transactions) error {
+		for _, tx := range txs {
+			if written >= snapshot.maxTxs {
+				return nil
+			}
+			if err := rlp.Encode(replacement, tx); err != nil {
+				return err
+			}
+			written++
+		}
+		return nil
+	}
+	// queued transactions of an account follow its pending ones to be promoted after the load
+	for addr, txs := range pending {
+		if err := encode(txs); err != nil {
+			replacement.Close()
+			return err
+		}
+		if err := encode(queued[addr]); err != nil {
+			replacement.Close()
+			return err
+		}
+	}
+	for addr, txs := range queued {
+		if _, ok := pending[addr]; ok {
+			continue
+		}
+		if err := encode(txs); err != nil {
+			replacement.Close()
+			return err
+		}
+	}
+	if err := replacement.Close(); err != nil {
+		return err
+	}
+
+	if err = os.Rename(snapshot.path+".new", snapshot.path); err != nil {
+		return err
+	}
+	log.Debug("Regenerated transactions snapshot", "transactions", written)
+
+	return nil
+}
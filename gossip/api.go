@@ -3,6 +3,9 @@ package gossip
 import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/Fantom-foundation/go-opera/gossip/gasprice"
+	"github.com/Fantom-foundation/go-opera/gossip/peerscore"
 )
 
 // PublicEthereumAPI provides an API to access Ethereum-like information.
@@ -35,3 +38,23 @@ func (api *PublicEthereumAPI) Hashrate() hexutil.Uint64 {
 func (api *PublicEthereumAPI) ChainId() hexutil.Uint64 {
 	return hexutil.Uint64(api.s.store.GetRules().NetworkID)
 }
+
+// GasPriceOracleStats returns the inputs of the last gas price suggestion, nil if there was no suggestion yet
+func (api *PublicEthereumAPI) GasPriceOracleStats() *gasprice.Stats {
+	return api.s.gpo.Stats()
+}
+
+// PrivateAdminAPI provides an API to access the node's p2p internals.
+type PrivateAdminAPI struct {
+	s *Service
+}
+
+// NewPrivateAdminAPI creates a new admin API for gossip.
+func NewPrivateAdminAPI(s *Service) *PrivateAdminAPI {
+	return &PrivateAdminAPI{s}
+}
+
+// PeerScores returns the scores of the recently seen peers and the active peer bans
+func (api *PrivateAdminAPI) PeerScores() []peerscore.PeerScore {
+	return api.s.handler.peerScores.Scores()
+}
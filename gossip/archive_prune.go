@@ -0,0 +1,65 @@
+package gossip
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+
+	"github.com/Fantom-foundation/go-opera/evmcore"
+	"github.com/Fantom-foundation/go-opera/inter/state"
+	"github.com/Fantom-foundation/go-opera/opera"
+)
+
+// blockEvmChainConfig returns the EVM chain config of the epoch the block belongs to
+func blockEvmChainConfig(store *Store, n idx.Block) *params.ChainConfig {
+	if es := store.GetHistoryEpochState(store.FindBlockEpoch(n)); es != nil {
+		return es.Rules.EvmChainConfig(store.GetUpgradeHeights())
+	}
+	return store.GetEvmChainConfig()
+}
+
+// ReplayBlocks re-executes historic blocks on top of the given state, which has to be
+// the state of the block preceding the range. Resulting state roots are checked against
+// the roots of the blocks, so the state is guaranteed to be the same as the original one.
+func ReplayBlocks(ctx context.Context, store *Store, statedb state.StateDB, from, to idx.Block) error {
+	reader := &EvmStateReader{store: store}
+	start, reported := time.Now(), time.Now()
+	for n := from; n <= to; n++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		block := reader.GetBlock(common.Hash{}, uint64(n))
+		if block == nil {
+			return fmt.Errorf("block %d not found", n)
+		}
+
+		statedb.BeginBlock(uint64(n))
+		var gasUsed uint64
+		_, _, _, err := evmcore.NewStateProcessor(blockEvmChainConfig(store, n), reader).Process(block, statedb, opera.DefaultVMConfig, &gasUsed, func(*types.Log) {})
+		if err != nil {
+			return fmt.Errorf("failed to replay block %d: %w", n, err)
+		}
+		statedb.EndBlock(uint64(n))
+
+		root, err := statedb.Commit(true)
+		if err != nil {
+			return fmt.Errorf("failed to commit state of block %d: %w", n, err)
+		}
+		if root != block.Root {
+			return fmt.Errorf("state root of replayed block %d does not match (%x != %x)", n, root, block.Root)
+		}
+
+		if time.Since(reported) >= 8*time.Second {
+			log.Info("Replaying blocks", "block", n, "last", to, "elapsed", common.PrettyDuration(time.Since(start)))
+			reported = time.Now()
+		}
+	}
+	log.Info("Blocks replayed", "first", from, "last", to, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
@@ -140,6 +140,8 @@ func (s *Service) switchEpochTo(newEpoch idx.Epoch) {
 		em.OnNewEpoch(s.store.GetValidators(), newEpoch)
 	}
 	s.feed.newEpoch.Send(newEpoch)
+	epochState := s.store.GetEpochState()
+	s.feed.sendAsync(&s.feed.newEpochState, &epochState)
 }
 
 func (s *Service) SwitchEpochTo(newEpoch idx.Epoch) error {
@@ -246,9 +248,12 @@ func (s *Service) processEvent(e *inter.EventPayload) error {
 
 	s.processEventEpochIndex(e, oldEpoch, newEpoch)
 
+	s.txpool.NotifyIncluded(e.ID(), e.Txs())
+
 	for _, em := range s.emitters {
 		em.OnEventConnected(e)
 	}
+	s.feed.sendAsync(&s.feed.newEvent, e)
 
 	if newEpoch != oldEpoch {
 		s.switchEpochTo(newEpoch)
@@ -2,6 +2,8 @@ package gossip
 
 import (
 	"errors"
+	"fmt"
+	"io"
 
 	"github.com/Fantom-foundation/lachesis-base/hash"
 	"github.com/Fantom-foundation/lachesis-base/inter/idx"
@@ -99,6 +101,20 @@ func (s *Service) ProcessBlockVotes(bvs inter.LlrSignedBlockVotes) error {
 	return err
 }
 
+// ImportStateSnapshot replaces the live state by the downloaded state snapshot.
+// The snapshot is accepted only if its state root matches the expected root,
+// the current live state is kept otherwise.
+func (s *Service) ImportStateSnapshot(root hash.Hash, r io.Reader) error {
+	s.engineMu.Lock()
+	defer s.engineMu.Unlock()
+	s.blockProcWg.Wait()
+
+	if err := s.store.evm.ReloadLiveWorldState(r, root); err != nil {
+		return fmt.Errorf("failed to import state snapshot: %w", err)
+	}
+	return nil
+}
+
 func indexRawReceipts(s *Store, receiptsForStorage []*types.ReceiptForStorage, txs types.Transactions, blockIdx idx.Block, atropos hash.Event) {
 	s.evm.SetRawReceipts(blockIdx, receiptsForStorage)
 	receipts, _ := evmstore.UnwrapStorageReceipts(receiptsForStorage, blockIdx, nil, common.Hash(atropos), txs)
@@ -110,7 +110,12 @@ func readEpochPubKeys(s *Store, epoch idx.Epoch) *ValidatorsPubKeys {
 	}
 	var pubkeys = make(map[idx.ValidatorID]validatorpk.PubKey, len(es.ValidatorProfiles))
 	for id, profile := range es.ValidatorProfiles {
-		pubkeys[id] = profile.PubKey
+		pubkey := profile.PubKey
+		if pubkey.Type == validatorpk.Types.Ed25519 && !es.Rules.Upgrades.Ed25519 {
+			// ed25519 keys aren't allowed before the upgrade, events of such validators are rejected
+			pubkey = validatorpk.PubKey{}
+		}
+		pubkeys[id] = pubkey
 	}
 	return &ValidatorsPubKeys{
 		Epoch:   epoch,
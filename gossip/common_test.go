@@ -189,7 +189,7 @@ func newTestEnv(firstEpoch idx.Epoch, validatorsNum idx.Validator, tb testing.TB
 		cfg.MaxTxsPerAddress = 10000000
 		_ = valKeystore.Add(pubkey, crypto.FromECDSA(makefakegenesis.FakeKey(vid)), validatorpk.FakePassword)
 		_ = valKeystore.Unlock(pubkey, validatorpk.FakePassword)
-		world := env.EmitterWorld(env.signer)
+		world := env.EmitterWorld(env.signer, nil)
 		world.External = testEmitterWorldExternal{world.External, env}
 		em := emitter.NewEmitter(cfg, world)
 		env.RegisterEmitter(em)
@@ -14,9 +14,11 @@ import (
 	"github.com/syndtr/goleveldb/leveldb/opt"
 
 	"github.com/Fantom-foundation/go-opera/eventcheck/heavycheck"
+	"github.com/Fantom-foundation/go-opera/evmcore"
 	"github.com/Fantom-foundation/go-opera/gossip/evmstore"
 	"github.com/Fantom-foundation/go-opera/gossip/filters"
 	"github.com/Fantom-foundation/go-opera/gossip/gasprice"
+	"github.com/Fantom-foundation/go-opera/gossip/peerscore"
 	"github.com/Fantom-foundation/go-opera/gossip/protocols/blockrecords/brprocessor"
 	"github.com/Fantom-foundation/go-opera/gossip/protocols/blockrecords/brstream/brstreamleecher"
 	"github.com/Fantom-foundation/go-opera/gossip/protocols/blockrecords/brstream/brstreamseeder"
@@ -28,6 +30,8 @@ import (
 	"github.com/Fantom-foundation/go-opera/gossip/protocols/epochpacks/epprocessor"
 	"github.com/Fantom-foundation/go-opera/gossip/protocols/epochpacks/epstream/epstreamleecher"
 	"github.com/Fantom-foundation/go-opera/gossip/protocols/epochpacks/epstream/epstreamseeder"
+	"github.com/Fantom-foundation/go-opera/gossip/protocols/snapshots/snapstream/snapleecher"
+	"github.com/Fantom-foundation/go-opera/gossip/protocols/snapshots/snapstream/snapseeder"
 )
 
 const nominalSize uint = 1
@@ -62,12 +66,15 @@ type (
 		BrStreamSeeder   brstreamseeder.Config
 		EpStreamLeecher  epstreamleecher.Config
 		EpStreamSeeder   epstreamseeder.Config
+		SnapLeecher      snapleecher.Config
+		SnapSeeder       snapseeder.Config
 
 		MaxInitialTxHashesSend   int
 		MaxRandomTxHashesSend    int
 		RandomTxHashesSendPeriod time.Duration
 
 		PeerCache PeerCacheConfig
+		PeerScore peerscore.Config
 	}
 
 	// Config for the gossip service.
@@ -80,6 +87,12 @@ type (
 
 		TxIndex bool // Whether to enable indexing transactions and receipts or not
 
+		// SyncMode is the mode of the initial synchronization, "dag" or "llr"
+		SyncMode string
+		// LlrSyncTimeout is how long the LLR sync may make no progress, or may not verify
+		// the state snapshot after all the decided epochs are filled, before it fails
+		LlrSyncTimeout time.Duration
+
 		// Protocol options
 		Protocol ProtocolConfig
 
@@ -88,6 +101,9 @@ type (
 		// Gas Price Oracle options
 		GPO gasprice.Config
 
+		// PrivateTxPool options of the transactions which aren't broadcast to peers
+		PrivateTxPool evmcore.PrivateTxPoolConfig
+
 		// RPCGasCap is the global gas cap for eth-call variants.
 		RPCGasCap uint64 `toml:",omitempty"`
 
@@ -159,6 +175,9 @@ func DefaultConfig(scale cachescale.Func) Config {
 
 		TxIndex: true,
 
+		SyncMode:       SyncModeDAG,
+		LlrSyncTimeout: 30 * time.Minute,
+
 		HeavyCheck: heavycheck.DefaultConfig(),
 
 		Protocol: ProtocolConfig{
@@ -209,10 +228,13 @@ func DefaultConfig(scale cachescale.Func) Config {
 			BrStreamSeeder:           brstreamseeder.DefaultConfig(scale),
 			EpStreamLeecher:          epstreamleecher.DefaultConfig(),
 			EpStreamSeeder:           epstreamseeder.DefaultConfig(scale),
+			SnapLeecher:              snapleecher.DefaultConfig(),
+			SnapSeeder:               snapseeder.DefaultConfig(),
 			MaxInitialTxHashesSend:   20000,
 			MaxRandomTxHashesSend:    250, // match softLimitItems to fit into one message
 			RandomTxHashesSendPeriod: 1 * time.Second,
 			PeerCache:                DefaultPeerCacheConfig(scale),
+			PeerScore:                peerscore.DefaultConfig(),
 		},
 
 		RPCEVMTimeout: 5 * time.Second,
@@ -221,8 +243,13 @@ func DefaultConfig(scale cachescale.Func) Config {
 			MaxGasPrice:      gasprice.DefaultMaxGasPrice,
 			MinGasPrice:      new(big.Int),
 			DefaultCertainty: 0.5 * gasprice.DecimalUnit,
+			Strategy:         gasprice.ModelStrategy,
+			PercentileBlocks: gasprice.DefaultPercentileBlocks,
+			BlendRatio:       gasprice.DecimalUnit / 2,
 		},
 
+		PrivateTxPool: evmcore.DefaultPrivateTxPoolConfig,
+
 		RPCBlockExt: true,
 
 		RPCGasCap:   50000000,
@@ -248,6 +275,9 @@ func DefaultConfig(scale cachescale.Func) Config {
 }
 
 func (c *Config) Validate() error {
+	if c.SyncMode != SyncModeDAG && c.SyncMode != SyncModeLLR {
+		return fmt.Errorf("SyncMode has to be %q or %q", SyncModeDAG, SyncModeLLR)
+	}
 	p := c.Protocol
 	defaultChunkSize := dag.Metric{idx.Event(p.DagStreamLeecher.Session.DefaultChunkItemsNum), p.DagStreamLeecher.Session.DefaultChunkItemsSize}
 	if defaultChunkSize.Num > hardLimitItems-1 {
@@ -273,6 +303,12 @@ func (c *Config) Validate() error {
 	if p.DagProcessor.EventsBufferLimit.Size < protocolMaxMsgSize {
 		return fmt.Errorf("EventsBufferLimit.Size has to be at least %d", protocolMaxMsgSize)
 	}
+	if p.SnapSeeder.MaxChunkSize > protocolMaxMsgSize/2 {
+		return fmt.Errorf("SnapSeeder.MaxChunkSize has to be at not greater than %d", protocolMaxMsgSize/2)
+	}
+	if p.SnapLeecher.ChunkSize > protocolMaxMsgSize/2 {
+		return fmt.Errorf("SnapLeecher.ChunkSize has to be at not greater than %d", protocolMaxMsgSize/2)
+	}
 
 	return nil
 }
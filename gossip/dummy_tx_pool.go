@@ -6,6 +6,7 @@ import (
 	"sort"
 	"sync"
 
+	"github.com/Fantom-foundation/lachesis-base/hash"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	notify "github.com/ethereum/go-ethereum/event"
@@ -15,9 +16,10 @@ import (
 
 // dummyTxPool is a fake, helper transaction pool for testing purposes
 type dummyTxPool struct {
-	txFeed notify.Feed
-	pool   []*types.Transaction        // Collection of all transactions
-	added  chan<- []*types.Transaction // Notification channel for new transactions
+	txFeed       notify.Feed
+	txEventsFeed notify.Feed
+	pool         []*types.Transaction        // Collection of all transactions
+	added        chan<- []*types.Transaction // Notification channel for new transactions
 
 	signer types.Signer
 
@@ -85,6 +87,16 @@ func (p *dummyTxPool) SubscribeNewTxsNotify(ch chan<- evmcore.NewTxsNotify) noti
 	return p.txFeed.Subscribe(ch)
 }
 
+func (p *dummyTxPool) TxHistory(hash common.Hash) []evmcore.TxEvent {
+	return nil
+}
+
+func (p *dummyTxPool) SubscribeTxEvents(ch chan<- evmcore.TxEvent) notify.Subscription {
+	return p.txEventsFeed.Subscribe(ch)
+}
+
+func (p *dummyTxPool) NotifyIncluded(event hash.Event, txs types.Transactions) {}
+
 func (p *dummyTxPool) Map() map[common.Hash]*types.Transaction {
 	p.lock.RLock()
 	defer p.lock.RUnlock()
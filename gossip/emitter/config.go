@@ -49,6 +49,10 @@ type Config struct {
 
 	TxsCacheInvalidation time.Duration
 
+	// SlashingProtectionFile is the slashing protection DB of the signed events
+	SlashingProtectionFile FileConfig
+
+	// DEPRECATED: replaced by SlashingProtectionFile, the files are only read if exist
 	PrevEmittedEventFile FileConfig
 	PrevBlockVotesFile   FileConfig
 	PrevEpochVoteFile    FileConfig
@@ -20,6 +20,7 @@ import (
 
 	"github.com/Fantom-foundation/go-opera/gossip/emitter/originatedtxs"
 	"github.com/Fantom-foundation/go-opera/inter"
+	"github.com/Fantom-foundation/go-opera/inter/validatorpk"
 	"github.com/Fantom-foundation/go-opera/logger"
 	"github.com/Fantom-foundation/go-opera/tracing"
 	"github.com/Fantom-foundation/go-opera/utils/errlock"
@@ -132,13 +133,13 @@ func (em *Emitter) init() {
 	em.OnNewEpoch(validators, epoch)
 
 	if len(em.config.PrevEmittedEventFile.Path) != 0 {
-		em.emittedEventFile = openPrevActionFile(em.config.PrevEmittedEventFile.Path, em.config.PrevEmittedEventFile.SyncMode)
+		em.emittedEventFile = openPrevActionFile(em.config.PrevEmittedEventFile.Path)
 	}
 	if len(em.config.PrevBlockVotesFile.Path) != 0 {
-		em.emittedBvsFile = openPrevActionFile(em.config.PrevBlockVotesFile.Path, em.config.PrevBlockVotesFile.SyncMode)
+		em.emittedBvsFile = openPrevActionFile(em.config.PrevBlockVotesFile.Path)
 	}
 	if len(em.config.PrevEpochVoteFile.Path) != 0 {
-		em.emittedEvFile = openPrevActionFile(em.config.PrevEpochVoteFile.Path, em.config.PrevEpochVoteFile.SyncMode)
+		em.emittedEvFile = openPrevActionFile(em.config.PrevEpochVoteFile.Path)
 	}
 	em.busyRate = rate.NewGauge()
 }
@@ -219,6 +220,9 @@ func (em *Emitter) tick() {
 func (em *Emitter) getSortedTxs() *types.TransactionsByPriceAndNonce {
 	// Short circuit if pool wasn't updated since the cache was built
 	poolCount := em.world.TxPool.Count()
+	if em.world.PrivateTxPool != nil {
+		poolCount += em.world.PrivateTxPool.Count()
+	}
 	if em.cache.sortedTxs != nil &&
 		em.cache.poolBlock == em.world.GetLatestBlockIndex() &&
 		em.cache.poolCount == poolCount &&
@@ -231,6 +235,14 @@ func (em *Emitter) getSortedTxs() *types.TransactionsByPriceAndNonce {
 		em.Log.Error("Tx pool transactions fetching error", "err", err)
 		return nil
 	}
+	if em.world.PrivateTxPool != nil {
+		privateTxs, err := em.world.PrivateTxPool.Pending(true)
+		if err != nil {
+			em.Log.Error("Private tx pool transactions fetching error", "err", err)
+			return nil
+		}
+		mergeTxs(pendingTxs, privateTxs)
+	}
 	for from, txs := range pendingTxs {
 		// Filter the excessive transactions from each sender
 		if len(txs) > em.config.MaxTxsPerAddress {
@@ -270,14 +282,8 @@ func (em *Emitter) EmitEvent() (*inter.EventPayload, error) {
 		em.Log.Error("Self-event connection failed", "err", err.Error())
 		return nil, err
 	}
-	// write event ID to avoid doublesigning in future after a crash
-	em.writeLastEmittedEventID(e.ID())
-	if e.EpochVote().Epoch != 0 {
-		em.writeLastEmittedEpochVote(e.EpochVote().Epoch)
-	}
-	if len(e.BlockVotes().Votes) != 0 {
-		em.writeLastEmittedBlockVotes(e.BlockVotes().LastBlock())
-	}
+	// record the event to avoid doublesigning in future after a crash
+	em.recordEmitted(e)
 	// broadcast the event
 	em.world.Broadcast(e)
 
@@ -330,12 +336,17 @@ func (em *Emitter) createEvent(sortedTxs *types.TransactionsByPriceAndNonce) (*i
 		maxLamport     idx.Lamport
 	)
 
+	if em.config.Validator.PubKey.Type == validatorpk.Types.Ed25519 && !em.world.GetRules().Upgrades.Ed25519 {
+		em.Periodic.Warn(time.Minute, "Validator key type isn't allowed by the network rules yet", "type", em.config.Validator.PubKey.Type)
+		return nil, nil
+	}
+
 	// Find parents
 	selfParent, parents, ok := em.chooseParents(em.epoch, em.config.Validator.ID)
 	if !ok {
 		return nil, nil
 	}
-	prevEmitted := em.readLastEmittedEventID()
+	prevEmitted := em.lastEmittedEventID()
 	if prevEmitted != nil && prevEmitted.Epoch() >= em.epoch {
 		if selfParent == nil || *selfParent != *prevEmitted {
 			errlock.Permanent(errors.New("Local database does not contain last emitted event - sync the node before enabling validation to avoid doublesign"))
@@ -444,6 +455,12 @@ func (em *Emitter) createEvent(sortedTxs *types.TransactionsByPriceAndNonce) (*i
 	// calc Payload hash
 	mutEvent.SetPayloadHash(inter.CalcPayloadHash(mutEvent))
 
+	// check the event against the slashing protection DB before signing
+	if err := em.approveSigning(mutEvent); err != nil {
+		em.Periodic.Error(time.Second, "Refused to sign conflicting event", "err", err)
+		return nil, err
+	}
+
 	// sign
 	bSig, err := em.world.Signer.Sign(em.config.Validator.PubKey, mutEvent.HashToSign().Bytes())
 	if err != nil {
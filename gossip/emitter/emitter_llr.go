@@ -25,7 +25,7 @@ func (em *Emitter) addLlrBlockVotes(e *inter.MutableEventPayload) {
 	if prevInDB != nil && start < *prevInDB+1 {
 		start = *prevInDB + 1
 	}
-	prevInFile := em.readLastBlockVotes()
+	prevInFile := em.lastBlockVotes()
 	if prevInFile != nil && start < *prevInFile+1 {
 		start = *prevInFile + 1
 	}
@@ -69,7 +69,7 @@ func (em *Emitter) addLlrEpochVote(e *inter.MutableEventPayload) {
 	if prevInDB != nil && target < *prevInDB+1 {
 		target = *prevInDB + 1
 	}
-	prevInFile := em.readLastEpochVote()
+	prevInFile := em.lastEpochVote()
 	if prevInFile != nil && target < *prevInFile+1 {
 		target = *prevInFile + 1
 	}
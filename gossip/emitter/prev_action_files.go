@@ -2,6 +2,7 @@ package emitter
 
 import (
 	"io"
+	"os"
 
 	"github.com/Fantom-foundation/lachesis-base/hash"
 	"github.com/Fantom-foundation/lachesis-base/inter/idx"
@@ -10,16 +11,18 @@ import (
 	"github.com/Fantom-foundation/go-opera/utils"
 )
 
-var openPrevActionFile = utils.OpenFile
+// The prev-action files are replaced by the slashing protection DB.
+// They are still read if exist, so the last actions written before the upgrade are respected.
 
-func (em *Emitter) writeLastEmittedEventID(id hash.Event) {
-	if em.emittedEventFile == nil {
-		return
+func openPrevActionFile(path string) *os.File {
+	if !utils.FileExists(path) {
+		return nil
 	}
-	_, err := em.emittedEventFile.WriteAt(id.Bytes(), 0)
+	fh, err := os.Open(path)
 	if err != nil {
-		log.Crit("Failed to write event file", "file", em.config.PrevEmittedEventFile.Path, "err", err)
+		log.Crit("Failed to open file", "file", path, "err", err)
 	}
+	return fh
 }
 
 func (em *Emitter) readLastEmittedEventID() *hash.Event {
@@ -38,16 +41,6 @@ func (em *Emitter) readLastEmittedEventID() *hash.Event {
 	return &v
 }
 
-func (em *Emitter) writeLastEmittedBlockVotes(b idx.Block) {
-	if em.emittedBvsFile == nil {
-		return
-	}
-	_, err := em.emittedBvsFile.WriteAt(b.Bytes(), 0)
-	if err != nil {
-		log.Crit("Failed to write BVs file", "file", em.config.PrevBlockVotesFile.Path, "err", err)
-	}
-}
-
 func (em *Emitter) readLastBlockVotes() *idx.Block {
 	if em.emittedBvsFile == nil {
 		return nil
@@ -64,16 +57,6 @@ func (em *Emitter) readLastBlockVotes() *idx.Block {
 	return &v
 }
 
-func (em *Emitter) writeLastEmittedEpochVote(e idx.Epoch) {
-	if em.emittedEvFile == nil {
-		return
-	}
-	_, err := em.emittedEvFile.WriteAt(e.Bytes(), 0)
-	if err != nil {
-		log.Crit("Failed to write BVs file", "file", em.config.PrevEpochVoteFile.Path, "err", err)
-	}
-}
-
 func (em *Emitter) readLastEpochVote() *idx.Epoch {
 	if em.emittedEvFile == nil {
 		return nil
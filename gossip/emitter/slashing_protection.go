@@ -0,0 +1,64 @@
+package emitter
+
+import (
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/Fantom-foundation/go-opera/inter"
+	"github.com/Fantom-foundation/go-opera/valkeystore/protection"
+)
+
+// lastEmittedEventID returns the last event signed by the validator
+func (em *Emitter) lastEmittedEventID() *hash.Event {
+	var last *hash.Event
+	if em.world.Protection != nil {
+		last = em.world.Protection.LastEvent(em.config.Validator.ID)
+	}
+	if legacy := em.readLastEmittedEventID(); legacy != nil && (last == nil || legacy.Epoch() > last.Epoch()) {
+		last = legacy
+	}
+	return last
+}
+
+// lastBlockVotes returns the last block voted by the validator
+func (em *Emitter) lastBlockVotes() *idx.Block {
+	var last *idx.Block
+	if em.world.Protection != nil {
+		last = em.world.Protection.LastBlockVote(em.config.Validator.ID)
+	}
+	if legacy := em.readLastBlockVotes(); legacy != nil && (last == nil || *legacy > *last) {
+		last = legacy
+	}
+	return last
+}
+
+// lastEpochVote returns the last epoch voted by the validator
+func (em *Emitter) lastEpochVote() *idx.Epoch {
+	var last *idx.Epoch
+	if em.world.Protection != nil {
+		last = em.world.Protection.LastEpochVote(em.config.Validator.ID)
+	}
+	if legacy := em.readLastEpochVote(); legacy != nil && (last == nil || *legacy > *last) {
+		last = legacy
+	}
+	return last
+}
+
+// approveSigning checks the event doesn't conflict with the signed events
+func (em *Emitter) approveSigning(e *inter.MutableEventPayload) error {
+	if em.world.Protection == nil {
+		return nil
+	}
+	return em.world.Protection.Approve(protection.NewRecord(e))
+}
+
+// recordEmitted writes the signed event to avoid doublesigning in future after a crash
+func (em *Emitter) recordEmitted(e *inter.EventPayload) {
+	if em.world.Protection == nil {
+		return
+	}
+	if err := em.world.Protection.Record(protection.NewRecord(e)); err != nil {
+		log.Crit("Failed to write slashing protection record", "file", em.config.SlashingProtectionFile.Path, "event", e.ID(), "err", err)
+	}
+}
@@ -1,6 +1,7 @@
 package emitter
 
 import (
+	"sort"
 	"time"
 
 	"github.com/Fantom-foundation/lachesis-base/common/bigendian"
@@ -181,7 +182,7 @@ func (em *Emitter) addTxs(e *inter.MutableEventPayload, sorted *types.Transactio
 			continue
 		}
 		// check transaction is not outdated
-		if !em.world.TxPool.Has(tx.Hash()) {
+		if !em.world.TxPool.Has(tx.Hash()) && (em.world.PrivateTxPool == nil || !em.world.PrivateTxPool.Has(tx.Hash())) {
 			txsSkippedOutdated.Inc(1)
 			sorted.Pop()
 			continue
@@ -193,3 +194,26 @@ func (em *Emitter) addTxs(e *inter.MutableEventPayload, sorted *types.Transactio
 		sorted.Shift()
 	}
 }
+
+// mergeTxs adds the private transactions into the pending ones.
+// A private transaction takes precedence over a pending one with the same sender and nonce.
+func mergeTxs(pending, private map[common.Address]types.Transactions) {
+	for from, txs := range private {
+		merged := make(types.Transactions, 0, len(pending[from])+len(txs))
+		merged = append(merged, txs...)
+		for _, tx := range pending[from] {
+			replaced := false
+			for _, ptx := range txs {
+				if ptx.Nonce() == tx.Nonce() {
+					replaced = true
+					break
+				}
+			}
+			if !replaced {
+				merged = append(merged, tx)
+			}
+		}
+		sort.Sort(types.TxByNonce(merged))
+		pending[from] = merged
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/Fantom-foundation/go-opera/inter/state"
 	"github.com/Fantom-foundation/go-opera/opera"
 	"github.com/Fantom-foundation/go-opera/valkeystore"
+	"github.com/Fantom-foundation/go-opera/valkeystore/protection"
 	"github.com/Fantom-foundation/go-opera/vecmt"
 )
 
@@ -47,9 +48,13 @@ type (
 	// World is an emitter's environment
 	World struct {
 		External
-		TxPool   TxPool
-		Signer   valkeystore.SignerI
-		TxSigner types.Signer
+		TxPool TxPool
+		// PrivateTxPool contains the transactions which aren't broadcast to peers, may be nil
+		PrivateTxPool TxPool
+		Signer        valkeystore.SignerI
+		TxSigner      types.Signer
+		// Protection is the slashing protection DB, may be nil
+		Protection *protection.DB
 	}
 )
 
@@ -288,6 +288,10 @@ func (b *EthAPIBackend) GetReceiptsByNumber(ctx context.Context, number rpc.Bloc
 		number = rpc.BlockNumber(header.Number.Uint64())
 	}
 
+	if err := b.svc.store.evm.CheckHistoryRetention(idx.Block(number)); err != nil {
+		return nil, err
+	}
+
 	block := b.state.GetBlock(common.Hash{}, uint64(number))
 	receipts := b.svc.store.evm.GetReceipts(idx.Block(number), b.signer, block.Hash, block.Transactions)
 	return receipts, nil
@@ -340,6 +344,11 @@ func (b *EthAPIBackend) SendTx(ctx context.Context, signedTx *types.Transaction)
 	return err
 }
 
+// SendPrivateTx adds the transaction into the private pool, it isn't broadcast to peers
+func (b *EthAPIBackend) SendPrivateTx(ctx context.Context, signedTx *types.Transaction) error {
+	return b.svc.privateTxPool.Add(signedTx)
+}
+
 func (b *EthAPIBackend) SubscribeLogsNotify(ch chan<- []*types.Log) notify.Subscription {
 	return b.svc.feed.SubscribeNewLogs(ch)
 }
@@ -348,10 +357,26 @@ func (b *EthAPIBackend) SubscribeNewBlockNotify(ch chan<- evmcore.ChainHeadNotif
 	return b.svc.feed.SubscribeNewBlock(ch)
 }
 
+func (b *EthAPIBackend) SubscribeNewEventNotify(ch chan<- *inter.EventPayload) notify.Subscription {
+	return b.svc.feed.SubscribeNewEvent(ch)
+}
+
+func (b *EthAPIBackend) SubscribeNewEpochNotify(ch chan<- *iblockproc.EpochState) notify.Subscription {
+	return b.svc.feed.SubscribeNewEpochState(ch)
+}
+
 func (b *EthAPIBackend) SubscribeNewTxsNotify(ch chan<- evmcore.NewTxsNotify) notify.Subscription {
 	return b.svc.txpool.SubscribeNewTxsNotify(ch)
 }
 
+func (b *EthAPIBackend) TxPoolHistory(hash common.Hash) []evmcore.TxEvent {
+	return b.svc.txpool.TxHistory(hash)
+}
+
+func (b *EthAPIBackend) SubscribeTxPoolEvents(ch chan<- evmcore.TxEvent) notify.Subscription {
+	return b.svc.txpool.SubscribeTxEvents(ch)
+}
+
 func (b *EthAPIBackend) GetPoolTransactions() (types.Transactions, error) {
 	pending, err := b.svc.txpool.Pending(false)
 	if err != nil {
@@ -379,8 +404,12 @@ func (b *EthAPIBackend) GetTransaction(ctx context.Context, txHash common.Hash)
 
 	position := b.svc.store.evm.GetTxPosition(txHash)
 	if position == nil {
+		// positions of the pruned txs are deleted, such txs can't be distinguished from the unknown ones
 		return nil, 0, 0, nil
 	}
+	if err := b.svc.store.evm.CheckHistoryRetention(position.Block); err != nil {
+		return nil, 0, 0, err
+	}
 
 	var tx *types.Transaction
 	if position.Event.IsZero() {
@@ -418,6 +447,7 @@ func (b *EthAPIBackend) Progress() ethapi.PeerProgress {
 	p2pProgress := b.svc.handler.myProgress()
 	highestP2pProgress := b.svc.handler.highestPeerProgress()
 	lastBlock := b.svc.store.GetBlock(p2pProgress.LastBlockIdx)
+	llrs := b.svc.store.GetLlrState()
 
 	return ethapi.PeerProgress{
 		CurrentEpoch:     p2pProgress.Epoch,
@@ -426,9 +456,16 @@ func (b *EthAPIBackend) Progress() ethapi.PeerProgress {
 		CurrentBlockTime: lastBlock.Time,
 		HighestBlock:     highestP2pProgress.LastBlockIdx,
 		HighestEpoch:     highestP2pProgress.Epoch,
+		SyncMode:         b.svc.handler.syncStatus.Stage().String(),
+		LlrEpoch:         llrs.LowestEpochToFill - 1,
+		LlrBlock:         llrs.LowestBlockToFill - 1,
 	}
 }
 
+func (b *EthAPIBackend) PrivateTxPoolContent() map[common.Address]types.Transactions {
+	return b.svc.privateTxPool.Content()
+}
+
 func (b *EthAPIBackend) TxPoolContentFrom(addr common.Address) (types.Transactions, types.Transactions) {
 	return b.svc.txpool.ContentFrom(addr)
 }
@@ -465,6 +502,11 @@ func (b *EthAPIBackend) RPCTxFeeCap() float64 {
 	return b.svc.config.RPCTxFeeCap
 }
 
+// CheckHistoryRetention returns an error if receipts and logs of the block are pruned.
+func (b *EthAPIBackend) CheckHistoryRetention(block idx.Block) error {
+	return b.svc.store.evm.CheckHistoryRetention(block)
+}
+
 func (b *EthAPIBackend) EvmLogIndex() topicsdb.Index {
 	return b.svc.store.evm.EvmLogs
 }
@@ -481,6 +523,11 @@ func (b *EthAPIBackend) MaxGasLimit() uint64 {
 	return b.state.MaxGasLimit()
 }
 
+// MaxBlockGasLimit returns the gas limit of a block
+func (b *EthAPIBackend) MaxBlockGasLimit() uint64 {
+	return b.svc.store.GetRules().Blocks.MaxBlockGas
+}
+
 func (b *EthAPIBackend) GetUptime(ctx context.Context, vid idx.ValidatorID) (*big.Int, error) {
 	// Note: loads bs and es atomically to avoid a race condition
 	bs, es := b.svc.store.GetBlockEpochState()
@@ -536,6 +583,26 @@ func (b *EthAPIBackend) CalcBlockExtApi() bool {
 	return b.svc.config.RPCBlockExt
 }
 
+// TraceIndexRange returns the range of blocks covered by the trace index
+func (b *EthAPIBackend) TraceIndexRange() (first idx.Block, last idx.Block, ok bool) {
+	return b.svc.store.evm.GetTraceIndexRange()
+}
+
+// ForEachIndexedTrace iterates over indexed calls from (or to, if callee is set) the address
+func (b *EthAPIBackend) ForEachIndexedTrace(ctx context.Context, addr common.Address, callee bool, from, to idx.Block, onTrace func(block idx.Block, txIndex uint32, traceAddress []uint32) bool) error {
+	dir := evmstore.TraceFrom
+	if callee {
+		dir = evmstore.TraceTo
+	}
+	b.svc.store.evm.ForEachTracePosition(addr, dir, from, to, func(pos evmstore.TracePosition) bool {
+		if ctx.Err() != nil {
+			return false
+		}
+		return onTrace(pos.Block, pos.TxIndex, pos.TraceAddress)
+	})
+	return ctx.Err()
+}
+
 func (b *EthAPIBackend) SealedEpochTiming(ctx context.Context) (start inter.Timestamp, end inter.Timestamp) {
 	es := b.svc.store.GetEpochState()
 	return es.PrevEpochStart, es.EpochStart
@@ -31,6 +31,14 @@ type (
 		DisableLogsIndexing bool
 		// Disables storing of txs positions
 		DisableTxHashesIndexing bool
+		// Enables indexing of transactions calls by from/to addresses
+		EnableTraceIndexing bool
+		// Number of recent blocks whose historic state may be queried, all blocks if zero.
+		// Only the queries are limited, the running node never prunes the archive.
+		// The archive may be pruned to a window of blocks only offline, by the sonictool.
+		ArchiveQueryWindow uint64
+		// Number of recent blocks whose receipts, txs positions and logs are kept, all blocks if zero
+		HistoryRetention uint64
 	}
 )
 
@@ -48,6 +48,9 @@ func (s *Store) GetRpcStateDb(blockNum *big.Int, stateRoot common.Hash) (state.S
 	if s.liveStateDb == nil {
 		return nil, fmt.Errorf("unable to get RPC StateDb - EvmStore is not open")
 	}
+	if err := s.checkArchiveQueryWindow(blockNum.Uint64()); err != nil {
+		return nil, err
+	}
 	stateDb, err := s.liveStateDb.GetArchiveStateDB(blockNum.Uint64())
 	if err != nil {
 		return nil, err
@@ -10,6 +10,8 @@ import (
 	carmen "github.com/Fantom-foundation/Carmen/go/state"
 	"github.com/Fantom-foundation/go-opera/opera/genesis"
 	"github.com/Fantom-foundation/go-opera/utils/adapters/kvdb2ethdb"
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
 	"github.com/Fantom-foundation/lachesis-base/kvdb/nokeyiserr"
 	"github.com/Fantom-foundation/lachesis-base/kvdb/pebble"
 	"github.com/Fantom-foundation/lachesis-base/kvdb/table"
@@ -40,6 +42,104 @@ func (s *Store) ImportLiveWorldState(liveReader io.Reader) error {
 	return nil
 }
 
+// ReplaceLiveWorldState replaces the live state by the live state snapshot.
+// The former live state is removed only after the snapshot is imported successfully.
+// Must be called before the first Open call.
+func (s *Store) ReplaceLiveWorldState(liveReader io.Reader) error {
+	return s.replaceLiveWorldState(liveReader, func(string) error {
+		return nil
+	})
+}
+
+// ReloadLiveWorldState replaces the live state of the open Store by the live state snapshot.
+// The snapshot is swapped with the live state only if its state root matches the given root,
+// otherwise the former live state is kept.
+// The caller must ensure no block is being processed during the call.
+func (s *Store) ReloadLiveWorldState(liveReader io.Reader, root hash.Hash) error {
+	if s.liveStateDb == nil {
+		return fmt.Errorf("unable to reload live state - EvmStore is not open")
+	}
+	if s.parameters.Archive != carmen.NoArchive {
+		return fmt.Errorf("live state may be reloaded only if the archive is disabled")
+	}
+	reopen := false
+	err := s.replaceLiveWorldState(liveReader, func(importDir string) error {
+		if err := s.checkImportedLiveStateHash(importDir, root); err != nil {
+			return err
+		}
+		if err := s.liveStateDb.Close(); err != nil {
+			return fmt.Errorf("failed to close State DB: %w", err)
+		}
+		s.carmenState = nil
+		s.liveStateDb = nil
+		reopen = true
+		return nil
+	})
+	if !reopen {
+		return err
+	}
+	// the State DB is reopened even if the swap has failed
+	carmenState, openErr := carmen.NewState(s.parameters)
+	if openErr != nil {
+		return fmt.Errorf("failed to create carmen state; %s", openErr)
+	}
+	s.carmenState = carmenState
+	s.liveStateDb = carmen.CreateStateDBUsing(s.carmenState)
+	return err
+}
+
+// checkImportedLiveStateHash checks the state root of the live state imported into the given directory
+func (s *Store) checkImportedLiveStateHash(importDir string, root hash.Hash) error {
+	params := s.parameters
+	params.Directory = importDir
+	params.Archive = carmen.NoArchive
+	importedState, err := carmen.NewState(params)
+	if err != nil {
+		return fmt.Errorf("failed to open imported LiveDB snapshot; %v", err)
+	}
+	stateHash, err := importedState.GetHash()
+	if closeErr := importedState.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get hash of imported LiveDB snapshot; %v", err)
+	}
+	if cc.Hash(root) != stateHash {
+		return fmt.Errorf("hash of the imported LiveDB snapshot is incorrect: expected: %x reproducedHash: %x", root, stateHash)
+	}
+	return nil
+}
+
+// replaceLiveWorldState imports the snapshot into a temporary dir and swaps it with the live state.
+// beforeSwap is called with the dir of the imported snapshot before the swap, the swap is aborted on error.
+func (s *Store) replaceLiveWorldState(liveReader io.Reader, beforeSwap func(importDir string) error) error {
+	liveDir := filepath.Join(s.parameters.Directory, "live")
+	tmpDir, err := os.MkdirTemp(s.parameters.Directory, "tmp-import-snapshot")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary dir for snapshot import; %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	tmpLiveDir := filepath.Join(tmpDir, "live")
+	if err := os.MkdirAll(tmpLiveDir, 0700); err != nil {
+		return fmt.Errorf("failed to create carmen dir during snapshot import; %v", err)
+	}
+	if err := io2.ImportLiveDb(io2.NewLog(), tmpLiveDir, liveReader); err != nil {
+		return fmt.Errorf("failed to import LiveDB snapshot; %v", err)
+	}
+	if err := beforeSwap(tmpDir); err != nil {
+		return err
+	}
+	// the former live state is removed together with the temporary dir
+	if err := os.Rename(liveDir, filepath.Join(tmpDir, "live-replaced")); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to move the former LiveDB; %v", err)
+	}
+	if err := os.Rename(tmpLiveDir, liveDir); err != nil {
+		return fmt.Errorf("failed to move the imported LiveDB; %v", err)
+	}
+	return nil
+}
+
 // ImportArchiveWorldState imports Fantom World State data from the archive state genesis section.
 // Must be called before the first Open call.
 func (s *Store) ImportArchiveWorldState(archiveReader io.Reader) error {
@@ -98,6 +198,25 @@ func (s *Store) ExportArchiveWorldState(ctx context.Context, out io.Writer) erro
 	return nil
 }
 
+// ExportBlockLiveWorldState exports Fantom World State data of the given block from the archive
+// in the live state genesis section format. Returns the state root of the exported data.
+func (s *Store) ExportBlockLiveWorldState(ctx context.Context, block idx.Block, out io.Writer) (hash.Hash, error) {
+	if s.carmenState == nil {
+		return hash.Hash{}, fmt.Errorf("unable to get archive state - EvmStore is not open")
+	}
+	archiveState, err := s.carmenState.GetArchiveState(uint64(block))
+	if err != nil {
+		return hash.Hash{}, fmt.Errorf("unable to get archive state: %w", err)
+	}
+	defer archiveState.Close()
+
+	root, err := archiveState.Export(ctx, out)
+	if err != nil {
+		return hash.Hash{}, fmt.Errorf("failed to export archive state of block %d; %v", block, err)
+	}
+	return hash.Hash(root), nil
+}
+
 func (s *Store) ImportLegacyEvmData(evmItems genesis.EvmItems, blockNum uint64, root common.Hash) error {
 	if err := s.Open(); err != nil {
 		return fmt.Errorf("failed to open EvmStore for legacy EVM data import; %v", err)
@@ -0,0 +1,177 @@
+package evmstore
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+
+	cc "github.com/Fantom-foundation/Carmen/go/common"
+	"github.com/Fantom-foundation/Carmen/go/common/amount"
+	carmen "github.com/Fantom-foundation/Carmen/go/state"
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/kvdb/memorydb"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/gossip/protocols/snapshots/snapstream"
+	"github.com/Fantom-foundation/go-opera/gossip/protocols/snapshots/snapstream/snapleecher"
+	"github.com/Fantom-foundation/go-opera/gossip/protocols/snapshots/snapstream/snapseeder"
+)
+
+const (
+	snapshotInfoRequestMsg = iota
+	snapshotInfoResponseMsg
+	snapshotChunkRequestMsg
+	snapshotChunkResponseMsg
+)
+
+func openCarmenStore(t *testing.T, dir string, archive carmen.ArchiveType) *Store {
+	cfg := LiteStoreConfig()
+	cfg.StateDb.Directory = dir
+	cfg.StateDb.Archive = archive
+	store := NewStore(memorydb.New(), cfg)
+	require.NoError(t, store.Open())
+	t.Cleanup(func() {
+		_ = store.Close()
+	})
+	return store
+}
+
+// serveSnapshots answers the snapshot requests of the leecher on the pipe
+func serveSnapshots(rw p2p.MsgReadWriter, seeder *snapseeder.Seeder) {
+	for {
+		msg, err := rw.ReadMsg()
+		if err != nil {
+			return
+		}
+		switch msg.Code {
+		case snapshotInfoRequestMsg:
+			_ = msg.Discard()
+			_ = p2p.Send(rw, snapshotInfoResponseMsg, snapstream.InfoResponse{Snapshots: seeder.Snapshots()})
+		case snapshotChunkRequestMsg:
+			var r snapstream.Request
+			if err := msg.Decode(&r); err != nil {
+				return
+			}
+			res, err := seeder.ReadChunk(r)
+			if err != nil {
+				return
+			}
+			_ = p2p.Send(rw, snapshotChunkResponseMsg, res)
+		}
+	}
+}
+
+// receiveSnapshots passes the seeder responses on the pipe to the leecher
+func receiveSnapshots(rw p2p.MsgReadWriter, peer string, leecher *snapleecher.Leecher) {
+	for {
+		msg, err := rw.ReadMsg()
+		if err != nil {
+			return
+		}
+		switch msg.Code {
+		case snapshotInfoResponseMsg:
+			var res snapstream.InfoResponse
+			if msg.Decode(&res) == nil {
+				_ = leecher.NotifyInfo(peer, res.Snapshots)
+			}
+		case snapshotChunkResponseMsg:
+			var res snapstream.Response
+			if msg.Decode(&res) == nil {
+				_ = leecher.NotifyChunk(peer, res)
+			}
+		}
+	}
+}
+
+// Tests that the live state made by the seeder from the archive is downloaded over a pipe
+// and replaces the live state of the leecher.
+func TestStateSnapshotSync(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+
+	// the state of the block 1 is made in the seeder archive
+	seederStore := openCarmenStore(t, filepath.Join(dir, "seeder-carmen"), carmen.S5Archive)
+	addr := cc.Address{1}
+	bulk := seederStore.liveStateDb.StartBulkLoad(1)
+	bulk.CreateAccount(addr)
+	bulk.SetBalance(addr, amount.New(1000))
+	bulk.SetNonce(addr, 7)
+	require.NoError(bulk.Close())
+	require.NoError(seederStore.carmenState.Flush())
+
+	seeder := snapseeder.New(snapseeder.Config{
+		Dir:          filepath.Join(dir, "seeder"),
+		Period:       1,
+		Keep:         1,
+		MaxChunkSize: 4 * 1024,
+	}, snapseeder.Callbacks{
+		Export: func(ctx context.Context, epoch idx.Epoch, w io.Writer) (hash.Hash, error) {
+			return seederStore.ExportBlockLiveWorldState(ctx, idx.Block(epoch), w)
+		},
+	})
+	seeder.Start()
+	defer seeder.Stop()
+	seeder.OnNewEpoch(1)
+	require.Eventually(func() bool {
+		return len(seeder.Snapshots()) == 1
+	}, 10*time.Second, 10*time.Millisecond)
+	root := seeder.Snapshots()[0].Root
+	require.NoError(seederStore.CheckLiveStateHash(1, root))
+
+	leecherStore := openCarmenStore(t, filepath.Join(dir, "leecher-carmen"), carmen.NoArchive)
+	emptyRoot := hash.Hash(leecherStore.liveStateDb.GetHash())
+	require.NotEqual(emptyRoot, root)
+
+	// the snapshot of other state root is refused, the former live state is kept
+	var snapshot bytes.Buffer
+	_, err := seederStore.ExportBlockLiveWorldState(context.Background(), 1, &snapshot)
+	require.NoError(err)
+	err = leecherStore.ReloadLiveWorldState(bytes.NewReader(snapshot.Bytes()), hash.Of([]byte("wrong")))
+	require.ErrorContains(err, "hash of the imported LiveDB snapshot is incorrect")
+	require.NoError(leecherStore.CheckLiveStateHash(0, emptyRoot))
+
+	local, remote := p2p.MsgPipe()
+	defer local.Close()
+	go serveSnapshots(remote, seeder)
+
+	cfg := snapleecher.LiteConfig()
+	cfg.Dir = filepath.Join(dir, "leecher")
+	imported := make(chan error, 1)
+	leecher := snapleecher.New(cfg, snapleecher.Callbacks{
+		RequestInfo: func(string) error {
+			return p2p.Send(local, snapshotInfoRequestMsg, snapstream.InfoRequest{})
+		},
+		RequestChunk: func(_ string, r snapstream.Request) error {
+			return p2p.Send(local, snapshotChunkRequestMsg, r)
+		},
+		VerifiedRoot: func(epoch idx.Epoch) (hash.Hash, bool) {
+			return root, epoch == 1
+		},
+		Import: func(info snapstream.Info, r io.Reader) error {
+			err := leecherStore.ReloadLiveWorldState(r, info.Root)
+			imported <- err
+			return err
+		},
+		Timeout: func(string) {},
+	})
+	go receiveSnapshots(local, "seeder", leecher)
+	require.NoError(leecher.RegisterPeer("seeder"))
+	leecher.Start()
+	defer leecher.Stop()
+
+	select {
+	case err := <-imported:
+		require.NoError(err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("snapshot isn't downloaded")
+	}
+	require.NoError(leecherStore.CheckLiveStateHash(1, root))
+	balance, err := leecherStore.carmenState.GetBalance(addr)
+	require.NoError(err)
+	require.Equal(amount.New(1000), balance)
+}
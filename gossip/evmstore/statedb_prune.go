@@ -0,0 +1,105 @@
+package evmstore
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	io2 "github.com/Fantom-foundation/Carmen/go/database/mpt/io"
+	carmen "github.com/Fantom-foundation/Carmen/go/state"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+
+	"github.com/Fantom-foundation/go-opera/inter/state"
+)
+
+// PruneArchive rebuilds the archive to contain only the state of blocks starting from the first one.
+// The state of the first block is taken from the current archive and the following blocks
+// have to be re-applied on top of it by the replay callback.
+// The Store must be closed during the call.
+func (s *Store) PruneArchive(ctx context.Context, first idx.Block, replay func(statedb state.StateDB) error) error {
+	if s.parameters.Archive != carmen.S5Archive {
+		return fmt.Errorf("archive pruning is supported only for the %s archive", carmen.S5Archive)
+	}
+	archiveDir := filepath.Join(s.parameters.Directory, "archive")
+	tmpDir, err := os.MkdirTemp(s.parameters.Directory, "tmp-prune-archive")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary dir for archive pruning: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// create both live and archive state of the first block
+	tmpLiveDir := filepath.Join(tmpDir, "live")
+	tmpArchiveDir := filepath.Join(tmpDir, "archive")
+	s.Log.Info("Exporting state of the first retained block", "block", first)
+	err = exportArchiveBlock(ctx, archiveDir, first, func(in io.Reader) error {
+		return io2.ImportLiveDb(io2.NewLog(), tmpLiveDir, in)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create live state of block %d: %w", first, err)
+	}
+	err = exportArchiveBlock(ctx, archiveDir, first, func(in io.Reader) error {
+		return io2.InitializeArchive(io2.NewLog(), tmpArchiveDir, in, uint64(first))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to initialize archive at block %d: %w", first, err)
+	}
+
+	// re-apply the following blocks
+	parameters := s.parameters
+	parameters.Directory = tmpDir
+	carmenState, err := carmen.NewState(parameters)
+	if err != nil {
+		return fmt.Errorf("failed to create carmen state; %w", err)
+	}
+	stateDb := carmen.CreateStateDBUsing(carmenState)
+	err = replay(CreateCarmenStateDb(stateDb))
+	if closeErr := stateDb.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to close pruned archive: %w", closeErr)
+	}
+	if err != nil {
+		return err
+	}
+
+	// replace the archive, the former one is removed together with the temporary dir
+	if err := os.Rename(archiveDir, filepath.Join(tmpDir, "archive-pruned")); err != nil {
+		return fmt.Errorf("failed to move the former archive: %w", err)
+	}
+	if err := os.Rename(tmpArchiveDir, archiveDir); err != nil {
+		return fmt.Errorf("failed to move the pruned archive: %w", err)
+	}
+	s.SetArchiveFirstBlock(first)
+	return nil
+}
+
+// exportArchiveBlock streams the state of the block from the archive into the import function
+func exportArchiveBlock(ctx context.Context, archiveDir string, block idx.Block, importFn func(io.Reader) error) error {
+	reader, writer := io.Pipe()
+	defer reader.Close()
+	bufReader := bufio.NewReaderSize(reader, 100*1024*1024) // 100 MiB
+	bufWriter := bufio.NewWriterSize(writer, 100*1024*1024) // 100 MiB
+
+	var exportErr error
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer writer.Close()
+		exportErr = io2.ExportBlockFromArchive(ctx, io2.NewLog(), archiveDir, bufWriter, uint64(block))
+		if exportErr == nil {
+			exportErr = bufWriter.Flush()
+		}
+	}()
+
+	err := importFn(bufReader)
+	if err != nil {
+		// unblock the exporter
+		reader.CloseWithError(err)
+	}
+	wg.Wait()
+	return errors.Join(err, exportErr)
+}
@@ -26,6 +26,13 @@ type Store struct {
 		Receipts    kvdb.Store `table:"r"`
 		TxPositions kvdb.Store `table:"x"`
 		Txs         kvdb.Store `table:"X"`
+
+		TraceIndex       kvdb.Store `table:"y"`
+		TraceIndexRange  kvdb.Store `table:"Y"`
+		TraceIndexBlocks kvdb.Store `table:"z"`
+
+		ArchiveInfo kvdb.Store `table:"A"`
+		HistoryInfo kvdb.Store `table:"H"`
 	}
 
 	EvmLogs  topicsdb.Index
@@ -0,0 +1,71 @@
+package evmstore
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+)
+
+// ErrStatePruned is returned if the requested historic state is pruned from the archive or out of the query window
+var ErrStatePruned = errors.New("state pruned")
+
+var archiveFirstBlockKey = []byte("f")
+
+// GetArchiveFirstBlock returns the first block kept in the pruned archive.
+// Returns zero if the archive was never pruned.
+func (s *Store) GetArchiveFirstBlock() idx.Block {
+	buf, err := s.table.ArchiveInfo.Get(archiveFirstBlockKey)
+	if err != nil {
+		s.Log.Crit("Failed to get key-value", "err", err)
+	}
+	if len(buf) != 8 {
+		return 0
+	}
+	return idx.BytesToBlock(buf)
+}
+
+// SetArchiveFirstBlock stores the first block kept in the pruned archive.
+func (s *Store) SetArchiveFirstBlock(n idx.Block) {
+	if err := s.table.ArchiveInfo.Put(archiveFirstBlockKey, n.Bytes()); err != nil {
+		s.Log.Crit("Failed to put key-value", "err", err)
+	}
+}
+
+// GetArchiveQueryWindow returns the number of recent blocks whose state may be queried.
+// Zero means the state of all the archived blocks may be queried.
+// The window limits only the queries, the archive itself isn't pruned.
+func (s *Store) GetArchiveQueryWindow() uint64 {
+	return s.cfg.ArchiveQueryWindow
+}
+
+// FirstQueryableBlock returns the first block whose state may be queried,
+// considering both the pruned archive and the configured query window.
+func (s *Store) FirstQueryableBlock() (idx.Block, error) {
+	first := s.GetArchiveFirstBlock()
+	if s.cfg.ArchiveQueryWindow == 0 {
+		return first, nil
+	}
+	height, empty, err := s.GetArchiveBlockHeight()
+	if err != nil {
+		return 0, err
+	}
+	if !empty && height >= s.cfg.ArchiveQueryWindow {
+		if windowStart := idx.Block(height - s.cfg.ArchiveQueryWindow + 1); windowStart > first {
+			first = windowStart
+		}
+	}
+	return first, nil
+}
+
+// checkArchiveQueryWindow returns ErrStatePruned if the state of the block may not be queried
+func (s *Store) checkArchiveQueryWindow(block uint64) error {
+	first, err := s.FirstQueryableBlock()
+	if err != nil {
+		return err
+	}
+	if block < uint64(first) {
+		return fmt.Errorf("%w: state of block %d is out of the archive query window (the first queryable block is %d)", ErrStatePruned, block, first)
+	}
+	return nil
+}
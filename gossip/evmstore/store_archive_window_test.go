@@ -0,0 +1,32 @@
+package evmstore
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/logger"
+)
+
+func TestStoreArchiveFirstBlock(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	store := cachedStore()
+	require.Equal(uint64(0), uint64(store.GetArchiveFirstBlock()))
+	require.NoError(store.checkArchiveQueryWindow(0))
+
+	store.SetArchiveFirstBlock(100)
+	require.Equal(uint64(100), uint64(store.GetArchiveFirstBlock()))
+
+	first, err := store.FirstQueryableBlock()
+	require.NoError(err)
+	require.Equal(uint64(100), uint64(first))
+
+	require.NoError(store.checkArchiveQueryWindow(100))
+	require.NoError(store.checkArchiveQueryWindow(150))
+	err = store.checkArchiveQueryWindow(99)
+	require.Error(err)
+	require.True(errors.Is(err, ErrStatePruned))
+}
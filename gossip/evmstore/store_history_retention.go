@@ -0,0 +1,47 @@
+package evmstore
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+)
+
+// ErrHistoryPruned is returned if the requested receipts or logs are out of the history retention window
+var ErrHistoryPruned = errors.New("history pruned")
+
+var historyFirstBlockKey = []byte("f")
+
+// GetHistoryFirstBlock returns the first block whose receipts, txs positions and logs are not pruned.
+// Returns zero if the history was never pruned.
+func (s *Store) GetHistoryFirstBlock() idx.Block {
+	buf, err := s.table.HistoryInfo.Get(historyFirstBlockKey)
+	if err != nil {
+		s.Log.Crit("Failed to get key-value", "err", err)
+	}
+	if len(buf) != 8 {
+		return 0
+	}
+	return idx.BytesToBlock(buf)
+}
+
+// SetHistoryFirstBlock stores the first block whose receipts, txs positions and logs are not pruned.
+func (s *Store) SetHistoryFirstBlock(n idx.Block) {
+	if err := s.table.HistoryInfo.Put(historyFirstBlockKey, n.Bytes()); err != nil {
+		s.Log.Crit("Failed to put key-value", "err", err)
+	}
+}
+
+// GetHistoryRetention returns the number of recent blocks whose receipts, txs positions and logs are kept.
+// Zero means the history is never pruned.
+func (s *Store) GetHistoryRetention() uint64 {
+	return s.cfg.HistoryRetention
+}
+
+// CheckHistoryRetention returns ErrHistoryPruned if receipts and logs of the block are pruned
+func (s *Store) CheckHistoryRetention(n idx.Block) error {
+	if first := s.GetHistoryFirstBlock(); n < first {
+		return fmt.Errorf("%w: block %d is out of the history retention window (the first retained block is %d)", ErrHistoryPruned, n, first)
+	}
+	return nil
+}
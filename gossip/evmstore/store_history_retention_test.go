@@ -0,0 +1,51 @@
+package evmstore
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/logger"
+)
+
+func TestStoreHistoryFirstBlock(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	store := cachedStore()
+	require.Equal(uint64(0), uint64(store.GetHistoryFirstBlock()))
+	require.NoError(store.CheckHistoryRetention(0))
+
+	store.SetHistoryFirstBlock(100)
+	require.Equal(uint64(100), uint64(store.GetHistoryFirstBlock()))
+
+	require.NoError(store.CheckHistoryRetention(100))
+	require.NoError(store.CheckHistoryRetention(150))
+	err := store.CheckHistoryRetention(99)
+	require.Error(err)
+	require.True(errors.Is(err, ErrHistoryPruned))
+}
+
+func TestStoreDeleteHistory(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	store := cachedStore()
+	txid := common.HexToHash("0x01")
+	store.SetTxPosition(txid, TxPosition{Block: 5})
+	store.SetReceipts(5, types.Receipts{{Status: types.ReceiptStatusSuccessful, Logs: []*types.Log{}}})
+
+	require.NotNil(store.GetTxPosition(txid))
+	receipts, _ := store.GetRawReceipts(5)
+	require.Len(receipts, 1)
+
+	store.DeleteTxPosition(txid)
+	store.DeleteReceipts(5)
+
+	require.Nil(store.GetTxPosition(txid))
+	receipts, _ = store.GetRawReceipts(5)
+	require.Nil(receipts)
+}
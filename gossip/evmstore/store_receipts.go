@@ -41,6 +41,16 @@ func (s *Store) SetRawReceipts(n idx.Block, receipts []*types.ReceiptForStorage)
 	return len(buf)
 }
 
+// DeleteReceipts removes transaction receipts of the block.
+func (s *Store) DeleteReceipts(n idx.Block) {
+	if err := s.table.Receipts.Delete(n.Bytes()); err != nil {
+		s.Log.Crit("Failed to delete key-value", "err", err)
+	}
+
+	// Remove from LRU cache.
+	s.cache.Receipts.Remove(n)
+}
+
 func (s *Store) GetRawReceiptsRLP(n idx.Block) rlp.RawValue {
 	buf, err := s.table.Receipts.Get(n.Bytes())
 	if err != nil {
@@ -0,0 +1,211 @@
+package evmstore
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+/*
+	Trace index maps addresses to positions of calls in transactions call trees.
+	Key: address (20 bytes) | direction (1 byte) | block (8 bytes) | tx index (4 bytes) | trace address (4 bytes per item)
+
+	Keys of each indexed transaction are also listed by the transaction position, to delete them with the pruned history.
+	Key: block (8 bytes) | tx index (4 bytes), value: RLP list of the trace index keys
+*/
+
+// TraceDirection distinguishes whether the indexed address was the caller or the callee
+type TraceDirection byte
+
+const (
+	TraceFrom TraceDirection = 'f'
+	TraceTo   TraceDirection = 't'
+)
+
+var traceIndexRangeKey = []byte("r")
+
+// TraceIndexRecord is a single call of a transaction call tree
+type TraceIndexRecord struct {
+	From         *common.Address
+	To           *common.Address
+	TraceAddress []uint32
+}
+
+// TracePosition points to a call in a transaction call tree
+type TracePosition struct {
+	Block        idx.Block
+	TxIndex      uint32
+	TraceAddress []uint32
+}
+
+// TraceIndexingEnabled returns true if the calls of new blocks should be indexed
+func (s *Store) TraceIndexingEnabled() bool {
+	return s.cfg.EnableTraceIndexing
+}
+
+func traceIndexKey(addr common.Address, dir TraceDirection, block idx.Block, txIndex uint32, traceAddress []uint32) []byte {
+	key := make([]byte, 0, common.AddressLength+1+8+4+4*len(traceAddress))
+	key = append(key, addr.Bytes()...)
+	key = append(key, byte(dir))
+	key = append(key, block.Bytes()...)
+	key = binary.BigEndian.AppendUint32(key, txIndex)
+	for _, n := range traceAddress {
+		key = binary.BigEndian.AppendUint32(key, n)
+	}
+	return key
+}
+
+// SetTraceIndex indexes calls of the transaction by their caller and callee addresses.
+func (s *Store) SetTraceIndex(block idx.Block, txIndex uint32, records []TraceIndexRecord) {
+	batch := s.table.TraceIndex.NewBatch()
+	defer batch.Reset()
+
+	keys := make([][]byte, 0, 2*len(records))
+	for _, rec := range records {
+		if rec.From != nil {
+			keys = append(keys, traceIndexKey(*rec.From, TraceFrom, block, txIndex, rec.TraceAddress))
+		}
+		if rec.To != nil {
+			keys = append(keys, traceIndexKey(*rec.To, TraceTo, block, txIndex, rec.TraceAddress))
+		}
+	}
+	for _, key := range keys {
+		if err := batch.Put(key, []byte{}); err != nil {
+			s.Log.Crit("Failed to put key-value", "err", err)
+		}
+	}
+	if err := batch.Write(); err != nil {
+		s.Log.Crit("Failed to write batch", "err", err)
+	}
+	if len(keys) != 0 {
+		s.rlp.Set(s.table.TraceIndexBlocks, binary.BigEndian.AppendUint32(block.Bytes(), txIndex), keys)
+	}
+}
+
+// DeleteTraceIndex deletes the indexed calls of the block transactions and excludes the block from the indexed range.
+// Blocks have to be deleted in the ascending order.
+func (s *Store) DeleteTraceIndex(block idx.Block) {
+	batch := s.table.TraceIndex.NewBatch()
+	defer batch.Reset()
+	blocksBatch := s.table.TraceIndexBlocks.NewBatch()
+	defer blocksBatch.Reset()
+
+	it := s.table.TraceIndexBlocks.NewIterator(block.Bytes(), nil)
+	defer it.Release()
+	for it.Next() {
+		var keys [][]byte
+		if err := rlp.DecodeBytes(it.Value(), &keys); err != nil {
+			s.Log.Crit("Failed to decode rlp", "err", err, "size", len(it.Value()))
+		}
+		for _, key := range keys {
+			if err := batch.Delete(key); err != nil {
+				s.Log.Crit("Failed to delete key-value", "err", err)
+			}
+		}
+		if err := blocksBatch.Delete(common.CopyBytes(it.Key())); err != nil {
+			s.Log.Crit("Failed to delete key-value", "err", err)
+		}
+	}
+	if it.Error() != nil {
+		s.Log.Crit("Failed to iterate trace index", "err", it.Error())
+	}
+	if err := batch.Write(); err != nil {
+		s.Log.Crit("Failed to write batch", "err", err)
+	}
+	if err := blocksBatch.Write(); err != nil {
+		s.Log.Crit("Failed to write batch", "err", err)
+	}
+
+	first, last, ok := s.GetTraceIndexRange()
+	if !ok || block < first {
+		return
+	}
+	if block >= last {
+		if err := s.table.TraceIndexRange.Delete(traceIndexRangeKey); err != nil {
+			s.Log.Crit("Failed to delete key-value", "err", err)
+		}
+		return
+	}
+	s.SetTraceIndexRange(block+1, last)
+}
+
+// ForEachTracePosition iterates over indexed calls of the address within the given blocks range.
+func (s *Store) ForEachTracePosition(addr common.Address, dir TraceDirection, from, to idx.Block, onPos func(TracePosition) bool) {
+	prefix := append(common.CopyBytes(addr.Bytes()), byte(dir))
+	it := s.table.TraceIndex.NewIterator(prefix, from.Bytes())
+	defer it.Release()
+	for it.Next() {
+		key := it.Key()[len(prefix):]
+		if len(key) < 12 || (len(key)-12)%4 != 0 {
+			s.Log.Crit("Corrupted trace index key", "key", it.Key())
+		}
+		pos := TracePosition{
+			Block:        idx.BytesToBlock(key[:8]),
+			TxIndex:      binary.BigEndian.Uint32(key[8:12]),
+			TraceAddress: make([]uint32, 0, (len(key)-12)/4),
+		}
+		if pos.Block > to {
+			return
+		}
+		for i := 12; i < len(key); i += 4 {
+			pos.TraceAddress = append(pos.TraceAddress, binary.BigEndian.Uint32(key[i:i+4]))
+		}
+		if !onPos(pos) {
+			return
+		}
+	}
+	if it.Error() != nil {
+		s.Log.Crit("Failed to iterate trace index", "err", it.Error())
+	}
+}
+
+// GetTraceIndexRange returns the range of blocks covered by the trace index.
+func (s *Store) GetTraceIndexRange() (first, last idx.Block, ok bool) {
+	buf, err := s.table.TraceIndexRange.Get(traceIndexRangeKey)
+	if err != nil {
+		s.Log.Crit("Failed to get key-value", "err", err)
+	}
+	if len(buf) != 16 {
+		return 0, 0, false
+	}
+	return idx.BytesToBlock(buf[:8]), idx.BytesToBlock(buf[8:]), true
+}
+
+// SetTraceIndexRange stores the range of blocks covered by the trace index.
+func (s *Store) SetTraceIndexRange(first, last idx.Block) {
+	if err := s.table.TraceIndexRange.Put(traceIndexRangeKey, append(first.Bytes(), last.Bytes()...)); err != nil {
+		s.Log.Crit("Failed to put key-value", "err", err)
+	}
+}
+
+// CheckTraceIndexRange returns an error if the blocks can't extend the already indexed range.
+// Only a continuous range is tracked, so the blocks have to overlap or be adjacent to it.
+func (s *Store) CheckTraceIndexRange(from, to idx.Block) error {
+	first, last, ok := s.GetTraceIndexRange()
+	if ok && (from > last+1 || to+1 < first) {
+		return fmt.Errorf("blocks %d-%d are not adjacent to the indexed blocks %d-%d", from, to, first, last)
+	}
+	return nil
+}
+
+// ExtendTraceIndexRange marks the given blocks as indexed.
+func (s *Store) ExtendTraceIndexRange(from, to idx.Block) error {
+	if err := s.CheckTraceIndexRange(from, to); err != nil {
+		return err
+	}
+	first, last, ok := s.GetTraceIndexRange()
+	if !ok {
+		first, last = from, to
+	}
+	if from < first {
+		first = from
+	}
+	if to > last {
+		last = to
+	}
+	s.SetTraceIndexRange(first, last)
+	return nil
+}
@@ -0,0 +1,125 @@
+package evmstore
+
+import (
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/logger"
+)
+
+func TestStoreTraceIndex(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	store := cachedStore()
+	a, b, c := common.Address{1}, common.Address{2}, common.Address{3}
+
+	store.SetTraceIndex(5, 0, []TraceIndexRecord{
+		{From: &a, To: &b, TraceAddress: []uint32{}},
+		{From: &b, To: &c, TraceAddress: []uint32{0}},
+		{From: &c, TraceAddress: []uint32{0, 1}},
+	})
+	store.SetTraceIndex(7, 2, []TraceIndexRecord{
+		{From: &a, To: &c, TraceAddress: []uint32{}},
+	})
+
+	collect := func(addr common.Address, dir TraceDirection, from, to idx.Block) []TracePosition {
+		res := make([]TracePosition, 0)
+		store.ForEachTracePosition(addr, dir, from, to, func(pos TracePosition) bool {
+			res = append(res, pos)
+			return true
+		})
+		return res
+	}
+
+	require.Equal([]TracePosition{
+		{Block: 5, TxIndex: 0, TraceAddress: []uint32{}},
+		{Block: 7, TxIndex: 2, TraceAddress: []uint32{}},
+	}, collect(a, TraceFrom, 0, 10))
+	require.Equal([]TracePosition{
+		{Block: 7, TxIndex: 2, TraceAddress: []uint32{}},
+	}, collect(a, TraceFrom, 6, 10))
+	require.Equal([]TracePosition{
+		{Block: 5, TxIndex: 0, TraceAddress: []uint32{0, 1}},
+	}, collect(c, TraceFrom, 0, 6))
+	require.Equal([]TracePosition{
+		{Block: 5, TxIndex: 0, TraceAddress: []uint32{0}},
+		{Block: 7, TxIndex: 2, TraceAddress: []uint32{}},
+	}, collect(c, TraceTo, 0, 10))
+	require.Empty(collect(a, TraceTo, 0, 10))
+}
+
+func TestStoreTraceIndexRange(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	store := cachedStore()
+	_, _, ok := store.GetTraceIndexRange()
+	require.False(ok)
+
+	check := func(expFirst, expLast idx.Block) {
+		first, last, ok := store.GetTraceIndexRange()
+		require.True(ok)
+		require.Equal(expFirst, first)
+		require.Equal(expLast, last)
+	}
+
+	require.NoError(store.ExtendTraceIndexRange(10, 10))
+	check(10, 10)
+	require.NoError(store.ExtendTraceIndexRange(11, 11))
+	check(10, 11)
+	require.NoError(store.ExtendTraceIndexRange(3, 9))
+	check(3, 11)
+	require.NoError(store.ExtendTraceIndexRange(5, 12))
+	check(3, 12)
+	// not adjacent blocks are rejected
+	require.Error(store.ExtendTraceIndexRange(1, 1))
+	require.Error(store.ExtendTraceIndexRange(20, 20))
+	check(3, 12)
+}
+
+func TestStoreDeleteTraceIndex(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	store := cachedStore()
+	a, b := common.Address{1}, common.Address{2}
+
+	store.SetTraceIndex(5, 0, []TraceIndexRecord{
+		{From: &a, To: &b, TraceAddress: []uint32{}},
+	})
+	store.SetTraceIndex(5, 1, []TraceIndexRecord{
+		{From: &b, TraceAddress: []uint32{}},
+	})
+	store.SetTraceIndex(6, 0, []TraceIndexRecord{
+		{From: &a, To: &b, TraceAddress: []uint32{}},
+	})
+	require.NoError(store.ExtendTraceIndexRange(5, 6))
+
+	count := func(addr common.Address, dir TraceDirection) int {
+		n := 0
+		store.ForEachTracePosition(addr, dir, 0, 10, func(TracePosition) bool {
+			n++
+			return true
+		})
+		return n
+	}
+
+	store.DeleteTraceIndex(5)
+	require.Equal(1, count(a, TraceFrom))
+	require.Equal(1, count(b, TraceTo))
+	require.Equal(0, count(b, TraceFrom))
+	first, last, ok := store.GetTraceIndexRange()
+	require.True(ok)
+	require.Equal(idx.Block(6), first)
+	require.Equal(idx.Block(6), last)
+
+	store.DeleteTraceIndex(6)
+	require.Equal(0, count(a, TraceFrom))
+	require.Equal(0, count(b, TraceTo))
+	_, _, ok = store.GetTraceIndexRange()
+	require.False(ok)
+}
@@ -29,6 +29,20 @@ func (s *Store) SetTxPosition(txid common.Hash, position TxPosition) {
 	s.cache.TxPositions.Add(txid.String(), &position, nominalSize)
 }
 
+// DeleteTxPosition removes transaction block and position.
+func (s *Store) DeleteTxPosition(txid common.Hash) {
+	if s.cfg.DisableTxHashesIndexing {
+		return
+	}
+
+	if err := s.table.TxPositions.Delete(txid.Bytes()); err != nil {
+		s.Log.Crit("Failed to delete key-value", "err", err)
+	}
+
+	// Remove from LRU cache.
+	s.cache.TxPositions.Remove(txid.String())
+}
+
 // GetTxPosition returns stored transaction block and position.
 func (s *Store) GetTxPosition(txid common.Hash) *TxPosition {
 	if s.cfg.DisableTxHashesIndexing {
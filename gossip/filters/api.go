@@ -255,6 +255,10 @@ func (api *PublicFilterAPI) NewHeads(ctx context.Context) (*rpc.Subscription, er
 }
 
 // Logs creates a subscription that fires for all new log that match the given filter criteria.
+// If fromBlock is a specific block and toBlock is "latest" or omitted, the stored matching
+// logs since fromBlock are sent first, followed by the new logs without gaps or duplicates.
+// The replayed blocks range is limited like the range of eth_getLogs. The history is replayed
+// before the subscription is returned, so the replay errors are returned instead of it.
 func (api *PublicFilterAPI) Logs(ctx context.Context, crit FilterCriteria) (*rpc.Subscription, error) {
 	notifier, supported := rpc.NotifierFromContext(ctx)
 	if !supported {
@@ -266,11 +270,55 @@ func (api *PublicFilterAPI) Logs(ctx context.Context, crit FilterCriteria) (*rpc
 		matchedLogs = make(chan []*types.Log)
 	)
 
+	if isResumable(crit) {
+		if err := api.backend.CheckHistoryRetention(idx.Block(crit.FromBlock.Uint64())); err != nil {
+			return nil, err
+		}
+	}
+
 	logsSub, err := api.events.SubscribeLogs(ethereum.FilterQuery(crit), matchedLogs)
 	if err != nil {
 		return nil, err
 	}
 
+	if isResumable(crit) {
+		from := idx.Block(crit.FromBlock.Uint64())
+		send := func(log *types.Log) {
+			_ = notifier.Notify(rpcSub.ID, log)
+		}
+		// the live subscription is already installed, so the logs after the head are delivered by it
+		head, err := api.replayHead(ctx, crit, from)
+		if err != nil {
+			logsSub.Unsubscribe()
+			return nil, err
+		}
+		// the replayed logs are buffered by the notifier until the subscription is returned,
+		// so the replay errors are returned to the client instead of the subscription
+		pending, err := api.replayLogs(ctx, crit, from, head, matchedLogs, send)
+		if err != nil {
+			logsSub.Unsubscribe()
+			return nil, err
+		}
+		next := from
+		if head >= from {
+			next = head + 1
+		}
+		go func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				select {
+				case <-rpcSub.Err(): // client send an unsubscribe request
+				case <-notifier.Closed(): // connection dropped
+				}
+				cancel()
+			}()
+			_ = followLogs(ctx, next, pending, matchedLogs, send)
+			logsSub.Unsubscribe()
+		}()
+		return rpcSub, nil
+	}
+
 	go func() {
 
 		for {
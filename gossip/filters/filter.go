@@ -48,6 +48,7 @@ type Backend interface {
 	SubscribeLogsNotify(ch chan<- []*types.Log) notify.Subscription
 
 	EvmLogIndex() topicsdb.Index
+	CheckHistoryRetention(block idx.Block) error
 
 	CalcBlockExtApi() bool
 }
@@ -143,6 +144,9 @@ func (f *Filter) indexedLogs(ctx context.Context, begin, end idx.Block) ([]*type
 	if end-begin > f.config.IndexedLogsBlockRangeLimit {
 		return nil, fmt.Errorf("too wide blocks range, the limit is %d", f.config.IndexedLogsBlockRangeLimit)
 	}
+	if err := f.backend.CheckHistoryRetention(begin); err != nil {
+		return nil, err
+	}
 
 	addresses := make([]common.Hash, len(f.addresses))
 	for i, addr := range f.addresses {
@@ -18,11 +18,13 @@ package filters
 
 import (
 	"context"
+	"errors"
 	"math/big"
 	"reflect"
 	"testing"
 	"time"
 
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
 	"github.com/Fantom-foundation/lachesis-base/kvdb/memorydb"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/rawdb"
@@ -147,6 +149,10 @@ func (b *testBackend) GetTxPosition(txid common.Hash) *evmstore.TxPosition {
 	return nil
 }
 
+func (b *testBackend) CheckHistoryRetention(block idx.Block) error {
+	return nil
+}
+
 func (b *testBackend) CalcBlockExtApi() bool {
 	return true
 }
@@ -392,3 +398,163 @@ func TestLogFilter(t *testing.T) {
 		}
 	}
 }
+
+// TestResumeLogs tests that a resumed logs subscription replays the stored logs
+// and then continues with the live logs without gaps or duplicates.
+func TestResumeLogs(t *testing.T) {
+	t.Parallel()
+
+	var (
+		backend = newTestBackend()
+		api     = NewPublicFilterAPI(backend, testConfig())
+
+		addr  = common.HexToAddress("0x1111111111111111111111111111111111111111")
+		other = common.HexToAddress("0x2222222222222222222222222222222222222222")
+	)
+	for i := uint64(0); i <= 3; i++ {
+		header := &types.Header{Number: new(big.Int).SetUint64(i), Difficulty: big.NewInt(0)}
+		rawdb.WriteHeader(backend.db, header)
+		rawdb.WriteCanonicalHash(backend.db, header.Hash(), i)
+		rawdb.WriteHeadBlockHash(backend.db, header.Hash())
+	}
+	stored := []*types.Log{
+		{Address: addr, BlockNumber: 1, Topics: []common.Hash{{1}}},
+		{Address: addr, BlockNumber: 2, Topics: []common.Hash{{2}}},
+		{Address: other, BlockNumber: 2, Topics: []common.Hash{{2}}},
+		{Address: addr, BlockNumber: 3, Topics: []common.Hash{{3}}},
+	}
+	backend.MustPushLogs(stored...)
+
+	var (
+		crit     = FilterCriteria{FromBlock: big.NewInt(2), Addresses: []common.Address{addr}}
+		live     = make(chan []*types.Log)
+		received = make(chan *types.Log, 16)
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if !isResumable(crit) {
+		t.Fatal("subscription from a specific block should be resumable")
+	}
+	go func() {
+		send := func(log *types.Log) {
+			received <- log
+		}
+		pending, err := api.replayLogs(ctx, crit, 2, 3, live, send)
+		if err != nil {
+			return
+		}
+		_ = followLogs(ctx, 4, pending, live, send)
+	}()
+
+	// the live logs of the replayed blocks are skipped
+	live <- []*types.Log{stored[3], {Address: addr, BlockNumber: 4, Topics: []common.Hash{{4}}}}
+	live <- []*types.Log{{Address: addr, BlockNumber: 5, Topics: []common.Hash{{5}}}}
+
+	for _, want := range []uint64{2, 3, 4, 5} {
+		select {
+		case log := <-received:
+			if log.BlockNumber != want || log.Topics[0] != (common.Hash{byte(want)}) {
+				t.Fatalf("log mismatch: have block %d, want %d", log.BlockNumber, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("log of block %d timeout", want)
+		}
+	}
+	select {
+	case log := <-received:
+		t.Fatalf("unexpected log of block %d", log.BlockNumber)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+// stalledLogIndex doesn't find the logs until the search is canceled.
+type stalledLogIndex struct {
+	topicsdb.Index
+}
+
+func (i stalledLogIndex) FindInBlocks(ctx context.Context, from, to idx.Block, pattern [][]common.Hash) ([]*types.Log, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+// failingLogIndex fails to find the logs.
+type failingLogIndex struct {
+	topicsdb.Index
+	err error
+}
+
+func (i failingLogIndex) FindInBlocks(ctx context.Context, from, to idx.Block, pattern [][]common.Hash) ([]*types.Log, error) {
+	return nil, i.err
+}
+
+// TestResumeLogsLimits tests that a resumed logs subscription respects the range limits,
+// and the replay fails if the live logs are pending for too long or the stored logs can't be read.
+func TestResumeLogsLimits(t *testing.T) {
+	t.Parallel()
+
+	var (
+		backend = newTestBackend()
+		cfg     = Config{IndexedLogsBlockRangeLimit: 10, UnindexedLogsBlockRangeLimit: 2}
+		api     = NewPublicFilterAPI(backend, cfg)
+		addr    = common.HexToAddress("0x1111111111111111111111111111111111111111")
+	)
+	for i := uint64(0); i <= 5; i++ {
+		header := &types.Header{Number: new(big.Int).SetUint64(i), Difficulty: big.NewInt(0)}
+		rawdb.WriteHeader(backend.db, header)
+		rawdb.WriteCanonicalHash(backend.db, header.Hash(), i)
+		rawdb.WriteHeadBlockHash(backend.db, header.Hash())
+	}
+	ctx := context.Background()
+
+	// the whole replayed range is limited, not only its batches
+	if _, err := api.replayHead(ctx, FilterCriteria{FromBlock: big.NewInt(1)}, 1); err == nil {
+		t.Fatal("too wide unindexed range isn't refused")
+	}
+	head, err := api.replayHead(ctx, FilterCriteria{FromBlock: big.NewInt(3)}, 3)
+	if err != nil || head != 5 {
+		t.Fatalf("replay head mismatch: have %d (%v), want 5", head, err)
+	}
+	crit := FilterCriteria{FromBlock: big.NewInt(1), Addresses: []common.Address{addr}}
+	if head, err = api.replayHead(ctx, crit, 1); err != nil || head != 5 {
+		t.Fatalf("replay head mismatch: have %d (%v), want 5", head, err)
+	}
+
+	// the live logs aren't buffered without a limit while the history is replayed
+	backend.logIndex = stalledLogIndex{backend.logIndex}
+	replayCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	live := make(chan []*types.Log)
+	errc := make(chan error, 1)
+	go func() {
+		_, err := api.replayLogs(replayCtx, crit, 1, 5, live, func(*types.Log) {})
+		errc <- err
+	}()
+	logs := make([]*types.Log, logsReplayPendingLimit/10)
+	for i := range logs {
+		logs[i] = &types.Log{Address: addr, BlockNumber: 6}
+	}
+	for i := 0; i <= 10; i++ {
+		select {
+		case live <- logs:
+		case err := <-errc:
+			t.Fatalf("unexpected replay error: %v", err)
+		case <-time.After(time.Second):
+			t.Fatal("live logs are blocked")
+		}
+	}
+	select {
+	case err := <-errc:
+		if err != errTooManyPendingLogs {
+			t.Fatalf("error mismatch: have %v, want %v", err, errTooManyPendingLogs)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("pending logs overflow isn't detected")
+	}
+
+	// the failure of the stored logs reading is returned by the replay
+	errIndex := errors.New("index failure")
+	backend.logIndex = failingLogIndex{backend.logIndex, errIndex}
+	if _, err := api.replayLogs(ctx, crit, 1, 5, live, func(*types.Log) {}); !errors.Is(err, errIndex) {
+		t.Fatalf("error mismatch: have %v, want %v", err, errIndex)
+	}
+}
@@ -263,3 +263,47 @@ func TestFilters(t *testing.T) {
 	}
 
 }
+
+// TestAddressFilter tests that the address only filters are served by the logs index,
+// so they aren't limited by the unindexed blocks range.
+func TestAddressFilter(t *testing.T) {
+	var (
+		backend = newTestBackend()
+		addr    = common.HexToAddress("0x1111111111111111111111111111111111111111")
+		other   = common.HexToAddress("0x2222222222222222222222222222222222222222")
+		cfg     = Config{
+			IndexedLogsBlockRangeLimit:   1000000,
+			UnindexedLogsBlockRangeLimit: 10,
+		}
+	)
+	head := &types.Header{Number: big.NewInt(100000), Difficulty: big.NewInt(0)}
+	rawdb.WriteHeader(backend.db, head)
+	rawdb.WriteHeadBlockHash(backend.db, head.Hash())
+
+	backend.MustPushLogs(
+		&types.Log{Address: addr, BlockNumber: 1},
+		&types.Log{Address: other, BlockNumber: 500, Topics: []common.Hash{{1}}},
+		&types.Log{Address: addr, BlockNumber: 90000, Topics: []common.Hash{{1}}},
+	)
+
+	logs, err := NewRangeFilter(backend, cfg, 0, -1, []common.Address{addr}, nil).Logs(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 2 || logs[0].BlockNumber != 1 || logs[1].BlockNumber != 90000 {
+		t.Fatalf("expected logs of blocks 1 and 90000, got %d logs", len(logs))
+	}
+
+	logs, err = NewRangeFilter(backend, cfg, 0, -1, []common.Address{addr, other}, [][]common.Hash{{{1}}}).Logs(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 2 || logs[0].BlockNumber != 500 || logs[1].BlockNumber != 90000 {
+		t.Fatalf("expected logs of blocks 500 and 90000, got %d logs", len(logs))
+	}
+
+	// the unindexed search is still limited
+	if _, err = NewRangeFilter(backend, cfg, 0, -1, nil, nil).Logs(context.Background()); err == nil {
+		t.Fatal("expected too wide blocks range error")
+	}
+}
@@ -0,0 +1,169 @@
+package filters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// logsReplayBatch is the maximum number of blocks replayed at once by a resumed logs subscription.
+	logsReplayBatch = 1000
+	// logsReplayLimit is the maximum number of stored logs replayed by a resumed logs subscription.
+	logsReplayLimit = 100000
+	// logsReplayPendingLimit is the maximum number of live logs buffered while the history is replayed.
+	logsReplayPendingLimit = 10000
+)
+
+var (
+	errTooManyReplayedLogs = fmt.Errorf("too many logs to replay, the limit is %d", logsReplayLimit)
+	errTooManyPendingLogs  = errors.New("too many new logs while the history is replayed")
+)
+
+// isResumable returns true if the logs subscription should replay the history
+// from a specific block before the live logs.
+func isResumable(crit FilterCriteria) bool {
+	if crit.BlockHash != nil || crit.FromBlock == nil || crit.FromBlock.Sign() < 0 {
+		return false
+	}
+	return crit.ToBlock == nil || crit.ToBlock.Int64() == rpc.LatestBlockNumber.Int64()
+}
+
+// replayRangeLimit returns the maximum blocks range replayed for the criteria.
+func (api *PublicFilterAPI) replayRangeLimit(crit FilterCriteria) idx.Block {
+	if isEmpty(crit.Topics) && len(crit.Addresses) == 0 {
+		return api.config.UnindexedLogsBlockRangeLimit
+	}
+	return api.config.IndexedLogsBlockRangeLimit
+}
+
+// replayHead returns the current head, whose logs and the logs of the blocks before it
+// are replayed by the resumed subscription. The live subscription must be installed
+// before the call, so the logs of the blocks after the head are delivered by it.
+func (api *PublicFilterAPI) replayHead(ctx context.Context, crit FilterCriteria, from idx.Block) (idx.Block, error) {
+	header, err := api.backend.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if err != nil {
+		return 0, err
+	}
+	if header == nil {
+		return 0, nil
+	}
+	head := idx.Block(header.Number.Uint64())
+	if limit := api.replayRangeLimit(crit); head >= from && head-from > limit {
+		return 0, fmt.Errorf("too wide blocks range, the limit is %d", limit)
+	}
+	return head, nil
+}
+
+// replayLogs sends the stored logs of the blocks from the given block up to the head,
+// and returns the live logs received meanwhile. The live logs are buffered not to block
+// the event system, and have to be sent by followLogs afterwards.
+func (api *PublicFilterAPI) replayLogs(ctx context.Context, crit FilterCriteria, from, head idx.Block, live <-chan []*types.Log, send func(*types.Log)) ([][]*types.Log, error) {
+	if head < from {
+		return nil, nil
+	}
+	history := make(chan []*types.Log)
+	errc := make(chan error, 1)
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	defer func() {
+		// the history reading is stopped before the return
+		cancel()
+		<-done
+	}()
+	go func() {
+		defer close(done)
+		defer close(history)
+		errc <- api.readHistory(ctx, crit, from, head, history)
+	}()
+
+	var (
+		pending      [][]*types.Log
+		pendingCount int
+		replayed     int
+	)
+	for history != nil {
+		select {
+		case logs, ok := <-history:
+			if !ok {
+				history = nil
+				break
+			}
+			replayed += len(logs)
+			if replayed > logsReplayLimit {
+				return nil, errTooManyReplayedLogs
+			}
+			for _, log := range logs {
+				send(log)
+			}
+		case logs := <-live:
+			pendingCount += len(logs)
+			if pendingCount > logsReplayPendingLimit {
+				return nil, errTooManyPendingLogs
+			}
+			pending = append(pending, logs)
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if err := <-errc; err != nil {
+		return nil, err
+	}
+	return pending, nil
+}
+
+// followLogs sends the pending and the live logs of the blocks starting from the given one,
+// until the context is done. The blocks are final, so skipping the live logs of the replayed
+// blocks leaves neither gaps nor duplicates.
+func followLogs(ctx context.Context, next idx.Block, pending [][]*types.Log, live <-chan []*types.Log, send func(*types.Log)) error {
+	for _, logs := range pending {
+		sendLiveLogs(logs, next, send)
+	}
+	for {
+		select {
+		case logs := <-live:
+			sendLiveLogs(logs, next, send)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func sendLiveLogs(logs []*types.Log, next idx.Block, send func(*types.Log)) {
+	for _, log := range logs {
+		if log.BlockNumber >= uint64(next) {
+			send(log)
+		}
+	}
+}
+
+// readHistory sends the stored logs of the blocks range in batches.
+func (api *PublicFilterAPI) readHistory(ctx context.Context, crit FilterCriteria, from, to idx.Block, history chan<- []*types.Log) error {
+	batch := api.replayRangeLimit(crit)
+	if batch > logsReplayBatch {
+		batch = logsReplayBatch
+	}
+	for begin := from; begin <= to; {
+		end := to
+		if end-begin > batch {
+			end = begin + batch
+		}
+		logs, err := NewRangeFilter(api.backend, api.config, int64(begin), int64(end), crit.Addresses, crit.Topics).Logs(ctx)
+		if err != nil {
+			return err
+		}
+		if len(logs) != 0 {
+			select {
+			case history <- logs:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		begin = end + 1
+	}
+	return nil
+}
@@ -20,11 +20,13 @@ import (
 	"fmt"
 	"math/big"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/Fantom-foundation/lachesis-base/inter/idx"
 	"github.com/Fantom-foundation/lachesis-base/utils/piecefunc"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/core/types"
 	lru "github.com/hashicorp/golang-lru"
@@ -50,6 +52,12 @@ type Config struct {
 	MaxGasPrice      *big.Int `toml:",omitempty"`
 	MinGasPrice      *big.Int `toml:",omitempty"`
 	DefaultCertainty uint64   `toml:",omitempty"`
+	// Strategy is one of ModelStrategy, PercentileStrategy or BlendStrategy
+	Strategy string `toml:",omitempty"`
+	// PercentileBlocks is the number of recent blocks whose tips are used by the percentile strategy
+	PercentileBlocks idx.Block `toml:",omitempty"`
+	// BlendRatio is the weight of the percentile strategy in the blend strategy
+	BlendRatio uint64 `toml:",omitempty"`
 }
 
 type Reader interface {
@@ -59,6 +67,7 @@ type Reader interface {
 	GetPendingRules() opera.Rules
 	PendingTxs() map[common.Address]types.Transactions
 	MinGasTip() *big.Int
+	GetBlockTxs(n idx.Block) types.Transactions
 }
 
 type tipCache struct {
@@ -83,6 +92,10 @@ type Oracle struct {
 
 	eCache effectiveMinGasPriceCache
 	tCache *lru.Cache
+	bCache *lru.Cache
+
+	strategy strategy
+	stats    atomic.Value
 
 	wg   sync.WaitGroup
 	quit chan struct{}
@@ -110,11 +123,25 @@ func NewOracle(params Config) *Oracle {
 	params.MaxGasPrice = sanitizeBigInt(params.MaxGasPrice, nil, nil, DefaultMaxGasPrice, "MaxGasPrice")
 	params.MinGasPrice = sanitizeBigInt(params.MinGasPrice, nil, nil, new(big.Int), "MinGasPrice")
 	params.DefaultCertainty = sanitizeBigInt(new(big.Int).SetUint64(params.DefaultCertainty), big.NewInt(0), DecimalUnitBn, big.NewInt(DecimalUnit/2), "DefaultCertainty").Uint64()
+	params.PercentileBlocks = idx.Block(sanitizeBigInt(new(big.Int).SetUint64(uint64(params.PercentileBlocks)), big.NewInt(1), big.NewInt(MaxPercentileBlocks), big.NewInt(DefaultPercentileBlocks), "PercentileBlocks").Uint64())
+	params.BlendRatio = sanitizeBigInt(new(big.Int).SetUint64(params.BlendRatio), big.NewInt(0), DecimalUnitBn, big.NewInt(0), "BlendRatio").Uint64()
+	if params.Strategy == "" {
+		params.Strategy = ModelStrategy
+	}
+	s, ok := strategies[params.Strategy]
+	if !ok {
+		log.Warn("Sanitizing invalid parameter Strategy of gasprice oracle", "provided", params.Strategy, "updated", ModelStrategy)
+		params.Strategy = ModelStrategy
+		s = strategies[ModelStrategy]
+	}
 	tCache, _ := lru.New(100)
+	bCache, _ := lru.New(MaxPercentileBlocks)
 	return &Oracle{
-		cfg:    params,
-		tCache: tCache,
-		quit:   make(chan struct{}),
+		cfg:      params,
+		tCache:   tCache,
+		bCache:   bCache,
+		strategy: s,
+		quit:     make(chan struct{}),
 	}
 }
 
@@ -137,10 +164,15 @@ func (gpo *Oracle) suggestTip(certainty uint64) *big.Int {
 	pendingMinPrice := gpo.backend.GetPendingRules().Economy.MinGasPrice
 	adjustedMinGasPrice := math.BigMax(minPrice, pendingMinPrice)
 
-	reactive := gpo.reactiveGasPrice(certainty)
-	constructive := gpo.constructiveGasPrice(gpo.c.totalGas(), 0.005*DecimalUnit+certainty/25, adjustedMinGasPrice)
+	stats := &Stats{
+		Strategy:    gpo.cfg.Strategy,
+		Certainty:   certainty,
+		MinGasPrice: (*hexutil.Big)(minPrice),
+		Time:        time.Now(),
+	}
+	defer gpo.stats.Store(stats)
 
-	combined := math.BigMax(reactive, constructive)
+	combined := gpo.strategy(gpo, certainty, minPrice, adjustedMinGasPrice, stats)
 	if combined.Cmp(gpo.cfg.MinGasPrice) < 0 {
 		combined = gpo.cfg.MinGasPrice
 	}
@@ -148,14 +180,23 @@ func (gpo *Oracle) suggestTip(certainty uint64) *big.Int {
 		combined = gpo.cfg.MaxGasPrice
 	}
 
+	stats.Price = (*hexutil.Big)(combined)
+
 	tip := new(big.Int).Sub(combined, minPrice)
 	minGasTip := gpo.backend.MinGasTip()
 	if tip.Cmp(minGasTip) < 0 {
-		return minGasTip
+		tip = minGasTip
 	}
+	stats.Tip = (*hexutil.Big)(tip)
 	return tip
 }
 
+// Stats returns the inputs and the result of the last computed tip suggestion, nil if there was no suggestion
+func (gpo *Oracle) Stats() *Stats {
+	stats, _ := gpo.stats.Load().(*Stats)
+	return stats
+}
+
 // SuggestTip returns a tip cap so that newly created transaction can have a
 // very high chance to be included in the following blocks.
 //
@@ -25,6 +25,7 @@ type TestBackend struct {
 	rules             opera.Rules
 	pendingRules      opera.Rules
 	pendingTxs        []fakeTx
+	blockTxs          map[idx.Block][]fakeTx
 }
 
 func (t TestBackend) GetLatestBlockIndex() idx.Block {
@@ -62,6 +63,19 @@ func (t TestBackend) MinGasTip() *big.Int {
 	return big.NewInt(0)
 }
 
+func (t TestBackend) GetBlockTxs(n idx.Block) types.Transactions {
+	txs := make(types.Transactions, 0, len(t.blockTxs[n]))
+	for i, tx := range t.blockTxs[n] {
+		txs = append(txs, types.NewTx(&types.DynamicFeeTx{
+			Nonce:     uint64(i),
+			GasTipCap: tx.tip,
+			GasFeeCap: tx.cap,
+			Gas:       tx.gas,
+		}))
+	}
+	return txs
+}
+
 func TestOracle_EffectiveMinGasPrice(t *testing.T) {
 	backend := &TestBackend{
 		block:             1,
@@ -0,0 +1,120 @@
+package gasprice
+
+import (
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+)
+
+const (
+	// ModelStrategy combines the constructive price from the free gas power
+	// and the reactive price from the txpool content
+	ModelStrategy = "model"
+	// PercentileStrategy takes a percentile of the tips included into the recent blocks
+	PercentileStrategy = "percentile"
+	// BlendStrategy is a weighted average of the model and percentile strategies
+	BlendStrategy = "blend"
+
+	DefaultPercentileBlocks = 20
+	MaxPercentileBlocks     = 1024
+)
+
+// Stats are the inputs and the result of the last computed tip suggestion
+type Stats struct {
+	Strategy     string       `json:"strategy"`
+	Certainty    uint64       `json:"certainty"`
+	MinGasPrice  *hexutil.Big `json:"minGasPrice"`
+	Reactive     *hexutil.Big `json:"reactive,omitempty"`
+	Constructive *hexutil.Big `json:"constructive,omitempty"`
+	Percentile   *hexutil.Big `json:"percentile,omitempty"`
+	Blocks       uint64       `json:"blocks,omitempty"`
+	Txs          uint64       `json:"txs,omitempty"`
+	Price        *hexutil.Big `json:"price"`
+	Tip          *hexutil.Big `json:"tip"`
+	Time         time.Time    `json:"time"`
+}
+
+// strategy computes a gas price including the minimum gas price
+type strategy func(gpo *Oracle, certainty uint64, minPrice, adjustedMinPrice *big.Int, stats *Stats) *big.Int
+
+var strategies = map[string]strategy{
+	ModelStrategy:      modelGasPrice,
+	PercentileStrategy: percentileGasPrice,
+	BlendStrategy:      blendGasPrice,
+}
+
+func modelGasPrice(gpo *Oracle, certainty uint64, _, adjustedMinPrice *big.Int, stats *Stats) *big.Int {
+	reactive := gpo.reactiveGasPrice(certainty)
+	constructive := gpo.constructiveGasPrice(gpo.c.totalGas(), 0.005*DecimalUnit+certainty/25, adjustedMinPrice)
+	stats.Reactive = (*hexutil.Big)(reactive)
+	stats.Constructive = (*hexutil.Big)(constructive)
+	return math.BigMax(reactive, constructive)
+}
+
+func percentileGasPrice(gpo *Oracle, certainty uint64, minPrice, _ *big.Int, stats *Stats) *big.Int {
+	tip := gpo.percentileTip(certainty, minPrice, stats)
+	stats.Percentile = (*hexutil.Big)(tip)
+	return new(big.Int).Add(minPrice, tip)
+}
+
+func blendGasPrice(gpo *Oracle, certainty uint64, minPrice, adjustedMinPrice *big.Int, stats *Stats) *big.Int {
+	model := modelGasPrice(gpo, certainty, minPrice, adjustedMinPrice, stats)
+	percentile := percentileGasPrice(gpo, certainty, minPrice, adjustedMinPrice, stats)
+
+	ratio := new(big.Int).SetUint64(gpo.cfg.BlendRatio)
+	price := new(big.Int).Mul(percentile, ratio)
+	price.Add(price, new(big.Int).Mul(model, new(big.Int).Sub(DecimalUnitBn, ratio)))
+	return price.Div(price, DecimalUnitBn)
+}
+
+// cachedTips are the block tips calculated against the minimum gas price
+type cachedTips struct {
+	minPrice *big.Int
+	tips     []*big.Int
+}
+
+// blockTips returns the sorted effective tips of the block transactions
+func (gpo *Oracle) blockTips(n idx.Block, minPrice *big.Int) []*big.Int {
+	if cached, ok := gpo.bCache.Get(n); ok && cached.(cachedTips).minPrice.Cmp(minPrice) == 0 {
+		return cached.(cachedTips).tips
+	}
+	txs := gpo.backend.GetBlockTxs(n)
+	tips := make([]*big.Int, 0, len(txs))
+	for _, tx := range txs {
+		tip, err := tx.EffectiveGasTip(minPrice)
+		if err != nil {
+			tip = new(big.Int)
+		}
+		tips = append(tips, tip)
+	}
+	sort.Slice(tips, func(i, j int) bool {
+		return tips[i].Cmp(tips[j]) < 0
+	})
+	gpo.bCache.Add(n, cachedTips{minPrice, tips})
+	return tips
+}
+
+// percentileTip returns the tip at the certainty percentile of the tips included into the recent blocks.
+// The tips are calculated against the current minimum gas price.
+func (gpo *Oracle) percentileTip(certainty uint64, minPrice *big.Int, stats *Stats) *big.Int {
+	head := gpo.backend.GetLatestBlockIndex()
+
+	tips := make([]*big.Int, 0)
+	for n := head; n > 0 && uint64(head-n) < uint64(gpo.cfg.PercentileBlocks); n-- {
+		tips = append(tips, gpo.blockTips(n, minPrice)...)
+		stats.Blocks++
+	}
+	stats.Txs = uint64(len(tips))
+	if len(tips) == 0 {
+		return new(big.Int)
+	}
+	sort.Slice(tips, func(i, j int) bool {
+		return tips[i].Cmp(tips[j]) < 0
+	})
+	i := uint64(len(tips)-1) * certainty / DecimalUnit
+	return new(big.Int).Set(tips[i])
+}
@@ -0,0 +1,104 @@
+package gasprice
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/opera"
+)
+
+func TestOracle_percentileStrategy(t *testing.T) {
+	backend := &TestBackend{
+		block:        3,
+		rules:        opera.FakeNetRules(),
+		pendingRules: opera.FakeNetRules(),
+		blockTxs: map[idx.Block][]fakeTx{
+			1: {{gas: 21000, tip: big.NewInt(1000), cap: big.NewInt(2000)}},
+			2: {{gas: 21000, tip: big.NewInt(30), cap: big.NewInt(115)}},
+			3: {
+				{gas: 21000, tip: big.NewInt(20), cap: big.NewInt(1000)},
+				{gas: 21000, tip: big.NewInt(10), cap: big.NewInt(1000)},
+			},
+		},
+	}
+	backend.rules.Economy.MinGasPrice = big.NewInt(100)
+
+	gpo := NewOracle(Config{
+		Strategy:         PercentileStrategy,
+		PercentileBlocks: 2,
+	})
+	gpo.backend = backend
+	gpo.cfg.MaxGasPrice = math.MaxBig256
+	gpo.cfg.MinGasPrice = new(big.Int)
+	require.Nil(t, gpo.Stats())
+
+	// the tip of the second block is capped by the fee cap
+	require.Equal(t, "10", gpo.suggestTip(0).String())
+	require.Equal(t, "15", gpo.suggestTip(0.5*DecimalUnit).String())
+	require.Equal(t, "20", gpo.suggestTip(DecimalUnit).String())
+
+	stats := gpo.Stats()
+	require.Equal(t, PercentileStrategy, stats.Strategy)
+	require.Equal(t, uint64(DecimalUnit), stats.Certainty)
+	require.Equal(t, uint64(2), stats.Blocks)
+	require.Equal(t, uint64(3), stats.Txs)
+	require.Equal(t, "20", stats.Percentile.ToInt().String())
+	require.Equal(t, "120", stats.Price.ToInt().String())
+	require.Equal(t, "20", stats.Tip.ToInt().String())
+	require.Nil(t, stats.Reactive)
+
+	// the cached tips are recalculated when the minimum gas price changes
+	backend.rules.Economy.MinGasPrice = big.NewInt(110)
+	require.Equal(t, "5", gpo.suggestTip(0).String())
+
+	// no transactions in the recent blocks
+	backend.block = 5
+	require.Equal(t, "0", gpo.suggestTip(DecimalUnit).String())
+	require.Equal(t, uint64(0), gpo.Stats().Txs)
+}
+
+func TestOracle_blendStrategy(t *testing.T) {
+	backend := &TestBackend{
+		block:        1,
+		rules:        opera.FakeNetRules(),
+		pendingRules: opera.FakeNetRules(),
+		blockTxs: map[idx.Block][]fakeTx{
+			1: {{gas: 21000, tip: big.NewInt(1e9), cap: big.NewInt(1e12)}},
+		},
+	}
+
+	gpo := NewOracle(Config{
+		Strategy:   BlendStrategy,
+		BlendRatio: 0.25 * DecimalUnit,
+	})
+	gpo.backend = backend
+	gpo.cfg.MaxGasPrice = math.MaxBig256
+	gpo.cfg.MinGasPrice = new(big.Int)
+
+	gpo.suggestTip(DecimalUnit)
+	stats := gpo.Stats()
+	require.Equal(t, BlendStrategy, stats.Strategy)
+
+	model := math.BigMax(stats.Reactive.ToInt(), stats.Constructive.ToInt())
+	percentile := new(big.Int).Add(stats.MinGasPrice.ToInt(), stats.Percentile.ToInt())
+	require.Equal(t, "1000000000", stats.Percentile.ToInt().String())
+
+	expected := new(big.Int).Mul(model, big.NewInt(3))
+	expected.Add(expected, percentile)
+	expected.Div(expected, big.NewInt(4))
+	require.Equal(t, expected.String(), stats.Price.ToInt().String())
+}
+
+func TestOracle_invalidStrategy(t *testing.T) {
+	gpo := NewOracle(Config{Strategy: "unknown"})
+	require.Equal(t, ModelStrategy, gpo.cfg.Strategy)
+	require.Equal(t, idx.Block(DefaultPercentileBlocks), gpo.cfg.PercentileBlocks)
+	require.Equal(t, uint64(0), gpo.cfg.BlendRatio)
+
+	gpo = NewOracle(Config{Strategy: BlendStrategy, BlendRatio: DecimalUnit + 1})
+	require.Equal(t, uint64(DecimalUnit), gpo.cfg.BlendRatio)
+}
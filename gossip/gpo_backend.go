@@ -46,6 +46,14 @@ func (b *GPOBackend) MinGasTip() *big.Int {
 	return b.txpool.GasPrice()
 }
 
+func (b *GPOBackend) GetBlockTxs(n idx.Block) types.Transactions {
+	block := b.store.GetBlock(n)
+	if block == nil {
+		return nil
+	}
+	return b.store.GetBlockTxs(n, block)
+}
+
 // TotalGasPowerLeft returns a total amount of obtained gas power by the validators, according to the latest events from each validator
 func (b *GPOBackend) TotalGasPowerLeft() uint64 {
 	bs, es := b.store.GetBlockEpochState()
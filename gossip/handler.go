@@ -1,10 +1,12 @@
 package gossip
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/p2p/enode"
+	"io"
 	"math"
 	"math/rand"
 	"strings"
@@ -18,6 +20,7 @@ import (
 	"github.com/Fantom-foundation/go-opera/eventcheck/heavycheck"
 	"github.com/Fantom-foundation/go-opera/eventcheck/parentlesscheck"
 	"github.com/Fantom-foundation/go-opera/evmcore"
+	"github.com/Fantom-foundation/go-opera/gossip/peerscore"
 	"github.com/Fantom-foundation/go-opera/gossip/protocols/blockrecords/brprocessor"
 	"github.com/Fantom-foundation/go-opera/gossip/protocols/blockrecords/brstream"
 	"github.com/Fantom-foundation/go-opera/gossip/protocols/blockrecords/brstream/brstreamleecher"
@@ -33,6 +36,9 @@ import (
 	"github.com/Fantom-foundation/go-opera/gossip/protocols/epochpacks/epstream"
 	"github.com/Fantom-foundation/go-opera/gossip/protocols/epochpacks/epstream/epstreamleecher"
 	"github.com/Fantom-foundation/go-opera/gossip/protocols/epochpacks/epstream/epstreamseeder"
+	"github.com/Fantom-foundation/go-opera/gossip/protocols/snapshots/snapstream"
+	"github.com/Fantom-foundation/go-opera/gossip/protocols/snapshots/snapstream/snapleecher"
+	"github.com/Fantom-foundation/go-opera/gossip/protocols/snapshots/snapstream/snapseeder"
 	"github.com/Fantom-foundation/go-opera/inter"
 	"github.com/Fantom-foundation/go-opera/inter/ibr"
 	"github.com/Fantom-foundation/go-opera/inter/ier"
@@ -93,6 +99,7 @@ type processCallback struct {
 	BR               func(ibr.LlrIdxFullBlockRecord) error
 	EV               func(inter.LlrSignedEpochVote) error
 	ER               func(ier.LlrIdxFullEpochRecord) error
+	Snapshot         func(root hash.Hash, r io.Reader) error
 }
 
 // handlerConfig is the collection of initialization parameters to create a full
@@ -138,6 +145,12 @@ type handler struct {
 	epSeeder    *epstreamseeder.Seeder
 	epProcessor *epprocessor.Processor
 
+	peerScores *peerscore.Scorer
+
+	snapLeecher  *snapleecher.Leecher
+	snapSeeder   *snapseeder.Seeder
+	snapshotSync bool
+
 	process processCallback
 
 	txFetcher *itemsfetcher.Fetcher
@@ -189,6 +202,7 @@ func newHandler(
 		process:              c.process,
 		checkers:             c.checkers,
 		peers:                newPeerSet(),
+		peerScores:           peerscore.New(c.config.Protocol.PeerScore, c.s.table.PeerBans),
 		engineMu:             c.engineMu,
 		txsyncCh:             make(chan *txsync),
 		quitSync:             make(chan struct{}),
@@ -226,7 +240,7 @@ func newHandler(
 			return p.RequestEventsStream(r)
 		},
 		Suspend: func(_ string) bool {
-			return h.dagFetcher.Overloaded() || h.dagProcessor.Overloaded()
+			return !h.syncStatus.AcceptEvents() || h.dagFetcher.Overloaded() || h.dagProcessor.Overloaded()
 		},
 		PeerEpoch: func(peer string) idx.Epoch {
 			p := h.peers.Peer(peer)
@@ -235,6 +249,12 @@ func newHandler(
 			}
 			return p.GetProgress().Epoch
 		},
+		Timeout: func(peer string) {
+			h.recordPeer(peer, peerscore.Timeout)
+		},
+		Slow: func(peer string) {
+			h.recordPeer(peer, peerscore.SlowResponse)
+		},
 	})
 	h.dagSeeder = dagstreamseeder.New(h.config.Protocol.DagStreamSeeder, dagstreamseeder.Callbacks{
 		ForEachEvent: c.s.ForEachEventRLP,
@@ -355,18 +375,91 @@ func newHandler(
 		Iterate: h.store.IterateEpochPacksRLP,
 	})
 
+	h.snapLeecher = snapleecher.New(h.config.Protocol.SnapLeecher, snapleecher.Callbacks{
+		RequestInfo: func(peer string) error {
+			p := h.peers.Peer(peer)
+			if p == nil {
+				return errNotRegistered
+			}
+			return p.RequestSnapshotInfo()
+		},
+		RequestChunk: func(peer string, r snapstream.Request) error {
+			p := h.peers.Peer(peer)
+			if p == nil {
+				return errNotRegistered
+			}
+			return p.RequestSnapshotChunk(r)
+		},
+		VerifiedRoot: func(epoch idx.Epoch) (hash.Hash, bool) {
+			// epoch records are verified by LLR votes before they are written
+			bs, _ := h.store.GetHistoryBlockEpochState(epoch)
+			if bs == nil {
+				return hash.Hash{}, false
+			}
+			return bs.FinalizedStateRoot, true
+		},
+		Import: func(info snapstream.Info, r io.Reader) error {
+			return h.process.Snapshot(info.Root, r)
+		},
+		Timeout: func(peer string) {
+			h.recordPeer(peer, peerscore.Timeout)
+		},
+	})
+	h.snapSeeder = snapseeder.New(h.config.Protocol.SnapSeeder, snapseeder.Callbacks{
+		Export: func(ctx context.Context, epoch idx.Epoch, w io.Writer) (hash.Hash, error) {
+			bs, _ := h.store.GetHistoryBlockEpochState(epoch)
+			if bs == nil {
+				return hash.Hash{}, errNonExistingEpoch
+			}
+			root, err := h.store.EvmStore().ExportBlockLiveWorldState(ctx, bs.LastBlock.Idx, w)
+			if err != nil {
+				return hash.Hash{}, err
+			}
+			if root != bs.FinalizedStateRoot {
+				return hash.Hash{}, fmt.Errorf("exported state root %s doesn't match the block state root %s", root, bs.FinalizedStateRoot)
+			}
+			return root, nil
+		},
+	})
+
 	return h, nil
 }
 
 func (h *handler) peerMisbehaviour(peer string, err error) bool {
 	if eventcheck.IsBan(err) {
 		log.Warn("Dropping peer due to a misbehaviour", "peer", peer, "err", err)
+		h.recordPeer(peer, peerscore.InvalidData)
 		h.removePeer(peer)
 		return true
 	}
 	return false
 }
 
+// recordPeer applies the peer behaviour to its score and drops the peer if it gets banned
+func (h *handler) recordPeer(peer string, event peerscore.Event) {
+	if !h.peerScores.Record(peer, event) {
+		return
+	}
+	if p := h.peers.Peer(peer); p != nil {
+		discfilter.Ban(p.ID())
+	}
+	h.removePeer(peer)
+}
+
+// releasedItem updates the score of the peer which has delivered the item
+func (h *handler) releasedItem(peer string, err error) {
+	if peer == "" {
+		// locally created item
+		return
+	}
+	if err == nil {
+		h.recordPeer(peer, peerscore.UsefulData)
+	} else if eventcheck.IsBan(err) {
+		h.recordPeer(peer, peerscore.InvalidData)
+		h.removePeer(peer)
+	}
+}
+
 func (h *handler) makeDagProcessor(checkers *eventcheck.Checkers) *dagprocessor.Processor {
 	// checkers
 	lightCheck := func(e dag.Event) error {
@@ -432,8 +525,8 @@ func (h *handler) makeDagProcessor(checkers *eventcheck.Checkers) *dagprocessor.
 			Released: func(e dag.Event, peer string, err error) {
 				if eventcheck.IsBan(err) {
 					log.Warn("Incoming event rejected", "event", e.ID().String(), "creator", e.Creator(), "err", err)
-					h.removePeer(peer)
 				}
+				h.releasedItem(peer, err)
 			},
 
 			Exists: func(id hash.Event) bool {
@@ -476,8 +569,8 @@ func (h *handler) makeBvProcessor(checkers *eventcheck.Checkers) *bvprocessor.Pr
 			Released: func(bvs inter.LlrSignedBlockVotes, peer string, err error) {
 				if eventcheck.IsBan(err) {
 					log.Warn("Incoming BVs rejected", "BVs", bvs.Signed.Locator.ID(), "creator", bvs.Signed.Locator.Creator, "err", err)
-					h.removePeer(peer)
 				}
+				h.releasedItem(peer, err)
 			},
 			Check: allChecker.Enqueue,
 		},
@@ -493,8 +586,8 @@ func (h *handler) makeBrProcessor() *brprocessor.Processor {
 			Released: func(br ibr.LlrIdxFullBlockRecord, peer string, err error) {
 				if eventcheck.IsBan(err) {
 					log.Warn("Incoming BR rejected", "block", br.Idx, "err", err)
-					h.removePeer(peer)
 				}
+				h.releasedItem(peer, err)
 			},
 		},
 	})
@@ -521,14 +614,14 @@ func (h *handler) makeEpProcessor(checkers *eventcheck.Checkers) *epprocessor.Pr
 			ReleasedEV: func(ev inter.LlrSignedEpochVote, peer string, err error) {
 				if eventcheck.IsBan(err) {
 					log.Warn("Incoming EV rejected", "event", ev.Signed.Locator.ID(), "creator", ev.Signed.Locator.Creator, "err", err)
-					h.removePeer(peer)
 				}
+				h.releasedItem(peer, err)
 			},
 			ReleasedER: func(er ier.LlrIdxFullEpochRecord, peer string, err error) {
 				if eventcheck.IsBan(err) {
 					log.Warn("Incoming ER rejected", "epoch", er.Idx, "err", err)
-					h.removePeer(peer)
 				}
+				h.releasedItem(peer, err)
 			},
 			CheckEV: allChecker.Enqueue,
 		},
@@ -584,6 +677,7 @@ func (h *handler) unregisterPeer(id string) {
 	_ = h.brSeeder.UnregisterPeer(id)
 	_ = h.bvLeecher.UnregisterPeer(id)
 	_ = h.bvSeeder.UnregisterPeer(id)
+	_ = h.snapLeecher.UnregisterPeer(id)
 	if err := h.peers.UnregisterPeer(id); err != nil {
 		log.Error("Peer removal failed", "peer", id, "err", err)
 	}
@@ -612,6 +706,10 @@ func (h *handler) Start(maxPeers int) {
 		go h.progressBroadcastLoop()
 		go h.onNewEpochLoop()
 	}
+	if h.syncStatus.Is(ssLlr) {
+		h.loopsWg.Add(1)
+		go h.llrSyncLoop()
+	}
 
 	// start sync handlers
 	go h.txsyncLoop()
@@ -634,12 +732,22 @@ func (h *handler) Start(maxPeers int) {
 	h.brProcessor.Start()
 	h.brSeeder.Start()
 	h.brLeecher.Start()
+
+	h.snapSeeder.Start()
+	if h.snapshotSync {
+		h.snapLeecher.Start()
+	}
 	h.started.Done()
 }
 
 func (h *handler) Stop() {
 	log.Info("Stopping Fantom protocol")
 
+	if h.snapshotSync {
+		h.snapLeecher.Stop()
+	}
+	h.snapSeeder.Stop()
+
 	h.brLeecher.Stop()
 	h.brSeeder.Stop()
 	h.brProcessor.Stop()
@@ -733,6 +841,10 @@ func (h *handler) handle(p *peer) error {
 	if !p.Peer.Info().Network.Trusted && useless {
 		p.SetUseless()
 	}
+	if !p.Peer.Info().Network.Trusted && h.peerScores.Banned(p.id) {
+		p.Log().Trace("Rejecting peer as banned")
+		return p2p.DiscUselessPeer
+	}
 
 	h.peerWG.Add(1)
 	defer h.peerWG.Done()
@@ -780,6 +892,12 @@ func (h *handler) handle(p *peer) error {
 			return err
 		}
 	}
+	if p.RunningCap(ProtocolName, []uint{FTM64}) {
+		if err := h.snapLeecher.RegisterPeer(p.id); err != nil {
+			p.Log().Warn("Leecher peer registration failed", "err", err)
+			return err
+		}
+	}
 	defer h.unregisterPeer(p.id)
 
 	// Propagate existing transactions. new transactions appearing
@@ -1010,6 +1128,10 @@ func (h *handler) handleMsg(p *peer) error {
 		})
 
 	case msg.Code == EventsMsg:
+		if !h.syncStatus.AcceptEvents() {
+			break
+		}
+
 		var events inter.EventPayloads
 		if err := msg.Decode(&events); err != nil {
 			return errResp(ErrDecode, "%v: %v", msg, err)
@@ -1021,6 +1143,10 @@ func (h *handler) handleMsg(p *peer) error {
 		h.handleEvents(p, events.Bases(), events.Len() > 1)
 
 	case msg.Code == NewEventIDsMsg:
+		if !h.syncStatus.AcceptEvents() {
+			break
+		}
+
 		var announces hash.Events
 		if err := msg.Decode(&announces); err != nil {
 			return errResp(ErrDecode, "%v: %v", msg, err)
@@ -1083,6 +1209,10 @@ func (h *handler) handleMsg(p *peer) error {
 		}
 
 	case msg.Code == EventsStreamResponse:
+		if !h.syncStatus.AcceptEvents() {
+			break
+		}
+
 		var chunk dagChunk
 		if err := msg.Decode(&chunk); err != nil {
 			return errResp(ErrDecode, "%v: %v", msg, err)
@@ -1239,6 +1369,42 @@ func (h *handler) handleMsg(p *peer) error {
 
 		_ = h.epLeecher.NotifyChunkReceived(chunk.SessionID, last, chunk.Done)
 
+	case msg.Code == GetSnapshotInfoMsg:
+		if err := p.SendSnapshotInfo(snapstream.InfoResponse{Snapshots: h.snapSeeder.Snapshots()}); err != nil {
+			return err
+		}
+
+	case msg.Code == SnapshotInfoMsg:
+		var info snapstream.InfoResponse
+		if err := msg.Decode(&info); err != nil {
+			return errResp(ErrDecode, "%v: %v", msg, err)
+		}
+		if err := h.snapLeecher.NotifyInfo(p.id, info.Snapshots); err != nil {
+			return err
+		}
+
+	case msg.Code == GetSnapshotChunkMsg:
+		var request snapstream.Request
+		if err := msg.Decode(&request); err != nil {
+			return errResp(ErrDecode, "%v: %v", msg, err)
+		}
+		chunk, peerErr := h.snapSeeder.ReadChunk(request)
+		if peerErr != nil {
+			return peerErr
+		}
+		if err := p.SendSnapshotChunk(chunk); err != nil {
+			return err
+		}
+
+	case msg.Code == SnapshotChunkMsg:
+		var chunk snapstream.Response
+		if err := msg.Decode(&chunk); err != nil {
+			return errResp(ErrDecode, "%v: %v", msg, err)
+		}
+		if err := h.snapLeecher.NotifyChunk(p.id, chunk); err != nil {
+			return err
+		}
+
 	default:
 		return errResp(ErrInvalidMsgCode, "%v", msg.Code)
 	}
@@ -1395,6 +1561,7 @@ func (h *handler) onNewEpochLoop() {
 		case myEpoch := <-h.newEpochsCh:
 			h.dagProcessor.Clear()
 			h.dagLeecher.OnNewEpoch(myEpoch)
+			h.snapSeeder.OnNewEpoch(myEpoch)
 		// Err() channel will be closed when unsubscribing.
 		case <-h.newEpochsSub.Err():
 			return
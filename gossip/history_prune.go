@@ -0,0 +1,141 @@
+package gossip
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const historyPrunePeriod = time.Minute
+
+// PruneHistoryBlock deletes receipts, txs positions, indexed logs and indexed traces of the block
+func PruneHistoryBlock(store *Store, n idx.Block) error {
+	block := store.GetBlock(n)
+	if block == nil {
+		return nil
+	}
+	txs := store.GetBlockTxs(n, block)
+	receipts, _ := store.evm.GetRawReceipts(n)
+
+	var logIndex uint
+	for i, r := range receipts {
+		if i >= len(txs) {
+			break
+		}
+		txHash := txs[i].Hash()
+		logs := make([]*types.Log, len(r.Logs))
+		for j, l := range r.Logs {
+			logs[j] = &types.Log{
+				Address:     l.Address,
+				Topics:      l.Topics,
+				BlockNumber: uint64(n),
+				TxHash:      txHash,
+				Index:       logIndex,
+			}
+			logIndex++
+		}
+		if err := store.evm.EvmLogs.Delete(logs...); err != nil {
+			return err
+		}
+	}
+	for _, tx := range txs {
+		store.evm.DeleteTxPosition(tx.Hash())
+	}
+	store.evm.DeleteReceipts(n)
+	store.evm.DeleteTraceIndex(n)
+	return nil
+}
+
+// historyPruner periodically prunes receipts, txs positions, indexed logs and indexed traces
+// of the blocks out of the history retention window
+type historyPruner struct {
+	store     *Store
+	engineMu  *sync.RWMutex
+	retention uint64
+
+	wg   sync.WaitGroup
+	quit chan struct{}
+}
+
+func newHistoryPruner(store *Store, engineMu *sync.RWMutex) *historyPruner {
+	return &historyPruner{
+		store:     store,
+		engineMu:  engineMu,
+		retention: store.evm.GetHistoryRetention(),
+		quit:      make(chan struct{}),
+	}
+}
+
+// horizon returns the first block which is kept according to the retention window
+func (p *historyPruner) horizon() idx.Block {
+	last := uint64(p.store.GetLatestBlockIndex())
+	if last < p.retention {
+		return 0
+	}
+	return idx.Block(last - p.retention + 1)
+}
+
+// pruneBlock prunes the first retained block if it's out of the retention window.
+// The engine is locked only for a single block, not to delay the blocks processing.
+// Returns false if no blocks are to be pruned.
+func (p *historyPruner) pruneBlock() (pruned bool, err error) {
+	p.engineMu.Lock()
+	defer p.engineMu.Unlock()
+
+	n := p.store.evm.GetHistoryFirstBlock()
+	if n >= p.horizon() {
+		return false, nil
+	}
+	if err := PruneHistoryBlock(p.store, n); err != nil {
+		return false, err
+	}
+	p.store.evm.SetHistoryFirstBlock(n + 1)
+	return true, nil
+}
+
+func (p *historyPruner) loop() {
+	defer p.wg.Done()
+	ticker := time.NewTicker(historyPrunePeriod)
+	defer ticker.Stop()
+	for {
+		start, reported := time.Now(), time.Now()
+		for pruned := true; pruned; {
+			select {
+			case <-p.quit:
+				return
+			default:
+			}
+			var err error
+			if pruned, err = p.pruneBlock(); err != nil {
+				log.Error("Failed to prune history", "err", err)
+			}
+			if time.Since(reported) >= 8*time.Second {
+				log.Info("Pruning history", "first", p.store.evm.GetHistoryFirstBlock(), "elapsed", common.PrettyDuration(time.Since(start)))
+				reported = time.Now()
+			}
+		}
+		log.Debug("History pruned", "first", p.store.evm.GetHistoryFirstBlock(), "elapsed", common.PrettyDuration(time.Since(start)))
+		select {
+		case <-ticker.C:
+		case <-p.quit:
+			return
+		}
+	}
+}
+
+func (p *historyPruner) Start() {
+	if p.retention == 0 {
+		return
+	}
+	p.wg.Add(1)
+	go p.loop()
+}
+
+func (p *historyPruner) Stop() {
+	close(p.quit)
+	p.wg.Wait()
+}
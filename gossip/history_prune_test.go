@@ -0,0 +1,32 @@
+package gossip
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/gossip/evmstore"
+)
+
+func TestGetTransactionPrunedHistory(t *testing.T) {
+	require := require.New(t)
+	env := newTestEnv(2, 1, t)
+	defer env.Close()
+	ctx := context.Background()
+
+	env.store.evm.SetHistoryFirstBlock(5)
+
+	// the unknown tx isn't reported as pruned
+	tx, _, _, err := env.EthAPI.GetTransaction(ctx, common.Hash{1})
+	require.NoError(err)
+	require.Nil(tx)
+
+	// the tx known to be out of the retention window is reported as pruned
+	pruned := common.Hash{2}
+	env.store.evm.SetTxPosition(pruned, evmstore.TxPosition{Block: 4})
+	_, _, _, err = env.EthAPI.GetTransaction(ctx, pruned)
+	require.True(errors.Is(err, evmstore.ErrHistoryPruned))
+}
@@ -0,0 +1,59 @@
+package gossip
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/Fantom-foundation/go-opera/utils/signers/gsignercache"
+)
+
+// ReindexLogs rebuilds the logs index (by addresses and topics) of historic blocks from the stored receipts.
+// The index records are overwritten, so the already indexed blocks aren't duplicated.
+// Blocks without stored receipts are skipped.
+func ReindexLogs(ctx context.Context, store *Store, from, to idx.Block) (err error) {
+	defer func() {
+		if flushErr := store.Commit(); err == nil {
+			err = flushErr
+		}
+	}()
+	reader := &EvmStateReader{store: store}
+	signer := gsignercache.Wrap(types.LatestSignerForChainID(new(big.Int).SetUint64(store.GetRules().NetworkID)))
+
+	var logs int
+	start, reported := time.Now(), time.Now()
+	for n := from; n <= to; n++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		block := reader.GetBlock(common.Hash{}, uint64(n))
+		if block == nil {
+			return fmt.Errorf("block %d not found", n)
+		}
+		if store.evm.GetRawReceiptsRLP(n) == nil {
+			continue // receipts are pruned or not stored
+		}
+		for _, r := range store.evm.GetReceipts(n, signer, block.Hash, block.Transactions) {
+			store.evm.IndexLogs(r.Logs...)
+			logs += len(r.Logs)
+		}
+
+		if store.IsCommitNeeded() {
+			if err := store.Commit(); err != nil {
+				return err
+			}
+		}
+		if time.Since(reported) >= 8*time.Second {
+			log.Info("Reindexing logs", "block", n, "last", to, "logs", logs, "elapsed", common.PrettyDuration(time.Since(start)))
+			reported = time.Now()
+		}
+	}
+	log.Info("Logs reindexed", "first", from, "last", to, "logs", logs, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/Fantom-foundation/go-opera/gossip/protocols/blockvotes/bvstream"
 	"github.com/Fantom-foundation/go-opera/gossip/protocols/dag/dagstream"
 	"github.com/Fantom-foundation/go-opera/gossip/protocols/epochpacks/epstream"
+	"github.com/Fantom-foundation/go-opera/gossip/protocols/snapshots/snapstream"
 	"github.com/Fantom-foundation/go-opera/inter"
 )
 
@@ -473,6 +474,22 @@ func (p *peer) RequestEPsStream(r epstream.Request) error {
 	return p2p.Send(p.rw, RequestEPsStream, r)
 }
 
+func (p *peer) SendSnapshotInfo(r snapstream.InfoResponse) error {
+	return p2p.Send(p.rw, SnapshotInfoMsg, r)
+}
+
+func (p *peer) RequestSnapshotInfo() error {
+	return p2p.Send(p.rw, GetSnapshotInfoMsg, snapstream.InfoRequest{})
+}
+
+func (p *peer) SendSnapshotChunk(r snapstream.Response) error {
+	return p2p.Send(p.rw, SnapshotChunkMsg, r)
+}
+
+func (p *peer) RequestSnapshotChunk(r snapstream.Request) error {
+	return p2p.Send(p.rw, GetSnapshotChunkMsg, r)
+}
+
 func (p *peer) SendEventsStream(r dagstream.Response, ids hash.Events) error {
 	// Mark all the event hash as known, but ensure we don't overflow our limits
 	for _, id := range ids {
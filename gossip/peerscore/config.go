@@ -0,0 +1,38 @@
+package peerscore
+
+import (
+	"time"
+)
+
+type Config struct {
+	// Threshold is the score below which a peer gets banned
+	Threshold int64
+	// MaxScore limits the score accumulated by useful data
+	MaxScore int64
+	// BanDuration is the duration of a ban
+	BanDuration time.Duration
+	// DecayPeriod is the period of moving the score one point towards zero
+	DecayPeriod time.Duration
+	// MaxPeers limits the number of tracked peers which aren't banned
+	MaxPeers int
+
+	InvalidDataPenalty  int64
+	TimeoutPenalty      int64
+	SlowResponsePenalty int64
+	UsefulDataReward    int64
+}
+
+// DefaultConfig returns default peer scoring config
+func DefaultConfig() Config {
+	return Config{
+		Threshold:           -100,
+		MaxScore:            100,
+		BanDuration:         time.Hour,
+		DecayPeriod:         time.Minute,
+		MaxPeers:            1024,
+		InvalidDataPenalty:  50,
+		TimeoutPenalty:      10,
+		SlowResponsePenalty: 5,
+		UsefulDataReward:    1,
+	}
+}
@@ -0,0 +1,236 @@
+package peerscore
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/common/bigendian"
+	"github.com/Fantom-foundation/lachesis-base/kvdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Event is a peer behaviour which affects its score
+type Event int
+
+const (
+	// InvalidData is a data rejected by the event checkers
+	InvalidData Event = iota
+	// Timeout is a request which wasn't answered in time
+	Timeout
+	// SlowResponse is a stream which was served too slowly
+	SlowResponse
+	// UsefulData is a data which was accepted
+	UsefulData
+)
+
+// PeerScore is the score of a peer exposed by API
+type PeerScore struct {
+	ID            string     `json:"id"`
+	Score         int64      `json:"score"`
+	InvalidData   uint64     `json:"invalidData"`
+	Timeouts      uint64     `json:"timeouts"`
+	SlowResponses uint64     `json:"slowResponses"`
+	UsefulData    uint64     `json:"usefulData"`
+	BannedUntil   *time.Time `json:"bannedUntil,omitempty"`
+}
+
+type record struct {
+	PeerScore
+	updated time.Time
+}
+
+// Scorer tracks the scores of peers and bans the misbehaving ones.
+// The bans are stored into DB and survive a restart.
+type Scorer struct {
+	cfg Config
+	db  kvdb.Store
+
+	mu    sync.Mutex
+	peers map[string]*record
+	bans  map[string]time.Time
+
+	now func() time.Time
+}
+
+// New creates a peer scorer and loads the active bans from the DB
+func New(cfg Config, db kvdb.Store) *Scorer {
+	s := &Scorer{
+		cfg:   cfg,
+		db:    db,
+		peers: make(map[string]*record),
+		bans:  make(map[string]time.Time),
+		now:   time.Now,
+	}
+	s.load()
+	return s
+}
+
+func (s *Scorer) load() {
+	it := s.db.NewIterator(nil, nil)
+	defer it.Release()
+	now := s.now()
+	expired := make([][]byte, 0)
+	for it.Next() {
+		until := time.Unix(0, int64(bigendian.BytesToUint64(it.Value())))
+		if !until.After(now) {
+			expired = append(expired, append([]byte{}, it.Key()...))
+			continue
+		}
+		s.bans[string(it.Key())] = until
+	}
+	if it.Error() != nil {
+		log.Error("Failed to load peer bans", "err", it.Error())
+	}
+	for _, key := range expired {
+		s.deleteBan(string(key))
+	}
+}
+
+func (s *Scorer) deleteBan(peer string) {
+	delete(s.bans, peer)
+	if err := s.db.Delete([]byte(peer)); err != nil {
+		log.Error("Failed to delete peer ban", "peer", peer, "err", err)
+	}
+}
+
+// banned returns the expiration time of the peer ban, s.mu must be locked
+func (s *Scorer) banned(peer string, now time.Time) (time.Time, bool) {
+	until, ok := s.bans[peer]
+	if !ok {
+		return time.Time{}, false
+	}
+	if !until.After(now) {
+		s.deleteBan(peer)
+		return time.Time{}, false
+	}
+	return until, true
+}
+
+// Banned returns true if the peer is temporarily banned
+func (s *Scorer) Banned(peer string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, banned := s.banned(peer, s.now())
+	return banned
+}
+
+// decay moves the score towards zero for every passed decay period
+func (s *Scorer) decay(r *record, now time.Time) {
+	if s.cfg.DecayPeriod <= 0 {
+		r.updated = now
+		return
+	}
+	steps := int64(now.Sub(r.updated) / s.cfg.DecayPeriod)
+	if steps <= 0 {
+		return
+	}
+	r.updated = r.updated.Add(time.Duration(steps) * s.cfg.DecayPeriod)
+	if r.Score > 0 {
+		r.Score -= steps
+		if r.Score < 0 {
+			r.Score = 0
+		}
+	} else if r.Score < 0 {
+		r.Score += steps
+		if r.Score > 0 {
+			r.Score = 0
+		}
+	}
+}
+
+// evict removes the least recently updated peer if too many peers are tracked
+func (s *Scorer) evict() {
+	if len(s.peers) < s.cfg.MaxPeers {
+		return
+	}
+	var oldest string
+	for peer, r := range s.peers {
+		if oldest == "" || r.updated.Before(s.peers[oldest].updated) {
+			oldest = peer
+		}
+	}
+	delete(s.peers, oldest)
+}
+
+// Record applies the peer behaviour to its score and returns true if the peer is banned
+func (s *Scorer) Record(peer string, event Event) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	if _, banned := s.banned(peer, now); banned {
+		return true
+	}
+
+	r := s.peers[peer]
+	if r == nil {
+		s.evict()
+		r = &record{
+			PeerScore: PeerScore{ID: peer},
+			updated:   now,
+		}
+		s.peers[peer] = r
+	}
+	s.decay(r, now)
+
+	switch event {
+	case InvalidData:
+		r.InvalidData++
+		r.Score -= s.cfg.InvalidDataPenalty
+	case Timeout:
+		r.Timeouts++
+		r.Score -= s.cfg.TimeoutPenalty
+	case SlowResponse:
+		r.SlowResponses++
+		r.Score -= s.cfg.SlowResponsePenalty
+	case UsefulData:
+		r.UsefulData++
+		r.Score += s.cfg.UsefulDataReward
+		if r.Score > s.cfg.MaxScore {
+			r.Score = s.cfg.MaxScore
+		}
+	}
+	if r.Score >= s.cfg.Threshold {
+		return false
+	}
+
+	// ban the peer and give it a fresh start after the ban
+	until := now.Add(s.cfg.BanDuration)
+	s.bans[peer] = until
+	delete(s.peers, peer)
+	if err := s.db.Put([]byte(peer), bigendian.Uint64ToBytes(uint64(until.UnixNano()))); err != nil {
+		log.Error("Failed to store peer ban", "peer", peer, "err", err)
+	}
+	log.Warn("Peer is banned due to a low score", "peer", peer, "score", r.Score, "until", until)
+	return true
+}
+
+// Scores returns the scores of the tracked and banned peers
+func (s *Scorer) Scores() []PeerScore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	res := make([]PeerScore, 0, len(s.peers)+len(s.bans))
+	for _, r := range s.peers {
+		s.decay(r, now)
+		res = append(res, r.PeerScore)
+	}
+	for peer := range s.bans {
+		until, banned := s.banned(peer, now)
+		if !banned {
+			continue
+		}
+		res = append(res, PeerScore{
+			ID:          peer,
+			Score:       s.cfg.Threshold,
+			BannedUntil: &until,
+		})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Score != res[j].Score {
+			return res[i].Score > res[j].Score
+		}
+		return res[i].ID < res[j].ID
+	})
+	return res
+}
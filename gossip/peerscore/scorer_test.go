@@ -0,0 +1,66 @@
+package peerscore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/kvdb/memorydb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScorerBan(t *testing.T) {
+	require := require.New(t)
+	db := memorydb.New()
+	now := time.Now()
+	cfg := DefaultConfig()
+
+	s := New(cfg, db)
+	s.now = func() time.Time { return now }
+	require.False(s.Record("peer1", UsefulData))
+	require.False(s.Record("peer2", Timeout))
+	require.False(s.Record("peer2", InvalidData))
+	require.False(s.Banned("peer2"))
+	require.True(s.Record("peer2", InvalidData))
+	require.True(s.Banned("peer2"))
+	require.False(s.Banned("peer1"))
+
+	scores := s.Scores()
+	require.Len(scores, 2)
+	require.Equal("peer1", scores[0].ID)
+	require.Equal(int64(1), scores[0].Score)
+	require.Equal(uint64(1), scores[0].UsefulData)
+	require.Equal("peer2", scores[1].ID)
+	require.Equal(now.Add(cfg.BanDuration), *scores[1].BannedUntil)
+
+	// the ban survives a restart
+	s = New(cfg, db)
+	s.now = func() time.Time { return now }
+	require.True(s.Banned("peer2"))
+	require.True(s.Record("peer2", UsefulData))
+
+	// the ban expires
+	now = now.Add(cfg.BanDuration)
+	require.False(s.Banned("peer2"))
+	s = New(cfg, db)
+	require.False(s.Banned("peer2"))
+}
+
+func TestScorerDecay(t *testing.T) {
+	require := require.New(t)
+	now := time.Now()
+	cfg := DefaultConfig()
+
+	s := New(cfg, memorydb.New())
+	s.now = func() time.Time { return now }
+	require.False(s.Record("peer", InvalidData))
+	require.Equal(-cfg.InvalidDataPenalty, s.Scores()[0].Score)
+
+	// the penalty is forgotten gradually
+	now = now.Add(10 * cfg.DecayPeriod)
+	require.Equal(-cfg.InvalidDataPenalty+10, s.Scores()[0].Score)
+	require.False(s.Record("peer", InvalidData))
+	require.Equal(-2*cfg.InvalidDataPenalty+10, s.Scores()[0].Score)
+
+	now = now.Add(time.Duration(2*cfg.InvalidDataPenalty) * cfg.DecayPeriod)
+	require.Equal(int64(0), s.Scores()[0].Score)
+}
@@ -19,17 +19,18 @@ import (
 const (
 	FTM62           = 62
 	FTM63           = 63
-	ProtocolVersion = FTM63
+	FTM64           = 64
+	ProtocolVersion = FTM64
 )
 
 // ProtocolName is the official short name of the protocol used during capability negotiation.
 const ProtocolName = "opera"
 
 // ProtocolVersions are the supported versions of the protocol (first is primary).
-var ProtocolVersions = []uint{FTM62, FTM63}
+var ProtocolVersions = []uint{FTM62, FTM63, FTM64}
 
 // protocolLengths are the number of implemented message corresponding to different protocol versions.
-var protocolLengths = map[uint]uint64{FTM62: EventsStreamResponse + 1, FTM63: EPsStreamResponse + 1}
+var protocolLengths = map[uint]uint64{FTM62: EventsStreamResponse + 1, FTM63: EPsStreamResponse + 1, FTM64: SnapshotChunkMsg + 1}
 
 const protocolMaxMsgSize = inter.ProtocolMaxMsgSize // Maximum cap on the size of a protocol message
 
@@ -68,6 +69,15 @@ const (
 	BRsStreamResponse = 13
 	RequestEPsStream  = 14
 	EPsStreamResponse = 15
+
+	// Request the live state snapshots served by the peer
+	GetSnapshotInfoMsg = 16
+	// Contains the live state snapshots served by the peer
+	SnapshotInfoMsg = 17
+	// Request a chunk of the live state snapshot
+	GetSnapshotChunkMsg = 18
+	// Contains the requested chunk of the live state snapshot
+	SnapshotChunkMsg = 19
 )
 
 type errCode int
@@ -121,6 +131,10 @@ type TxPool interface {
 	Content() (map[common.Address]types.Transactions, map[common.Address]types.Transactions)
 	ContentFrom(addr common.Address) (types.Transactions, types.Transactions)
 	GasPrice() *big.Int
+
+	TxHistory(hash common.Hash) []evmcore.TxEvent
+	SubscribeTxEvents(ch chan<- evmcore.TxEvent) notify.Subscription
+	NotifyIncluded(event hash.Event, txs types.Transactions)
 }
 
 // handshakeData is the network packet for the initial handshake message
@@ -60,6 +60,11 @@ type Callbacks struct {
 	RequestChunk func(peer string, r dagstream.Request) error
 	Suspend      func(peer string) bool
 	PeerEpoch    func(peer string) idx.Epoch
+
+	// Timeout is called when a session is terminated as the peer stopped sending chunks
+	Timeout func(peer string)
+	// Slow is called when a session is terminated as the peer serves it too slowly
+	Slow func(peer string)
 }
 
 type sessionState struct {
@@ -78,6 +83,14 @@ func (d *Leecher) shouldTerminateSession() bool {
 
 	noProgress := time.Since(d.session.lastReceived) >= d.cfg.BaseProgressWatchdog*time.Duration(d.session.try+5)/5
 	stuck := time.Since(d.session.startTime) >= d.cfg.BaseSessionWatchdog*time.Duration(d.session.try+5)/5
+	if !d.callback.Suspend(d.session.peer) {
+		// blame the peer only if the session isn't slowed down by the local node
+		if noProgress {
+			d.callback.Timeout(d.session.peer)
+		} else if stuck {
+			d.callback.Slow(d.session.peer)
+		}
+	}
 	return stuck || noProgress
 }
 
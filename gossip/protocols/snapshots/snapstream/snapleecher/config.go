@@ -0,0 +1,38 @@
+package snapleecher
+
+import (
+	"time"
+)
+
+type Config struct {
+	// Dir is the directory of the downloaded snapshot
+	Dir             string
+	ChunkSize       uint64
+	ParallelChunks  int
+	ArriveTimeout   time.Duration
+	RecheckInterval time.Duration
+	// InfoInterval is the interval of requesting the served snapshots from peers
+	InfoInterval time.Duration
+}
+
+// DefaultConfig returns default leecher config
+func DefaultConfig() Config {
+	return Config{
+		ChunkSize:       1024 * 1024,
+		ParallelChunks:  16,
+		ArriveTimeout:   20 * time.Second,
+		RecheckInterval: time.Second,
+		InfoInterval:    time.Minute,
+	}
+}
+
+// LiteConfig returns default leecher config for tests
+func LiteConfig() Config {
+	cfg := DefaultConfig()
+	cfg.ChunkSize = 1024
+	cfg.ParallelChunks = 4
+	cfg.ArriveTimeout = time.Second
+	cfg.RecheckInterval = 10 * time.Millisecond
+	cfg.InfoInterval = 100 * time.Millisecond
+	return cfg
+}
@@ -0,0 +1,441 @@
+package snapleecher
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/Fantom-foundation/go-opera/gossip/protocols/snapshots/snapstream"
+)
+
+var (
+	ErrWrongChunkSize  = errors.New("wrong size of snapshot chunk")
+	ErrWrongRoot       = errors.New("snapshot header doesn't match the state root")
+	ErrTooManySnapshot = errors.New("too many snapshots")
+)
+
+const (
+	partExt      = ".part"
+	maxSnapshots = 64
+)
+
+// Leecher downloads the live state snapshot whose root is verified by LLR votes.
+// The snapshot is downloaded by chunks from all the peers which serve it,
+// and the download is resumed from the partially downloaded file after a restart.
+type Leecher struct {
+	cfg      Config
+	callback Callbacks
+
+	mu     sync.Mutex
+	peers  map[string]*peerState
+	bad    map[snapstream.Info]bool
+	target *download
+
+	done uint32
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+type Callbacks struct {
+	RequestInfo  func(peer string) error
+	RequestChunk func(peer string, r snapstream.Request) error
+	// VerifiedRoot returns the state root at the beginning of the epoch if it's verified by LLR votes
+	VerifiedRoot func(epoch idx.Epoch) (hash.Hash, bool)
+	// Import imports the downloaded snapshot
+	Import func(info snapstream.Info, r io.Reader) error
+	// Timeout is called when a requested chunk hasn't arrived in time
+	Timeout func(peer string)
+}
+
+type peerState struct {
+	snapshots     []snapstream.Info
+	infoRequested time.Time
+}
+
+type request struct {
+	peer string
+	size uint64
+	sent time.Time
+}
+
+type download struct {
+	info     snapstream.Info
+	file     *os.File
+	written  uint64
+	next     uint64
+	retry    []uint64
+	requests map[uint64]request
+	received map[uint64][]byte
+}
+
+// New creates a snapshot downloader
+func New(cfg Config, callback Callbacks) *Leecher {
+	return &Leecher{
+		cfg:      cfg,
+		callback: callback,
+		peers:    make(map[string]*peerState),
+		bad:      make(map[snapstream.Info]bool),
+		quit:     make(chan struct{}),
+	}
+}
+
+// Start starts downloading of the snapshot
+func (d *Leecher) Start() {
+	d.wg.Add(1)
+	go d.loop()
+}
+
+// Stop interrupts downloading, the download is resumed after the next start
+func (d *Leecher) Stop() {
+	close(d.quit)
+	d.wg.Wait()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.target != nil {
+		_ = d.target.file.Close()
+		d.target = nil
+	}
+}
+
+// Done returns true if the snapshot is downloaded and imported
+func (d *Leecher) Done() bool {
+	return atomic.LoadUint32(&d.done) != 0
+}
+
+func (d *Leecher) loop() {
+	defer d.wg.Done()
+	ticker := time.NewTicker(d.cfg.RecheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if d.Done() {
+				return
+			}
+			if complete := d.tick(); complete != nil {
+				d.importSnapshot(complete)
+			}
+		case <-d.quit:
+			return
+		}
+	}
+}
+
+func (d *Leecher) RegisterPeer(peer string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.peers[peer] = &peerState{}
+	return nil
+}
+
+func (d *Leecher) UnregisterPeer(peer string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.peers, peer)
+	if t := d.target; t != nil {
+		for offset, r := range t.requests {
+			if r.peer == peer {
+				delete(t.requests, offset)
+				t.retry = append(t.retry, offset)
+			}
+		}
+	}
+	return nil
+}
+
+// NotifyInfo is called when the peer reports the served snapshots
+func (d *Leecher) NotifyInfo(peer string, snapshots []snapstream.Info) error {
+	if len(snapshots) > maxSnapshots {
+		return ErrTooManySnapshot
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if p := d.peers[peer]; p != nil {
+		p.snapshots = snapshots
+	}
+	return nil
+}
+
+// NotifyChunk is called when the chunk is received from the peer
+func (d *Leecher) NotifyChunk(peer string, r snapstream.Response) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	t := d.target
+	if t == nil || r.Epoch != t.info.Epoch || r.Root != t.info.Root {
+		return nil
+	}
+	req, ok := t.requests[r.Offset]
+	if !ok || req.peer != peer {
+		// already re-requested from another peer
+		return nil
+	}
+	delete(t.requests, r.Offset)
+	if len(r.Data) == 0 {
+		// the peer doesn't serve the snapshot anymore
+		if p := d.peers[peer]; p != nil {
+			p.snapshots = nil
+		}
+		t.retry = append(t.retry, r.Offset)
+		return nil
+	}
+	if uint64(len(r.Data)) != req.size {
+		t.retry = append(t.retry, r.Offset)
+		return ErrWrongChunkSize
+	}
+	if r.Offset == 0 {
+		if root, err := snapstream.ReadRoot(r.Data); err != nil || root != t.info.Root {
+			t.retry = append(t.retry, r.Offset)
+			return ErrWrongRoot
+		}
+	}
+	t.received[r.Offset] = r.Data
+	if err := t.flush(); err != nil {
+		log.Error("Failed to write state snapshot", "epoch", t.info.Epoch, "err", err)
+		d.dropTarget()
+		return nil
+	}
+	d.request()
+	return nil
+}
+
+// flush writes the received chunks which follow the written ones
+func (t *download) flush() error {
+	for {
+		data, ok := t.received[t.written]
+		if !ok {
+			return nil
+		}
+		if _, err := t.file.Write(data); err != nil {
+			return err
+		}
+		delete(t.received, t.written)
+		t.written += uint64(len(data))
+	}
+}
+
+func (d *Leecher) path(info snapstream.Info) string {
+	return filepath.Join(d.cfg.Dir, fmt.Sprintf("%d-%s%s", info.Epoch, common.Bytes2Hex(info.Root.Bytes()), partExt))
+}
+
+func (d *Leecher) tick() *download {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := time.Now()
+	for id, p := range d.peers {
+		if now.Sub(p.infoRequested) >= d.cfg.InfoInterval {
+			p.infoRequested = now
+			_ = d.callback.RequestInfo(id)
+		}
+	}
+	if d.target == nil {
+		d.selectTarget()
+		if d.target == nil {
+			return nil
+		}
+	}
+	t := d.target
+	if t.written == t.info.Size {
+		d.target = nil
+		return t
+	}
+	for offset, r := range t.requests {
+		if now.Sub(r.sent) >= d.cfg.ArriveTimeout {
+			delete(t.requests, offset)
+			t.retry = append(t.retry, offset)
+			d.callback.Timeout(r.peer)
+		}
+	}
+	if len(d.servers(t.info)) == 0 {
+		// the download is resumed when the snapshot is served again
+		log.Warn("State snapshot isn't served by peers anymore", "epoch", t.info.Epoch)
+		_ = t.file.Close()
+		d.target = nil
+		return nil
+	}
+	d.request()
+	return nil
+}
+
+func (d *Leecher) servers(info snapstream.Info) []string {
+	servers := make([]string, 0, len(d.peers))
+	for id, p := range d.peers {
+		for _, s := range p.snapshots {
+			if s == info {
+				servers = append(servers, id)
+				break
+			}
+		}
+	}
+	return servers
+}
+
+// selectTarget selects the latest verified snapshot, a partially downloaded snapshot is preferred
+func (d *Leecher) selectTarget() {
+	var best *snapstream.Info
+	bestPartial := false
+	for _, p := range d.peers {
+		for _, info := range p.snapshots {
+			info := info
+			if d.bad[info] {
+				continue
+			}
+			if root, ok := d.callback.VerifiedRoot(info.Epoch); !ok || root != info.Root {
+				continue
+			}
+			_, err := os.Stat(d.path(info))
+			partial := err == nil
+			if best == nil || partial && !bestPartial || partial == bestPartial && info.Epoch > best.Epoch {
+				best = &info
+				bestPartial = partial
+			}
+		}
+	}
+	if best == nil {
+		return
+	}
+	if err := d.open(*best); err != nil {
+		log.Error("Failed to open state snapshot", "epoch", best.Epoch, "err", err)
+	}
+}
+
+func (d *Leecher) open(info snapstream.Info) error {
+	if err := os.MkdirAll(d.cfg.Dir, 0700); err != nil {
+		return err
+	}
+	// remove other partially downloaded snapshots
+	path := d.path(info)
+	entries, err := os.ReadDir(d.cfg.Dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		other := filepath.Join(d.cfg.Dir, entry.Name())
+		if strings.HasSuffix(other, partExt) && other != path {
+			_ = os.Remove(other)
+		}
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	written, err := f.Seek(0, io.SeekEnd)
+	if err == nil && uint64(written) > info.Size {
+		written = 0
+		err = f.Truncate(0)
+		if err == nil {
+			_, err = f.Seek(0, io.SeekStart)
+		}
+	}
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	log.Info("Downloading state snapshot", "epoch", info.Epoch, "root", info.Root, "size", info.Size, "offset", written)
+	d.target = &download{
+		info:     info,
+		file:     f,
+		written:  uint64(written),
+		next:     uint64(written),
+		requests: make(map[uint64]request),
+		received: make(map[uint64][]byte),
+	}
+	return nil
+}
+
+func (d *Leecher) dropTarget() {
+	_ = d.target.file.Close()
+	_ = os.Remove(d.path(d.target.info))
+	d.target = nil
+}
+
+// request requests the next chunks from the least loaded peers
+func (d *Leecher) request() {
+	t := d.target
+	servers := d.servers(t.info)
+	load := make(map[string]int, len(servers))
+	for _, r := range t.requests {
+		load[r.peer]++
+	}
+	now := time.Now()
+	for len(servers) != 0 && len(t.requests)+len(t.received) < d.cfg.ParallelChunks {
+		var offset uint64
+		if len(t.retry) != 0 {
+			offset = t.retry[0]
+			t.retry = t.retry[1:]
+		} else if t.next < t.info.Size {
+			offset = t.next
+			t.next += d.chunkSize(t.info, offset)
+		} else {
+			return
+		}
+		peer := servers[0]
+		for _, id := range servers[1:] {
+			if load[id] < load[peer] {
+				peer = id
+			}
+		}
+		size := d.chunkSize(t.info, offset)
+		err := d.callback.RequestChunk(peer, snapstream.Request{
+			Epoch:  t.info.Epoch,
+			Root:   t.info.Root,
+			Offset: offset,
+			Size:   size,
+		})
+		if err != nil {
+			t.retry = append(t.retry, offset)
+			return
+		}
+		t.requests[offset] = request{
+			peer: peer,
+			size: size,
+			sent: now,
+		}
+		load[peer]++
+	}
+}
+
+func (d *Leecher) chunkSize(info snapstream.Info, offset uint64) uint64 {
+	if info.Size-offset < d.cfg.ChunkSize {
+		return info.Size - offset
+	}
+	return d.cfg.ChunkSize
+}
+
+func (d *Leecher) importSnapshot(t *download) {
+	path := d.path(t.info)
+	_ = t.file.Close()
+	err := func() error {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		log.Info("Importing state snapshot", "epoch", t.info.Epoch, "root", t.info.Root)
+		return d.callback.Import(t.info, bufio.NewReaderSize(f, 4*1024*1024))
+	}()
+	_ = os.Remove(path)
+	if err != nil {
+		log.Error("Failed to import state snapshot", "epoch", t.info.Epoch, "err", err)
+		d.mu.Lock()
+		d.bad[t.info] = true
+		d.mu.Unlock()
+		return
+	}
+	log.Info("State snapshot is imported", "epoch", t.info.Epoch, "root", t.info.Root)
+	atomic.StoreUint32(&d.done, 1)
+}
@@ -0,0 +1,216 @@
+package snapleecher
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/gossip/protocols/snapshots/snapstream"
+	"github.com/Fantom-foundation/go-opera/gossip/protocols/snapshots/snapstream/snapseeder"
+)
+
+const (
+	infoRequestMsg = iota
+	infoResponseMsg
+	chunkRequestMsg
+	chunkResponseMsg
+)
+
+func fakeSnapshot(root hash.Hash, size int) []byte {
+	data := make([]byte, size)
+	_, _ = rand.New(rand.NewSource(int64(size))).Read(data)
+	header := append([]byte("Fantom-World-State"), 1, 'H', 0)
+	header = append(header, root.Bytes()...)
+	copy(data, header)
+	return data
+}
+
+// serve answers the snapshot requests on the pipe until limit chunks are sent
+func serve(rw p2p.MsgReadWriter, seeder *snapseeder.Seeder, limit int64) {
+	var sent int64
+	for {
+		msg, err := rw.ReadMsg()
+		if err != nil {
+			return
+		}
+		switch msg.Code {
+		case infoRequestMsg:
+			_ = msg.Discard()
+			_ = p2p.Send(rw, infoResponseMsg, snapstream.InfoResponse{Snapshots: seeder.Snapshots()})
+		case chunkRequestMsg:
+			var r snapstream.Request
+			if err := msg.Decode(&r); err != nil {
+				return
+			}
+			if atomic.AddInt64(&sent, 1) > limit {
+				continue
+			}
+			res, err := seeder.ReadChunk(r)
+			if err != nil {
+				return
+			}
+			_ = p2p.Send(rw, chunkResponseMsg, res)
+		}
+	}
+}
+
+// connect connects the leecher to the seeder over a local pipe
+func connect(t *testing.T, peer string, leecher **Leecher, seeder *snapseeder.Seeder, limit int64) map[string]p2p.MsgReadWriter {
+	local, remote := p2p.MsgPipe()
+	t.Cleanup(func() {
+		_ = local.Close()
+	})
+	go serve(remote, seeder, limit)
+	go func() {
+		for {
+			msg, err := local.ReadMsg()
+			if err != nil {
+				return
+			}
+			switch msg.Code {
+			case infoResponseMsg:
+				var res snapstream.InfoResponse
+				require.NoError(t, msg.Decode(&res))
+				go func() {
+					require.NoError(t, (*leecher).NotifyInfo(peer, res.Snapshots))
+				}()
+			case chunkResponseMsg:
+				var res snapstream.Response
+				require.NoError(t, msg.Decode(&res))
+				go func() {
+					require.NoError(t, (*leecher).NotifyChunk(peer, res))
+				}()
+			}
+		}
+	}()
+	return map[string]p2p.MsgReadWriter{peer: local}
+}
+
+func TestLeecherResume(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+	root := hash.Of([]byte("root"))
+	snapshot := fakeSnapshot(root, 100*1024+7)
+
+	seeder := snapseeder.New(snapseeder.Config{
+		Dir:          filepath.Join(dir, "seeder"),
+		Period:       5,
+		Keep:         1,
+		MaxChunkSize: 4 * 1024,
+	}, snapseeder.Callbacks{
+		Export: func(_ context.Context, epoch idx.Epoch, w io.Writer) (hash.Hash, error) {
+			_, err := w.Write(snapshot)
+			return root, err
+		},
+	})
+	seeder.Start()
+	defer seeder.Stop()
+	seeder.OnNewEpoch(10)
+	require.Eventually(func() bool {
+		return len(seeder.Snapshots()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	info := seeder.Snapshots()[0]
+	require.Equal(snapstream.Info{Epoch: 10, Root: root, Size: uint64(len(snapshot))}, info)
+
+	cfg := LiteConfig()
+	cfg.Dir = filepath.Join(dir, "leecher")
+	imported := make(chan []byte, 1)
+	newLeecher := func(pipes map[string]p2p.MsgReadWriter) *Leecher {
+		return New(cfg, Callbacks{
+			RequestInfo: func(peer string) error {
+				return p2p.Send(pipes[peer], infoRequestMsg, snapstream.InfoRequest{})
+			},
+			RequestChunk: func(peer string, r snapstream.Request) error {
+				return p2p.Send(pipes[peer], chunkRequestMsg, r)
+			},
+			VerifiedRoot: func(epoch idx.Epoch) (hash.Hash, bool) {
+				return root, epoch == 10
+			},
+			Import: func(_ snapstream.Info, r io.Reader) error {
+				data, err := io.ReadAll(r)
+				imported <- data
+				return err
+			},
+			Timeout: func(string) {},
+		})
+	}
+
+	// the first peer stops serving in the middle of the snapshot
+	var leecher *Leecher
+	leecher = newLeecher(connect(t, "peer1", &leecher, seeder, 20))
+	require.NoError(leecher.RegisterPeer("peer1"))
+	leecher.Start()
+	partPath := filepath.Join(cfg.Dir, "10-"+info.Root.Hex()[2:]+partExt)
+	require.Eventually(func() bool {
+		stat, err := os.Stat(partPath)
+		return err == nil && stat.Size() >= 16*int64(cfg.ChunkSize)
+	}, 5*time.Second, 10*time.Millisecond)
+	leecher.Stop()
+	require.False(leecher.Done())
+	stat, err := os.Stat(partPath)
+	require.NoError(err)
+	partial := stat.Size()
+	require.Less(partial, int64(len(snapshot)))
+
+	// the download is resumed from other peers after a restart
+	pipes := connect(t, "peer2", &leecher, seeder, 1<<30)
+	for peer, rw := range connect(t, "peer3", &leecher, seeder, 1<<30) {
+		pipes[peer] = rw
+	}
+	leecher = newLeecher(pipes)
+	require.NoError(leecher.RegisterPeer("peer2"))
+	require.NoError(leecher.RegisterPeer("peer3"))
+	leecher.Start()
+	defer leecher.Stop()
+	select {
+	case data := <-imported:
+		require.True(bytes.Equal(snapshot, data))
+	case <-time.After(5 * time.Second):
+		t.Fatal("snapshot isn't downloaded")
+	}
+	require.Eventually(leecher.Done, time.Second, 10*time.Millisecond)
+	_, err = os.Stat(partPath)
+	require.True(os.IsNotExist(err))
+}
+
+func TestLeecherWrongRoot(t *testing.T) {
+	require := require.New(t)
+	cfg := LiteConfig()
+	cfg.Dir = t.TempDir()
+	root := hash.Of([]byte("root"))
+	var requested snapstream.Request
+	leecher := New(cfg, Callbacks{
+		RequestInfo: func(string) error {
+			return nil
+		},
+		RequestChunk: func(_ string, r snapstream.Request) error {
+			requested = r
+			return nil
+		},
+		VerifiedRoot: func(epoch idx.Epoch) (hash.Hash, bool) {
+			return root, true
+		},
+		Timeout: func(string) {},
+	})
+	require.NoError(leecher.RegisterPeer("peer"))
+	info := snapstream.Info{Epoch: 1, Root: root, Size: 10 * cfg.ChunkSize}
+	require.NoError(leecher.NotifyInfo("peer", []snapstream.Info{{Epoch: 2, Root: hash.Of([]byte("unverified")), Size: 1}, info}))
+	require.Nil(leecher.tick())
+	require.Equal(info.Root, requested.Root)
+
+	// a chunk with other state root is refused
+	wrong := fakeSnapshot(hash.Of([]byte("wrong")), int(cfg.ChunkSize))
+	err := leecher.NotifyChunk("peer", snapstream.Response{Epoch: info.Epoch, Root: info.Root, Data: wrong})
+	require.Equal(ErrWrongRoot, err)
+}
@@ -0,0 +1,25 @@
+package snapseeder
+
+import (
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+)
+
+type Config struct {
+	// Dir is the directory of the served snapshots
+	Dir string
+	// Period is the number of epochs between the snapshots, 0 disables making of snapshots
+	Period idx.Epoch
+	// Keep is the number of the latest snapshots to serve
+	Keep int
+	// MaxChunkSize is the maximum size of a requested chunk
+	MaxChunkSize uint64
+}
+
+// DefaultConfig returns default seeder config
+func DefaultConfig() Config {
+	return Config{
+		Period:       0,
+		Keep:         2,
+		MaxChunkSize: 4 * 1024 * 1024,
+	}
+}
@@ -0,0 +1,242 @@
+package snapseeder
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/Fantom-foundation/go-opera/gossip/protocols/snapshots/snapstream"
+)
+
+var (
+	ErrTooLargeChunk = errors.New("too large chunk is requested")
+	ErrWrongOffset   = errors.New("chunk offset is out of the snapshot")
+)
+
+const snapshotExt = ".snapshot"
+
+// Seeder makes live state snapshots at epoch boundaries and serves them by chunks
+type Seeder struct {
+	cfg      Config
+	callback Callbacks
+
+	mu        sync.RWMutex
+	snapshots []snapstream.Info
+
+	making int32
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+type Callbacks struct {
+	// Export writes the live state snapshot at the beginning of the epoch and returns its root
+	Export func(ctx context.Context, epoch idx.Epoch, w io.Writer) (hash.Hash, error)
+}
+
+func New(cfg Config, callback Callbacks) *Seeder {
+	s := &Seeder{
+		cfg:      cfg,
+		callback: callback,
+	}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	return s
+}
+
+// Start loads the snapshots made before
+func (s *Seeder) Start() {
+	if s.cfg.Period == 0 {
+		return
+	}
+	if err := s.load(); err != nil {
+		log.Warn("Failed to load state snapshots", "dir", s.cfg.Dir, "err", err)
+	}
+}
+
+// Stop interrupts making of a snapshot
+func (s *Seeder) Stop() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+func (s *Seeder) path(info snapstream.Info) string {
+	return filepath.Join(s.cfg.Dir, fmt.Sprintf("%d-%s%s", info.Epoch, common.Bytes2Hex(info.Root.Bytes()), snapshotExt))
+}
+
+func (s *Seeder) load() error {
+	entries, err := os.ReadDir(s.cfg.Dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	snapshots := make([]snapstream.Info, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasSuffix(name, snapshotExt) {
+			// unfinished snapshot
+			_ = os.Remove(filepath.Join(s.cfg.Dir, name))
+			continue
+		}
+		var info snapstream.Info
+		var root string
+		if _, err := fmt.Sscanf(strings.Replace(strings.TrimSuffix(name, snapshotExt), "-", " ", 1), "%d %s", &info.Epoch, &root); err != nil {
+			continue
+		}
+		info.Root = hash.BytesToHash(common.FromHex(root))
+		stat, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		info.Size = uint64(stat.Size())
+		snapshots = append(snapshots, info)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.snapshots = snapshots
+	s.prune()
+	return nil
+}
+
+// prune removes the snapshots except the latest ones
+func (s *Seeder) prune() {
+	sort.Slice(s.snapshots, func(i, j int) bool {
+		return s.snapshots[i].Epoch > s.snapshots[j].Epoch
+	})
+	for len(s.snapshots) > s.cfg.Keep {
+		old := s.snapshots[len(s.snapshots)-1]
+		s.snapshots = s.snapshots[:len(s.snapshots)-1]
+		if err := os.Remove(s.path(old)); err != nil {
+			log.Warn("Failed to remove state snapshot", "epoch", old.Epoch, "err", err)
+		}
+	}
+}
+
+// OnNewEpoch makes the snapshot of the epoch beginning in background if it's time to
+func (s *Seeder) OnNewEpoch(epoch idx.Epoch) {
+	if s.cfg.Period == 0 || epoch%s.cfg.Period != 0 {
+		return
+	}
+	if !atomic.CompareAndSwapInt32(&s.making, 0, 1) {
+		log.Warn("Previous state snapshot is still being made", "epoch", epoch)
+		return
+	}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer atomic.StoreInt32(&s.making, 0)
+		if err := s.make(epoch); err != nil {
+			log.Warn("Failed to make state snapshot", "epoch", epoch, "err", err)
+		}
+	}()
+}
+
+func (s *Seeder) make(epoch idx.Epoch) error {
+	if err := os.MkdirAll(s.cfg.Dir, 0700); err != nil {
+		return err
+	}
+	tmpPath := filepath.Join(s.cfg.Dir, fmt.Sprintf("%d.tmp", epoch))
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpPath)
+
+	log.Info("Making state snapshot", "epoch", epoch)
+	w := bufio.NewWriterSize(f, 4*1024*1024)
+	root, err := s.callback.Export(s.ctx, epoch, w)
+	if err == nil {
+		err = w.Flush()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	stat, err := os.Stat(tmpPath)
+	if err != nil {
+		return err
+	}
+	info := snapstream.Info{
+		Epoch: epoch,
+		Root:  root,
+		Size:  uint64(stat.Size()),
+	}
+	if err := os.Rename(tmpPath, s.path(info)); err != nil {
+		return err
+	}
+	log.Info("State snapshot is made", "epoch", epoch, "root", root, "size", info.Size)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.snapshots = append(s.snapshots, info)
+	s.prune()
+	return nil
+}
+
+// Snapshots returns the served snapshots
+func (s *Seeder) Snapshots() []snapstream.Info {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append(make([]snapstream.Info, 0, len(s.snapshots)), s.snapshots...)
+}
+
+func (s *Seeder) get(epoch idx.Epoch, root hash.Hash) (snapstream.Info, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, info := range s.snapshots {
+		if info.Epoch == epoch && info.Root == root {
+			return info, true
+		}
+	}
+	return snapstream.Info{}, false
+}
+
+// ReadChunk returns the requested chunk.
+// The returned error is caused by a malformed request.
+func (s *Seeder) ReadChunk(r snapstream.Request) (snapstream.Response, error) {
+	res := snapstream.Response{
+		Epoch:  r.Epoch,
+		Root:   r.Root,
+		Offset: r.Offset,
+	}
+	if r.Size > s.cfg.MaxChunkSize {
+		return res, ErrTooLargeChunk
+	}
+	info, ok := s.get(r.Epoch, r.Root)
+	if !ok {
+		// snapshot isn't served
+		return res, nil
+	}
+	if r.Offset+r.Size > info.Size || r.Offset+r.Size < r.Offset {
+		return res, ErrWrongOffset
+	}
+	f, err := os.Open(s.path(info))
+	if err != nil {
+		log.Warn("Failed to open state snapshot", "epoch", info.Epoch, "err", err)
+		return res, nil
+	}
+	defer f.Close()
+	data := make([]byte, r.Size)
+	if _, err := f.ReadAt(data, int64(r.Offset)); err != nil {
+		log.Warn("Failed to read state snapshot", "epoch", info.Epoch, "err", err)
+		return res, nil
+	}
+	res.Data = data
+	return res, nil
+}
@@ -0,0 +1,62 @@
+package snapstream
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+)
+
+// Info describes the live state snapshot served by a peer.
+// The snapshot is the live state at the beginning of the epoch.
+type Info struct {
+	Epoch idx.Epoch
+	Root  hash.Hash
+	Size  uint64
+}
+
+func (i Info) String() string {
+	return fmt.Sprintf("{Epoch=%d,Root=%s,Size=%d}", i.Epoch, i.Root.String(), i.Size)
+}
+
+// InfoRequest requests the snapshots served by a peer
+type InfoRequest struct{}
+
+// InfoResponse contains the snapshots served by a peer
+type InfoResponse struct {
+	Snapshots []Info
+}
+
+// Request requests a chunk of the snapshot
+type Request struct {
+	Epoch  idx.Epoch
+	Root   hash.Hash
+	Offset uint64
+	Size   uint64
+}
+
+// Response contains the requested chunk, the data is empty if the snapshot isn't served anymore
+type Response struct {
+	Epoch  idx.Epoch
+	Root   hash.Hash
+	Offset uint64
+	Data   []byte
+}
+
+// HeaderSize is the size of the snapshot header.
+// The live state snapshot starts with the magic number, the format version, the hash type and the state hash.
+const HeaderSize = len(magic) + 3 + 32
+
+const magic = "Fantom-World-State"
+
+var ErrMalformedHeader = errors.New("malformed snapshot header")
+
+// ReadRoot returns the state root hash from the snapshot header
+func ReadRoot(header []byte) (hash.Hash, error) {
+	if len(header) < HeaderSize || !bytes.HasPrefix(header, []byte(magic)) || header[len(magic)+1] != 'H' {
+		return hash.Hash{}, ErrMalformedHeader
+	}
+	return hash.BytesToHash(header[len(magic)+3 : HeaderSize]), nil
+}
@@ -9,6 +9,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	carmen "github.com/Fantom-foundation/Carmen/go/state"
 	"github.com/Fantom-foundation/lachesis-base/hash"
 	"github.com/Fantom-foundation/lachesis-base/inter/dag"
 	"github.com/Fantom-foundation/lachesis-base/inter/idx"
@@ -19,6 +20,7 @@ import (
 	"github.com/ethereum/go-ethereum/eth/tracers"
 	notify "github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/p2p/dnsdisc"
@@ -45,19 +47,40 @@ import (
 	"github.com/Fantom-foundation/go-opera/gossip/gasprice"
 	"github.com/Fantom-foundation/go-opera/gossip/proclogger"
 	"github.com/Fantom-foundation/go-opera/inter"
+	"github.com/Fantom-foundation/go-opera/inter/iblockproc"
 	"github.com/Fantom-foundation/go-opera/logger"
 	"github.com/Fantom-foundation/go-opera/utils/signers/gsignercache"
 	"github.com/Fantom-foundation/go-opera/utils/txtime"
 	"github.com/Fantom-foundation/go-opera/utils/wgmutex"
 	"github.com/Fantom-foundation/go-opera/valkeystore"
+	"github.com/Fantom-foundation/go-opera/valkeystore/protection"
 	"github.com/Fantom-foundation/go-opera/vecmt"
 )
 
+// feedQueueSize is the number of notifications which may wait for the slow subscribers
+// before the following notifications are dropped
+const feedQueueSize = 1024
+
+var droppedFeedNotificationsMeter = metrics.GetOrRegisterMeter("chain/feed/dropped", nil)
+
+type feedNotification struct {
+	feed  *notify.Feed
+	value interface{}
+}
+
 type ServiceFeed struct {
 	scope notify.SubscriptionScope
 
+	// notifications are passed to the subscribers by the separate routine,
+	// so the slow subscribers don't block the events processing
+	queue chan feedNotification
+	wg    sync.WaitGroup
+	quit  chan struct{}
+
 	newEpoch        notify.Feed
+	newEpochState   notify.Feed
 	newEmittedEvent notify.Feed
+	newEvent        notify.Feed
 	newBlock        notify.Feed
 	newLogs         notify.Feed
 }
@@ -66,10 +89,18 @@ func (f *ServiceFeed) SubscribeNewEpoch(ch chan<- idx.Epoch) notify.Subscription
 	return f.scope.Track(f.newEpoch.Subscribe(ch))
 }
 
+func (f *ServiceFeed) SubscribeNewEpochState(ch chan<- *iblockproc.EpochState) notify.Subscription {
+	return f.scope.Track(f.newEpochState.Subscribe(ch))
+}
+
 func (f *ServiceFeed) SubscribeNewEmitted(ch chan<- *inter.EventPayload) notify.Subscription {
 	return f.scope.Track(f.newEmittedEvent.Subscribe(ch))
 }
 
+func (f *ServiceFeed) SubscribeNewEvent(ch chan<- *inter.EventPayload) notify.Subscription {
+	return f.scope.Track(f.newEvent.Subscribe(ch))
+}
+
 func (f *ServiceFeed) SubscribeNewBlock(ch chan<- evmcore.ChainHeadNotify) notify.Subscription {
 	return f.scope.Track(f.newBlock.Subscribe(ch))
 }
@@ -78,6 +109,39 @@ func (f *ServiceFeed) SubscribeNewLogs(ch chan<- []*types.Log) notify.Subscripti
 	return f.scope.Track(f.newLogs.Subscribe(ch))
 }
 
+// sendAsync queues the notification without blocking, the notification is dropped if the queue is full
+func (f *ServiceFeed) sendAsync(feed *notify.Feed, value interface{}) {
+	select {
+	case f.queue <- feedNotification{feed, value}:
+	default:
+		droppedFeedNotificationsMeter.Mark(1)
+	}
+}
+
+func (f *ServiceFeed) loop() {
+	defer f.wg.Done()
+	for {
+		select {
+		case n := <-f.queue:
+			n.feed.Send(n.value)
+		case <-f.quit:
+			return
+		}
+	}
+}
+
+func (f *ServiceFeed) Start() {
+	f.wg.Add(1)
+	go f.loop()
+}
+
+// Stop closes the subscriptions, which also releases the blocked sending
+func (f *ServiceFeed) Stop() {
+	f.scope.Close()
+	close(f.quit)
+	f.wg.Wait()
+}
+
 type BlockProc struct {
 	SealerModule     blockproc.SealerModule
 	TxListenerModule blockproc.TxListenerModule
@@ -115,6 +179,7 @@ type Service struct {
 	engineMu            *sync.RWMutex
 	emitters            []*emitter.Emitter
 	txpool              TxPool
+	privateTxPool       *evmcore.PrivateTxPool
 	heavyCheckReader    HeavyCheckReader
 	gasPowerCheckReader GasPowerCheckReader
 	checkers            *eventcheck.Checkers
@@ -150,6 +215,9 @@ type Service struct {
 
 	tflusher PeriodicFlusher
 
+	historyPruner *historyPruner
+	traceIndexer  *traceIndexer
+
 	bootstrapping bool
 
 	logger.Instance
@@ -191,6 +259,8 @@ func newService(config Config, store *Store, blockProc BlockProc, engine lachesi
 		procLogger:         proclogger.NewLogger(),
 		Instance:           logger.New("gossip-service"),
 	}
+	svc.feed.queue = make(chan feedNotification, feedQueueSize)
+	svc.feed.quit = make(chan struct{})
 
 	svc.blockProcTasks = workers.New(new(sync.WaitGroup), svc.blockProcTasksDone, 1)
 
@@ -227,6 +297,7 @@ func newService(config Config, store *Store, blockProc BlockProc, engine lachesi
 	// create tx pool
 	stateReader := svc.GetEvmStateReader()
 	svc.txpool = newTxPool(stateReader)
+	svc.privateTxPool = evmcore.NewPrivateTxPool(config.PrivateTxPool, stateReader, txSigner)
 
 	// init dialCandidates
 	dnsclient := dnsdisc.NewClient(dnsdisc.Config{})
@@ -255,6 +326,7 @@ func newService(config Config, store *Store, blockProc BlockProc, engine lachesi
 			BR:               svc.ProcessFullBlockRecord,
 			EV:               svc.ProcessEpochVote,
 			ER:               svc.ProcessFullEpochRecord,
+			Snapshot:         svc.ImportStateSnapshot,
 		},
 	})
 	if err != nil {
@@ -271,6 +343,8 @@ func newService(config Config, store *Store, blockProc BlockProc, engine lachesi
 
 	svc.verWatcher = verwatcher.New(netVerStore)
 	svc.tflusher = svc.makePeriodicFlusher()
+	svc.historyPruner = newHistoryPruner(store, svc.engineMu)
+	svc.traceIndexer = newTraceIndexer(store, svc.engineMu)
 
 	return svc, nil
 }
@@ -323,16 +397,18 @@ func (s *Service) makePeriodicFlusher() PeriodicFlusher {
 	}
 }
 
-func (s *Service) EmitterWorld(signer valkeystore.SignerI) emitter.World {
+func (s *Service) EmitterWorld(signer valkeystore.SignerI, protectionDB *protection.DB) emitter.World {
 	return emitter.World{
 		External: &emitterWorld{
 			emitterWorldProc: emitterWorldProc{s},
 			emitterWorldRead: emitterWorldRead{s.store},
 			WgMutex:          wgmutex.New(s.engineMu, &s.blockProcWg),
 		},
-		TxPool:   s.txpool,
-		Signer:   signer,
-		TxSigner: s.EthAPI.signer,
+		TxPool:        s.txpool,
+		PrivateTxPool: s.privateTxPool,
+		Signer:        signer,
+		TxSigner:      s.EthAPI.signer,
+		Protection:    protectionDB,
 	}
 }
 
@@ -408,6 +484,11 @@ func (s *Service) APIs() []rpc.API {
 			Version:   "1.0",
 			Service:   s.netRPCService,
 			Public:    true,
+		}, {
+			Namespace: "admin",
+			Version:   "1.0",
+			Service:   NewPrivateAdminAPI(s),
+			Public:    false,
 		},
 	}...)
 
@@ -433,11 +514,31 @@ func (s *Service) Start() error {
 	s.tflusher.Start()
 	blockState := s.store.GetBlockState()
 	if s.store.evm.CheckLiveStateHash(blockState.LastBlock.Idx, blockState.FinalizedStateRoot) != nil {
-		return errors.New("fullsync isn't possible because state root is missing")
+		if s.config.SyncMode != SyncModeLLR {
+			return errors.New("fullsync isn't possible because state root is missing")
+		}
+		// the live state is imported from a snapshot, the history up to it is filled by the LLR sync
+		s.handler.syncStatus.Set(ssLlr)
+		log.Info("Starting LLR sync", "epoch", s.store.GetEpoch(), "block", blockState.LastBlock.Idx)
+	} else if s.config.SyncMode == SyncModeLLR && s.store.GetLatestBlockIndex() == *s.store.GetGenesisBlockIndex() {
+		if s.store.cfg.EVM.StateDb.Archive != carmen.NoArchive {
+			return errors.New("state snapshot sync isn't possible if the archive is enabled")
+		}
+		// the live state is downloaded from peers, the history up to it is filled by the LLR sync
+		s.handler.syncStatus.Set(ssLlr)
+		s.handler.snapshotSync = true
+		log.Info("Starting LLR sync from a state snapshot")
+	}
+	if s.config.Protocol.SnapSeeder.Period != 0 && s.store.cfg.EVM.StateDb.Archive == carmen.NoArchive {
+		return errors.New("state snapshots for peers may be made only if the archive is enabled")
 	}
 
 	// start blocks processor
 	s.blockProcTasks.Start(1)
+	s.feed.Start()
+
+	s.historyPruner.Start()
+	s.traceIndexer.Start()
 
 	// start p2p
 	StartENRUpdater(s, s.p2pServer.LocalNode())
@@ -475,10 +576,12 @@ func (s *Service) Stop() error {
 	s.operaDialCandidates.Close()
 
 	s.handler.Stop()
-	s.feed.scope.Close()
+	s.feed.Stop()
 	s.gpo.Stop()
-	// it's safe to stop tflusher only before locking engineMu
+	// it's safe to stop tflusher, history pruner and trace indexer only before locking engineMu
 	s.tflusher.Stop()
+	s.historyPruner.Stop()
+	s.traceIndexer.Stop()
 
 	// flush the state at exit, after all the routines stopped
 	s.engineMu.Lock()
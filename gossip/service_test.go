@@ -0,0 +1,44 @@
+package gossip
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Fantom-foundation/go-opera/inter"
+)
+
+func TestServiceFeedSendAsync(t *testing.T) {
+	feed := &ServiceFeed{
+		queue: make(chan feedNotification, 2),
+		quit:  make(chan struct{}),
+	}
+	feed.Start()
+
+	// the subscriber never reads the notifications
+	stalled := make(chan *inter.EventPayload)
+	feed.SubscribeNewEvent(stalled)
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 10; i++ {
+			feed.sendAsync(&feed.newEvent, &inter.EventPayload{})
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("sending is blocked by the stalled subscriber")
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		feed.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("feed isn't stopped")
+	}
+}
@@ -41,6 +41,7 @@ type Store struct {
 
 		// P2P-only
 		HighestLamport kvdb.Store `table:"l"`
+		PeerBans       kvdb.Store `table:"N"`
 
 		// Network version
 		NetworkVersion kvdb.Store `table:"V"`
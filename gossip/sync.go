@@ -1,19 +1,68 @@
 package gossip
 
 import (
+	"fmt"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/p2p/enode"
 	"math/rand"
 	"sync/atomic"
+	"time"
 )
 
 var isMaybeSyncedGauge = metrics.GetOrRegisterGauge("chain/maybeSynced", nil)
 
+const (
+	// SyncModeDAG syncs the node by downloading and processing the DAG of events
+	SyncModeDAG = "dag"
+	// SyncModeLLR syncs the node by downloading the epoch packs and block records verified by LLR votes,
+	// the state is imported from a snapshot instead of being reproduced by processing the historic events
+	SyncModeLLR = "llr"
+)
+
+type syncStage uint32
+
+const (
+	ssEvents syncStage = iota
+	ssLlr
+)
+
+func (s syncStage) String() string {
+	switch s {
+	case ssEvents:
+		return SyncModeDAG
+	case ssLlr:
+		return SyncModeLLR
+	default:
+		return "unknown"
+	}
+}
+
 type syncStatus struct {
+	stage       uint32
 	maybeSynced uint32
 }
 
+func (ss *syncStatus) Stage() syncStage {
+	return syncStage(atomic.LoadUint32(&ss.stage))
+}
+
+func (ss *syncStatus) Is(s ...syncStage) bool {
+	stage := ss.Stage()
+	for _, v := range s {
+		if stage == v {
+			return true
+		}
+	}
+	return false
+}
+
+func (ss *syncStatus) Set(s syncStage) {
+	atomic.StoreUint32(&ss.stage, uint32(s))
+}
+
 func (ss *syncStatus) MaybeSynced() bool {
 	return atomic.LoadUint32(&ss.maybeSynced) != 0
 }
@@ -24,19 +73,146 @@ func (ss *syncStatus) MarkMaybeSynced() {
 }
 
 func (ss *syncStatus) AcceptEvents() bool {
-	return true
+	return ss.Is(ssEvents)
 }
 
 func (ss *syncStatus) AcceptBlockRecords() bool {
-	return false
+	return ss.Is(ssLlr)
 }
 
 func (ss *syncStatus) AcceptTxs() bool {
-	return ss.MaybeSynced()
+	return ss.MaybeSynced() && ss.Is(ssEvents)
 }
 
 func (ss *syncStatus) RequestLLR() bool {
-	return ss.MaybeSynced()
+	return ss.Is(ssLlr) || ss.MaybeSynced()
+}
+
+const (
+	llrSyncCheckPeriod = 3 * time.Second
+	llrSyncLogPeriod   = time.Minute
+)
+
+// llrSyncProgress tracks the LLR sync until the history is filled up to the epoch which starts with the live state
+type llrSyncProgress struct {
+	timeout time.Duration
+	// llrState returns the current LLR state
+	llrState func() LlrState
+	// liveStateEpoch returns the last block before the epoch if the epoch starts with the live state
+	liveStateEpoch func(epoch idx.Epoch) (idx.Block, bool)
+
+	checked     idx.Epoch
+	target      idx.Epoch
+	targetBlock idx.Block
+
+	last       LlrState
+	progressed time.Time
+	caughtUp   time.Time
+}
+
+func newLlrSyncProgress(timeout time.Duration, checked idx.Epoch, now time.Time, llrState func() LlrState, liveStateEpoch func(idx.Epoch) (idx.Block, bool)) *llrSyncProgress {
+	return &llrSyncProgress{
+		timeout:        timeout,
+		llrState:       llrState,
+		liveStateEpoch: liveStateEpoch,
+		checked:        checked,
+		progressed:     now,
+	}
+}
+
+// wait postpones the timeouts while the LLR sync is paused
+func (p *llrSyncProgress) wait(now time.Time) {
+	p.progressed = now
+	p.caughtUp = time.Time{}
+}
+
+// update checks the LLR sync progress, returns true if the history preceding the live state is filled.
+// An error is returned if the LLR sync doesn't progress, or if all the decided epochs are filled
+// but none of them starts with the live state, for longer than the timeout.
+func (p *llrSyncProgress) update(now time.Time) (bool, error) {
+	llrs := p.llrState()
+	if llrs != p.last {
+		p.last = llrs
+		p.progressed = now
+	}
+	// find the epoch whose starting state is the live state
+	for p.target == 0 && p.checked+1 < llrs.LowestEpochToFill {
+		p.checked++
+		if block, ok := p.liveStateEpoch(p.checked); ok {
+			p.target, p.targetBlock = p.checked, block
+			log.Info("State snapshot is verified by LLR votes", "epoch", p.target, "block", p.targetBlock)
+		}
+	}
+	if p.target != 0 && llrs.LowestBlockToFill > p.targetBlock {
+		return true, nil
+	}
+	if p.timeout == 0 {
+		return false, nil
+	}
+	if p.target == 0 && llrs.LowestEpochToFill >= llrs.LowestEpochToDecide {
+		if p.caughtUp.IsZero() {
+			p.caughtUp = now
+		}
+		if now.Sub(p.caughtUp) > p.timeout {
+			return false, fmt.Errorf("state snapshot doesn't match the state of any epoch up to %d verified by LLR votes", p.checked)
+		}
+	} else {
+		p.caughtUp = time.Time{}
+	}
+	if now.Sub(p.progressed) > p.timeout {
+		return false, fmt.Errorf("no LLR sync progress for %v", common.PrettyDuration(now.Sub(p.progressed)))
+	}
+	return false, nil
+}
+
+// llrSyncLoop waits until the LLR sync fills the history up to the imported state snapshot
+// and switches to the DAG sync from the epoch which starts with the snapshot state
+func (h *handler) llrSyncLoop() {
+	defer h.loopsWg.Done()
+	ticker := time.NewTicker(llrSyncCheckPeriod)
+	defer ticker.Stop()
+
+	progress := newLlrSyncProgress(h.config.LlrSyncTimeout, h.store.GetEpoch(), time.Now(), h.store.GetLlrState, func(epoch idx.Epoch) (idx.Block, bool) {
+		bs, _ := h.store.GetHistoryBlockEpochState(epoch)
+		if bs == nil || h.store.EvmStore().CheckLiveStateHash(bs.LastBlock.Idx, bs.FinalizedStateRoot) != nil {
+			return 0, false
+		}
+		return bs.LastBlock.Idx, true
+	})
+	logged := time.Now()
+	for {
+		select {
+		case <-ticker.C:
+			now := time.Now()
+			if h.snapshotSync && !h.snapLeecher.Done() {
+				// wait for the live state to be downloaded
+				progress.wait(now)
+				continue
+			}
+			filled, err := progress.update(now)
+			if err != nil {
+				log.Crit("LLR sync failed, the state snapshot has to be re-imported", "err", err)
+			}
+			if now.Sub(logged) >= llrSyncLogPeriod {
+				llrs := progress.last
+				log.Info("LLR sync progress", "epochs", llrs.LowestEpochToFill-1, "blocks", llrs.LowestBlockToFill-1,
+					"snapshotEpoch", progress.target, "snapshotBlock", progress.targetBlock)
+				logged = now
+			}
+			if !filled {
+				continue
+			}
+			if err := h.process.SwitchEpochTo(progress.target); err != nil {
+				log.Error("Failed to switch to the DAG sync", "epoch", progress.target, "err", err)
+				continue
+			}
+			h.syncStatus.Set(ssEvents)
+			log.Info("Switched from LLR sync to DAG sync", "epoch", progress.target, "block", progress.targetBlock)
+			return
+		case <-h.quitProgressBradcast:
+			return
+		}
+	}
 }
 
 type txsync struct {
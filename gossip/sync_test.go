@@ -0,0 +1,89 @@
+package gossip
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLlrSyncProgress(t *testing.T) {
+	const timeout = time.Minute
+	start := time.Unix(1000, 0)
+
+	// the live state is imported from the snapshot of the epoch 5, which starts after the block 50
+	newProgress := func(llrs *LlrState, snapshotEpoch idx.Epoch) *llrSyncProgress {
+		return newLlrSyncProgress(timeout, 1, start, func() LlrState {
+			return *llrs
+		}, func(epoch idx.Epoch) (idx.Block, bool) {
+			return 50, epoch == snapshotEpoch
+		})
+	}
+
+	t.Run("handoff", func(t *testing.T) {
+		require := require.New(t)
+		llrs := LlrState{LowestEpochToDecide: 10, LowestEpochToFill: 3, LowestBlockToFill: 20}
+		p := newProgress(&llrs, 5)
+
+		filled, err := p.update(start)
+		require.NoError(err)
+		require.False(filled)
+		require.Equal(idx.Epoch(0), p.target)
+
+		// the snapshot epoch is filled, but the preceding blocks aren't
+		llrs.LowestEpochToFill, llrs.LowestBlockToFill = 7, 40
+		filled, err = p.update(start.Add(time.Second))
+		require.NoError(err)
+		require.False(filled)
+		require.Equal(idx.Epoch(5), p.target)
+		require.Equal(idx.Block(50), p.targetBlock)
+
+		llrs.LowestBlockToFill = 50
+		filled, err = p.update(start.Add(2 * time.Second))
+		require.NoError(err)
+		require.False(filled)
+
+		llrs.LowestBlockToFill = 51
+		filled, err = p.update(start.Add(3 * time.Second))
+		require.NoError(err)
+		require.True(filled)
+	})
+
+	t.Run("no progress", func(t *testing.T) {
+		require := require.New(t)
+		llrs := LlrState{LowestEpochToDecide: 10, LowestEpochToFill: 3, LowestBlockToFill: 20}
+		p := newProgress(&llrs, 5)
+
+		_, err := p.update(start.Add(timeout))
+		require.NoError(err)
+		// the timeouts are postponed while the snapshot is downloaded
+		p.wait(start.Add(2 * timeout))
+		_, err = p.update(start.Add(3 * timeout))
+		require.NoError(err)
+		_, err = p.update(start.Add(3*timeout + time.Second))
+		require.Error(err)
+	})
+
+	t.Run("snapshot not verified", func(t *testing.T) {
+		require := require.New(t)
+		llrs := LlrState{LowestEpochToDecide: 10, LowestEpochToFill: 3, LowestBlockToFill: 20}
+		p := newProgress(&llrs, 0)
+
+		// new epochs keep being filled, but none of them starts with the live state
+		for i := 0; i < 10; i++ {
+			llrs.LowestEpochToFill++
+			llrs.LowestBlockToFill += 10
+			llrs.LowestEpochToDecide = llrs.LowestEpochToFill
+			now := start.Add(time.Duration(i) * timeout / 4)
+			_, err := p.update(now)
+			if now.Sub(start) <= timeout {
+				require.NoError(err)
+			} else {
+				require.Error(err)
+				return
+			}
+		}
+		t.Fatal("unverified snapshot must fail the LLR sync")
+	})
+}
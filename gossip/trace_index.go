@@ -0,0 +1,246 @@
+package gossip
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	carmen "github.com/Fantom-foundation/Carmen/go/state"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/Fantom-foundation/go-opera/evmcore"
+	"github.com/Fantom-foundation/go-opera/gossip/evmstore"
+	"github.com/Fantom-foundation/go-opera/inter/state"
+	"github.com/Fantom-foundation/go-opera/opera"
+	"github.com/Fantom-foundation/go-opera/txtrace"
+)
+
+const traceIndexPeriod = time.Second
+
+// txStartNotifier passes hashes of the executed transactions to the calls recorder
+type txStartNotifier struct {
+	state.StateDB
+	recorder *txtrace.CallAddressRecorder
+}
+
+func (s *txStartNotifier) Prepare(txHash common.Hash, txIndex int) {
+	s.recorder.StartTx(txHash)
+	s.StateDB.Prepare(txHash, txIndex)
+}
+
+// blockTracer replays historic blocks on top of the archive state and records calls of their transactions
+type blockTracer struct {
+	store    *Store
+	reader   *EvmStateReader
+	recorder *txtrace.CallAddressRecorder
+	vmConfig vm.Config
+}
+
+func newBlockTracer(store *Store) *blockTracer {
+	recorder := txtrace.NewCallAddressRecorder()
+	vmConfig := opera.DefaultVMConfig
+	vmConfig.Debug = true
+	vmConfig.Tracer = recorder
+	return &blockTracer{
+		store:    store,
+		reader:   &EvmStateReader{store: store},
+		recorder: recorder,
+		vmConfig: vmConfig,
+	}
+}
+
+// traceBlock returns the calls of the block transactions, indexed by positions of the transactions in the block
+func (t *blockTracer) traceBlock(n idx.Block) (map[uint32][]evmstore.TraceIndexRecord, error) {
+	block := t.reader.GetBlock(common.Hash{}, uint64(n))
+	if block == nil {
+		return nil, fmt.Errorf("block %d not found", n)
+	}
+	parent := t.reader.GetHeader(common.Hash{}, uint64(n-1))
+	if parent == nil {
+		return nil, fmt.Errorf("block %d not found", n-1)
+	}
+	statedb, err := t.store.evm.GetRpcStateDb(big.NewInt(int64(n-1)), parent.Root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get state of block %d: %w", n-1, err)
+	}
+	defer statedb.Release()
+
+	t.recorder.Reset()
+	var gasUsed uint64
+	receipts, _, _, err := evmcore.NewStateProcessor(blockEvmChainConfig(t.store, n), t.reader).Process(block, &txStartNotifier{statedb, t.recorder}, t.vmConfig, &gasUsed, func(*types.Log) {})
+	if err != nil {
+		return nil, fmt.Errorf("failed to replay block %d: %w", n, err)
+	}
+	txs := make(map[uint32][]evmstore.TraceIndexRecord, len(receipts))
+	for _, r := range receipts {
+		txs[uint32(r.TransactionIndex)] = traceIndexRecords(t.recorder.TakeTx(r.TxHash))
+	}
+	return txs, nil
+}
+
+func traceIndexRecords(calls []txtrace.CallAddresses) []evmstore.TraceIndexRecord {
+	records := make([]evmstore.TraceIndexRecord, len(calls))
+	for i, call := range calls {
+		records[i] = evmstore.TraceIndexRecord{
+			From:         call.From,
+			To:           call.To,
+			TraceAddress: call.TraceAddress,
+		}
+	}
+	return records
+}
+
+func (s *Store) setBlockTraceIndex(n idx.Block, txs map[uint32][]evmstore.TraceIndexRecord) error {
+	for txIndex, records := range txs {
+		s.evm.SetTraceIndex(n, txIndex, records)
+	}
+	return s.evm.ExtendTraceIndexRange(n, n)
+}
+
+// IndexTraces replays historic blocks on top of the archive state and indexes calls of their transactions.
+// The EvmStore has to be opened with the archive enabled. The blocks have to be adjacent to the already indexed range.
+func IndexTraces(ctx context.Context, store *Store, from, to idx.Block) (err error) {
+	if from == 0 {
+		from = 1 // genesis block is not traceable
+	}
+	if err := store.evm.CheckTraceIndexRange(from, to); err != nil {
+		return err
+	}
+	defer func() {
+		if flushErr := store.Commit(); err == nil {
+			err = flushErr
+		}
+	}()
+	tracer := newBlockTracer(store)
+
+	start, reported := time.Now(), time.Now()
+	for n := from; n <= to; n++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		txs, err := tracer.traceBlock(n)
+		if err != nil {
+			return err
+		}
+		if err := store.setBlockTraceIndex(n, txs); err != nil {
+			return err
+		}
+
+		if store.IsCommitNeeded() {
+			if err := store.Commit(); err != nil {
+				return err
+			}
+		}
+		if time.Since(reported) >= 8*time.Second {
+			log.Info("Indexing traces", "block", n, "last", to, "elapsed", common.PrettyDuration(time.Since(start)))
+			reported = time.Now()
+		}
+	}
+	log.Info("Traces indexed", "first", from, "last", to, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// traceIndexer follows the new blocks and indexes calls of their transactions.
+// The blocks are replayed on top of the archive state once they are archived,
+// so the blocks processing isn't slowed down by the tracing.
+type traceIndexer struct {
+	store    *Store
+	engineMu *sync.RWMutex
+	tracer   *blockTracer
+	enabled  bool
+
+	wg   sync.WaitGroup
+	quit chan struct{}
+}
+
+func newTraceIndexer(store *Store, engineMu *sync.RWMutex) *traceIndexer {
+	return &traceIndexer{
+		store:    store,
+		engineMu: engineMu,
+		tracer:   newBlockTracer(store),
+		enabled:  store.evm.TraceIndexingEnabled(),
+		quit:     make(chan struct{}),
+	}
+}
+
+// next returns the first block which is not indexed yet
+func (t *traceIndexer) next() idx.Block {
+	if _, last, ok := t.store.evm.GetTraceIndexRange(); ok {
+		return last + 1
+	}
+	// only the new blocks are indexed, older blocks may be indexed by the backfill command
+	return t.store.GetLatestBlockIndex() + 1
+}
+
+// archived returns the last block whose state is available in the archive
+func (t *traceIndexer) archived() (idx.Block, error) {
+	height, empty, err := t.store.evm.GetArchiveBlockHeight()
+	if err != nil || empty {
+		return 0, err
+	}
+	return idx.Block(height), nil
+}
+
+// indexBlock traces the block without locking the engine, only the resulting records are written under the lock
+func (t *traceIndexer) indexBlock(n idx.Block) error {
+	txs, err := t.tracer.traceBlock(n)
+	if err != nil {
+		return err
+	}
+	t.engineMu.Lock()
+	defer t.engineMu.Unlock()
+	return t.store.setBlockTraceIndex(n, txs)
+}
+
+func (t *traceIndexer) loop() {
+	defer t.wg.Done()
+	ticker := time.NewTicker(traceIndexPeriod)
+	defer ticker.Stop()
+	next := t.next()
+	for {
+		archived, err := t.archived()
+		if err != nil {
+			log.Error("Failed to get archive block height", "err", err)
+		}
+		// the block is replayed on top of the state of the previous block
+		for ; err == nil && next <= archived+1 && next <= t.store.GetLatestBlockIndex(); next++ {
+			select {
+			case <-t.quit:
+				return
+			default:
+			}
+			if err = t.indexBlock(next); err != nil {
+				log.Error("Failed to index traces", "block", next, "err", err)
+				break
+			}
+		}
+		select {
+		case <-ticker.C:
+		case <-t.quit:
+			return
+		}
+	}
+}
+
+func (t *traceIndexer) Start() {
+	if !t.enabled {
+		return
+	}
+	if t.store.cfg.EVM.StateDb.Archive == carmen.NoArchive {
+		log.Warn("Trace indexing requires the archive, new blocks aren't indexed")
+		return
+	}
+	t.wg.Add(1)
+	go t.loop()
+}
+
+func (t *traceIndexer) Stop() {
+	close(t.quit)
+	t.wg.Wait()
+}
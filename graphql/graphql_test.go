@@ -0,0 +1,27 @@
+package graphql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaResolvers(t *testing.T) {
+	// all the schema fields must have a resolver
+	_, err := newSchema(nil)
+	require.NoError(t, err)
+}
+
+func TestLongUnmarshalGraphQL(t *testing.T) {
+	require := require.New(t)
+
+	var l Long
+	require.NoError(l.UnmarshalGraphQL("1234"))
+	require.Equal(Long(1234), l)
+	require.NoError(l.UnmarshalGraphQL(int32(5)))
+	require.Equal(Long(5), l)
+	require.NoError(l.UnmarshalGraphQL(int64(7)))
+	require.Equal(Long(7), l)
+	require.Error(l.UnmarshalGraphQL(1.5))
+	require.Error(l.UnmarshalGraphQL("0x10"))
+}
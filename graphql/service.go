@@ -0,0 +1,108 @@
+// Copyright 2019 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package graphql
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/graph-gophers/graphql-go"
+
+	"github.com/Fantom-foundation/go-opera/ethapi"
+	"github.com/Fantom-foundation/go-opera/gossip/filters"
+)
+
+// Backend is the node backend the GraphQL service is served from.
+// Logs are searched by the same filter as the eth_getLogs uses.
+type Backend interface {
+	ethapi.Backend
+	filters.Backend
+}
+
+// fullBackend is the backend together with the logs filter config
+type fullBackend interface {
+	Backend
+	FilterConfig() filters.Config
+}
+
+type configuredBackend struct {
+	Backend
+	filterCfg filters.Config
+}
+
+func (b *configuredBackend) FilterConfig() filters.Config {
+	return b.filterCfg
+}
+
+type handler struct {
+	Schema *graphql.Schema
+}
+
+func (h handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var params struct {
+		Query         string                 `json:"query"`
+		OperationName string                 `json:"operationName"`
+		Variables     map[string]interface{} `json:"variables"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	response := h.Schema.Exec(r.Context(), params.Query, params.OperationName, params.Variables)
+	responseJSON, err := json.Marshal(response)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if len(response.Errors) > 0 {
+		w.WriteHeader(http.StatusBadRequest)
+	}
+	_, _ = w.Write(responseJSON)
+}
+
+// New constructs a new GraphQL service instance and registers it on the HTTP server of the node.
+func New(stack *node.Node, backend Backend, filterCfg filters.Config, cors, vhosts []string) error {
+	if backend == nil {
+		panic("missing backend")
+	}
+	return newHandler(stack, &configuredBackend{backend, filterCfg}, cors, vhosts)
+}
+
+// newSchema parses the GraphQL schema with the resolvers served from the backend
+func newSchema(backend fullBackend) (*graphql.Schema, error) {
+	return graphql.ParseSchema(schema, &Resolver{backend})
+}
+
+// newHandler returns a new `http.Handler` that will answer GraphQL queries.
+// It additionally exports an interactive query browser on the /graphql/ui endpoint.
+func newHandler(stack *node.Node, backend fullBackend, cors, vhosts []string) error {
+	s, err := newSchema(backend)
+	if err != nil {
+		return err
+	}
+	h := handler{Schema: s}
+	handler := node.NewHTTPHandlerStack(h, cors, vhosts)
+
+	stack.RegisterHandler("GraphQL UI", "/graphql/ui", GraphiQL{})
+	stack.RegisterHandler("GraphQL", "/graphql", handler)
+	stack.RegisterHandler("GraphQL", "/graphql/", handler)
+
+	return nil
+}
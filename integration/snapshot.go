@@ -0,0 +1,53 @@
+package integration
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	carmen "github.com/Fantom-foundation/Carmen/go/state"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// ImportStateSnapshot replaces the live state by the live state snapshot exported by another node.
+// The snapshot is imported only if the node hasn't processed any block after the genesis,
+// the history up to the snapshot is filled by the LLR sync afterwards.
+func ImportStateSnapshot(chaindataDir string, cfg Configs, snapshotPath string) error {
+	if isEmpty(chaindataDir) || isInterrupted(chaindataDir) {
+		return errors.New("database is empty or the genesis import interrupted")
+	}
+	if cfg.OperaStore.EVM.StateDb.Archive != carmen.NoArchive {
+		return errors.New("state snapshot may be imported only if the archive is disabled")
+	}
+
+	dbs, err := GetDbProducer(chaindataDir, cfg.DBs.RuntimeCache)
+	if err != nil {
+		return err
+	}
+	defer dbs.Close()
+	gdb, cdb, err := getStores(dbs, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to get stores: %w", err)
+	}
+	defer cdb.Close()
+	defer gdb.Close()
+
+	genesisBlock := gdb.GetGenesisBlockIndex()
+	if genesisBlock == nil || gdb.GetLatestBlockIndex() != *genesisBlock {
+		log.Warn("State snapshot is skipped as blocks after the genesis are already processed", "block", gdb.GetLatestBlockIndex())
+		return nil
+	}
+
+	f, err := os.Open(snapshotPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	log.Info("Importing state snapshot", "file", snapshotPath)
+	if err := gdb.EvmStore().ReplaceLiveWorldState(f); err != nil {
+		return err
+	}
+	log.Info("State snapshot is imported")
+	return nil
+}
@@ -93,6 +93,9 @@ func (u Upgrades) EncodeRLP(w io.Writer) error {
 	if u.Llr {
 		bitmap.V |= llrBit
 	}
+	if u.Ed25519 {
+		bitmap.V |= ed25519Bit
+	}
 	return rlp.Encode(w, &bitmap)
 }
 
@@ -108,6 +111,7 @@ func (u *Upgrades) DecodeRLP(s *rlp.Stream) error {
 	u.Berlin = (bitmap.V & berlinBit) != 0
 	u.London = (bitmap.V & londonBit) != 0
 	u.Llr = (bitmap.V & llrBit) != 0
+	u.Ed25519 = (bitmap.V & ed25519Bit) != 0
 	return nil
 }
 
@@ -79,6 +79,22 @@ func TestRulesLondonRLP(t *testing.T) {
 	require.True(decodedRules.Upgrades.London)
 }
 
+func TestRulesEd25519RLP(t *testing.T) {
+	rules := MainNetRules()
+	rules.Upgrades.Ed25519 = true
+	require := require.New(t)
+
+	b, err := rlp.EncodeToBytes(rules)
+	require.NoError(err)
+
+	decodedRules := Rules{}
+	require.NoError(rlp.DecodeBytes(b, &decodedRules))
+
+	require.Equal(rules.String(), decodedRules.String())
+	require.True(decodedRules.Upgrades.Ed25519)
+	require.False(decodedRules.Upgrades.Llr)
+}
+
 func TestRulesBerlinCompatibilityRLP(t *testing.T) {
 	require := require.New(t)
 
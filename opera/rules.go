@@ -22,6 +22,7 @@ const (
 	berlinBit              = 1 << 0
 	londonBit              = 1 << 1
 	llrBit                 = 1 << 2
+	ed25519Bit             = 1 << 3
 )
 
 var DefaultVMConfig = vm.Config{
@@ -116,6 +117,8 @@ type Upgrades struct {
 	Berlin bool
 	London bool
 	Llr    bool
+	// Ed25519 allows validators to sign events by ed25519 keys
+	Ed25519 bool
 }
 
 type UpgradeHeight struct {
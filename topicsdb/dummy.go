@@ -18,6 +18,10 @@ func (n dummyIndex) Push(recs ...*types.Log) error {
 	return nil
 }
 
+func (n dummyIndex) Delete(recs ...*types.Log) error {
+	return nil
+}
+
 func (n dummyIndex) Close() {}
 
 func (n dummyIndex) WrapTablesAsBatched() (unwrap func()) {
@@ -21,6 +21,7 @@ var (
 type Index interface {
 	FindInBlocks(ctx context.Context, from, to idx.Block, pattern [][]common.Hash) (logs []*types.Log, err error)
 	Push(recs ...*types.Log) error
+	Delete(recs ...*types.Log) error
 	Close()
 
 	WrapTablesAsBatched() (unwrap func())
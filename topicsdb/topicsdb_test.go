@@ -363,6 +363,48 @@ func TestIndexSearchSimple(t *testing.T) {
 
 }
 
+func TestIndexDelete(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	var (
+		hash1 = common.BytesToHash([]byte("topic1"))
+		hash2 = common.BytesToHash([]byte("topic2"))
+		addr  = randAddress()
+	)
+	testdata := []*types.Log{{
+		BlockNumber: 1,
+		Address:     addr,
+		Topics:      []common.Hash{hash1, hash2},
+	}, {
+		BlockNumber: 2,
+		Address:     addr,
+		Topics:      []common.Hash{hash1},
+	},
+	}
+
+	db := memorydb.New()
+	index := newIndex(db)
+	require.NoError(index.Push(testdata...))
+
+	got, err := index.FindInBlocks(nil, 0, 0xffffffff, [][]common.Hash{{addr.Hash()}, {hash1}})
+	require.NoError(err)
+	require.Equal(2, len(got))
+
+	require.NoError(index.Delete(testdata[0]))
+
+	got, err = index.FindInBlocks(nil, 0, 0xffffffff, [][]common.Hash{{addr.Hash()}, {hash1}})
+	require.NoError(err)
+	require.Equal(1, len(got))
+	require.Equal(uint64(2), got[0].BlockNumber)
+
+	require.NoError(index.Delete(testdata[1]))
+
+	it := db.NewIterator(nil, nil)
+	defer it.Release()
+	require.False(it.Next(), "all the records have to be deleted")
+}
+
 func TestMaxTopicsCount(t *testing.T) {
 	logger.SetTestMode(t)
 
@@ -0,0 +1,115 @@
+package txtrace
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// CallAddresses holds the caller and callee of a single call in a transaction call tree
+type CallAddresses struct {
+	From         *common.Address
+	To           *common.Address
+	TraceAddress []uint32
+}
+
+// CallAddressRecorder is a lightweight tracer which records only addresses of calls.
+// The hash of the executed transaction has to be set by StartTx before its execution,
+// call trees of executed transactions are kept by their hashes and have to be taken by TakeTx.
+type CallAddressRecorder struct {
+	txs    map[common.Hash][]CallAddresses
+	txHash common.Hash
+
+	// trace address of the current call and number of subcalls of each active frame
+	traceAddress []uint32
+	subcalls     []uint32
+}
+
+// NewCallAddressRecorder creates new recorder of calls addresses
+func NewCallAddressRecorder() *CallAddressRecorder {
+	return &CallAddressRecorder{
+		txs: make(map[common.Hash][]CallAddresses),
+	}
+}
+
+// StartTx sets the hash of the transaction which is executed next
+func (r *CallAddressRecorder) StartTx(txHash common.Hash) {
+	r.txHash = txHash
+}
+
+// TakeTx returns calls of the transaction and removes them from the recorder.
+// Nil is returned if the transaction wasn't executed.
+func (r *CallAddressRecorder) TakeTx(txHash common.Hash) []CallAddresses {
+	calls := r.txs[txHash]
+	delete(r.txs, txHash)
+	return calls
+}
+
+// Reset drops all recorded transactions
+func (r *CallAddressRecorder) Reset() {
+	r.txs = make(map[common.Hash][]CallAddresses)
+	r.txHash = common.Hash{}
+	r.traceAddress = nil
+	r.subcalls = nil
+}
+
+func (r *CallAddressRecorder) addCall(from, to common.Address, create bool) {
+	call := CallAddresses{
+		From:         &from,
+		TraceAddress: append(make([]uint32, 0, len(r.traceAddress)), r.traceAddress...),
+	}
+	if !create {
+		call.To = &to
+	}
+	r.txs[r.txHash] = append(r.txs[r.txHash], call)
+}
+
+// CaptureStart starts recording of a new transaction
+func (r *CallAddressRecorder) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	r.txs[r.txHash] = make([]CallAddresses, 0, 1)
+	r.traceAddress = r.traceAddress[:0]
+	r.subcalls = append(r.subcalls[:0], 0)
+	r.addCall(from, to, create)
+}
+
+// CaptureState is not used as only calls are recorded
+func (r *CallAddressRecorder) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+}
+
+// CaptureEnter records the inner call
+func (r *CallAddressRecorder) CaptureEnter(op vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	if len(r.subcalls) == 0 {
+		return
+	}
+	parent := len(r.subcalls) - 1
+	r.traceAddress = append(r.traceAddress, r.subcalls[parent])
+	r.subcalls[parent]++
+	r.subcalls = append(r.subcalls, 0)
+	if op == vm.SELFDESTRUCT {
+		// beneficiary of the destructed contract is not a callee
+		r.addCall(from, to, true)
+		return
+	}
+	r.addCall(from, to, op == vm.CREATE || op == vm.CREATE2)
+}
+
+// CaptureExit closes the inner call
+func (r *CallAddressRecorder) CaptureExit(output []byte, gasUsed uint64, err error) {
+	if len(r.subcalls) <= 1 {
+		return
+	}
+	r.subcalls = r.subcalls[:len(r.subcalls)-1]
+	r.traceAddress = r.traceAddress[:len(r.traceAddress)-1]
+}
+
+// CaptureFault is not used as only calls are recorded
+func (r *CallAddressRecorder) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+}
+
+// CaptureEnd finishes recording of the transaction
+func (r *CallAddressRecorder) CaptureEnd(output []byte, gasUsed uint64, t time.Duration, err error) {
+	r.traceAddress = r.traceAddress[:0]
+	r.subcalls = r.subcalls[:0]
+}
@@ -0,0 +1,60 @@
+package txtrace
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// Tests that the recorded calls are paired with the receipts by the tx hashes,
+// even if some of the txs are skipped without being executed.
+func TestCallAddressRecorderPairing(t *testing.T) {
+	var (
+		a, b, c  = common.HexToAddress("0xa"), common.HexToAddress("0xb"), common.HexToAddress("0xc")
+		tx1      = common.HexToHash("0x1")
+		skipped  = common.HexToHash("0x2")
+		tx3      = common.HexToHash("0x3")
+		recorder = NewCallAddressRecorder()
+	)
+
+	recorder.StartTx(tx1)
+	recorder.CaptureStart(nil, a, b, false, nil, 0, nil)
+	recorder.CaptureEnd(nil, 0, 0, nil)
+
+	// the tx is rejected before its execution
+	recorder.StartTx(skipped)
+
+	recorder.StartTx(tx3)
+	recorder.CaptureStart(nil, b, c, false, nil, 0, nil)
+	recorder.CaptureEnter(vm.CALL, c, a, nil, 0, nil)
+	recorder.CaptureEnter(vm.CREATE, a, b, nil, 0, nil)
+	recorder.CaptureExit(nil, 0, nil)
+	recorder.CaptureExit(nil, 0, nil)
+	recorder.CaptureEnter(vm.STATICCALL, c, b, nil, 0, nil)
+	recorder.CaptureExit(nil, 0, nil)
+	recorder.CaptureEnd(nil, 0, 0, nil)
+
+	// receipts are taken in any order
+	if calls := recorder.TakeTx(tx3); !reflect.DeepEqual(calls, []CallAddresses{
+		{From: &b, To: &c, TraceAddress: []uint32{}},
+		{From: &c, To: &a, TraceAddress: []uint32{0}},
+		{From: &a, TraceAddress: []uint32{0, 0}},
+		{From: &c, To: &b, TraceAddress: []uint32{1}},
+	}) {
+		t.Fatalf("unexpected calls of tx3: %+v", calls)
+	}
+	if calls := recorder.TakeTx(skipped); calls != nil {
+		t.Fatalf("unexpected calls of skipped tx: %+v", calls)
+	}
+	if calls := recorder.TakeTx(tx1); !reflect.DeepEqual(calls, []CallAddresses{
+		{From: &a, To: &b, TraceAddress: []uint32{}},
+	}) {
+		t.Fatalf("unexpected calls of tx1: %+v", calls)
+	}
+	// the calls are taken only once
+	if calls := recorder.TakeTx(tx1); calls != nil {
+		t.Fatalf("unexpected calls of taken tx: %+v", calls)
+	}
+}
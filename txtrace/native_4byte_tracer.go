@@ -0,0 +1,79 @@
+package txtrace
+
+import (
+	"encoding/json"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+)
+
+// fourByteTracer is the native implementation of the 4byteTracer.
+// It counts 4byte method selectors of the calls together with the size
+// of the call data, keyed as "0x<selector>-<size>".
+type fourByteTracer struct {
+	nativeInterrupt
+	ids         map[string]int
+	precompiles []common.Address
+}
+
+func newFourByteTracer(ctx *tracers.Context, cfg json.RawMessage) (NativeTracer, error) {
+	return &fourByteTracer{
+		ids: make(map[string]int),
+	}, nil
+}
+
+func (t *fourByteTracer) store(input []byte) {
+	if len(input) < 4 {
+		return
+	}
+	key := hexutil.Encode(input[:4]) + "-" + strconv.Itoa(len(input)-4)
+	t.ids[key]++
+}
+
+// CaptureStart counts the selector of the outer call
+func (t *fourByteTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	t.env = env
+	t.precompiles = activePrecompiles(env)
+	t.store(input)
+}
+
+// CaptureState only checks if the tracing was stopped
+func (t *fourByteTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	t.stopped()
+}
+
+// CaptureEnter counts the selector of an inner call, precompiled contracts are skipped
+func (t *fourByteTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	switch typ {
+	case vm.CALL, vm.CALLCODE, vm.DELEGATECALL, vm.STATICCALL:
+		if !isPrecompiled(t.precompiles, to) {
+			t.store(input)
+		}
+	}
+}
+
+// CaptureExit is not used
+func (t *fourByteTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+}
+
+// CaptureFault is not used
+func (t *fourByteTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+}
+
+// CaptureEnd is not used
+func (t *fourByteTracer) CaptureEnd(output []byte, gasUsed uint64, d time.Duration, err error) {
+}
+
+// GetResult returns the counts of the selectors
+func (t *fourByteTracer) GetResult() (json.RawMessage, error) {
+	res, err := json.Marshal(t.ids)
+	if err != nil {
+		return nil, err
+	}
+	return res, t.stopReason()
+}
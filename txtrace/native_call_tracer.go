@@ -0,0 +1,189 @@
+package txtrace
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+)
+
+// callTracerConfig are the options of the native callTracer
+type callTracerConfig struct {
+	OnlyTopCall bool `json:"onlyTopCall"` // If true, inner calls are not traced
+	WithLog     bool `json:"withLog"`     // If true, logs emitted by calls are included
+}
+
+// callLog is a log emitted by a traced call
+type callLog struct {
+	Address common.Address `json:"address"`
+	Topics  []common.Hash  `json:"topics"`
+	Data    hexutil.Bytes  `json:"data"`
+}
+
+// callFrame is a single call of the call tree, fields are in the order of the JS callTracer
+type callFrame struct {
+	Type    string          `json:"type"`
+	From    common.Address  `json:"from"`
+	To      *common.Address `json:"to,omitempty"`
+	Value   *hexutil.Big    `json:"value,omitempty"`
+	Gas     hexutil.Uint64  `json:"gas"`
+	GasUsed hexutil.Uint64  `json:"gasUsed"`
+	Input   hexutil.Bytes   `json:"input"`
+	Output  hexutil.Bytes   `json:"output,omitempty"`
+	Error   string          `json:"error,omitempty"`
+	Calls   []callFrame     `json:"calls,omitempty"`
+	Logs    []callLog       `json:"logs,omitempty"`
+}
+
+// processOutput sets the result of the call. Output of a failed call
+// is kept only if the call was reverted.
+func (f *callFrame) processOutput(output []byte, err error) {
+	if err == nil {
+		f.Output = common.CopyBytes(output)
+		return
+	}
+	f.Error = err.Error()
+	if f.Type == vm.CREATE.String() || f.Type == vm.CREATE2.String() {
+		f.To = nil
+	}
+	if errors.Is(err, vm.ErrExecutionReverted) && len(output) > 0 {
+		f.Output = common.CopyBytes(output)
+	}
+}
+
+// clearFailedLogs removes logs of failed calls, as they are reverted
+func clearFailedLogs(f *callFrame, parentFailed bool) {
+	failed := f.Error != "" || parentFailed
+	if failed {
+		f.Logs = nil
+	}
+	for i := range f.Calls {
+		clearFailedLogs(&f.Calls[i], failed)
+	}
+}
+
+// callTracer is the native implementation of the callTracer
+type callTracer struct {
+	nativeInterrupt
+	config    callTracerConfig
+	callstack []callFrame
+}
+
+func newCallTracer(ctx *tracers.Context, cfg json.RawMessage) (NativeTracer, error) {
+	t := &callTracer{}
+	if err := parseNativeTracerConfig(cfg, &t.config); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// CaptureStart creates the top-level call frame
+func (t *callTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	t.env = env
+	typ := vm.CALL
+	if create {
+		typ = vm.CREATE
+	}
+	if value == nil {
+		value = new(big.Int)
+	}
+	t.callstack = []callFrame{{
+		Type:  typ.String(),
+		From:  from,
+		To:    &to,
+		Value: (*hexutil.Big)(new(big.Int).Set(value)),
+		Gas:   hexutil.Uint64(gas),
+		Input: common.CopyBytes(input),
+	}}
+}
+
+// CaptureState collects logs emitted by calls if enabled
+func (t *callTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	if t.stopped() || !t.config.WithLog || err != nil {
+		return
+	}
+	if op < vm.LOG0 || op > vm.LOG4 || depth != len(t.callstack) {
+		return
+	}
+	stack := scope.Stack
+	offset, size := stack.Back(0), stack.Back(1)
+	topics := make([]common.Hash, int(op-vm.LOG0))
+	for i := range topics {
+		topics[i] = common.Hash(stack.Back(2 + i).Bytes32())
+	}
+	frame := &t.callstack[len(t.callstack)-1]
+	frame.Logs = append(frame.Logs, callLog{
+		Address: scope.Contract.Address(),
+		Topics:  topics,
+		Data:    scope.Memory.GetCopy(int64(offset.Uint64()), int64(size.Uint64())),
+	})
+}
+
+// CaptureEnter opens a frame of an inner call
+func (t *callTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	if t.config.OnlyTopCall || len(t.callstack) == 0 {
+		return
+	}
+	frame := callFrame{
+		Type:  typ.String(),
+		From:  from,
+		To:    &to,
+		Gas:   hexutil.Uint64(gas),
+		Input: common.CopyBytes(input),
+	}
+	if value != nil {
+		frame.Value = (*hexutil.Big)(new(big.Int).Set(value))
+	}
+	t.callstack = append(t.callstack, frame)
+}
+
+// CaptureExit closes the frame of an inner call and adds it into its parent
+func (t *callTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+	if t.config.OnlyTopCall || len(t.callstack) <= 1 {
+		return
+	}
+	size := len(t.callstack)
+	frame := t.callstack[size-1]
+	t.callstack = t.callstack[:size-1]
+	frame.GasUsed = hexutil.Uint64(gasUsed)
+	frame.processOutput(output, err)
+	parent := &t.callstack[size-2]
+	parent.Calls = append(parent.Calls, frame)
+}
+
+// CaptureFault is not used, errors are reported by CaptureExit and CaptureEnd
+func (t *callTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+}
+
+// CaptureEnd finishes the top-level call frame
+func (t *callTracer) CaptureEnd(output []byte, gasUsed uint64, _ time.Duration, err error) {
+	if len(t.callstack) == 0 {
+		return
+	}
+	frame := &t.callstack[0]
+	frame.GasUsed = hexutil.Uint64(gasUsed)
+	frame.processOutput(output, err)
+}
+
+// GetResult returns the call tree
+func (t *callTracer) GetResult() (json.RawMessage, error) {
+	if len(t.callstack) != 1 {
+		if reason := t.stopReason(); reason != nil {
+			return nil, reason
+		}
+		return nil, errors.New("incorrect number of top-level calls")
+	}
+	if t.config.WithLog {
+		clearFailedLogs(&t.callstack[0], false)
+	}
+	res, err := json.Marshal(t.callstack[0])
+	if err != nil {
+		return nil, err
+	}
+	return res, t.stopReason()
+}
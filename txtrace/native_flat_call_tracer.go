@@ -0,0 +1,89 @@
+package txtrace
+
+import (
+	"encoding/json"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+)
+
+// flatCallTracer is a native tracer returning the flat list of calls
+// in the same format as the trace_* methods
+type flatCallTracer struct {
+	nativeInterrupt
+	ctx    *tracers.Context
+	logger *TraceStructLogger
+}
+
+func newFlatCallTracer(ctx *tracers.Context, cfg json.RawMessage) (NativeTracer, error) {
+	return &flatCallTracer{ctx: ctx}, nil
+}
+
+// CaptureStart creates the trace logger for the traced call
+func (t *flatCallTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	t.env = env
+	t.logger = &TraceStructLogger{
+		from:      from,
+		blockHash: t.ctx.BlockHash,
+		tx:        t.ctx.TxHash,
+		txIndex:   uint(t.ctx.TxIndex),
+	}
+	if !create {
+		t.logger.to = &to
+	}
+	if value != nil {
+		t.logger.value.Set(value)
+	}
+	if env.Context.BlockNumber != nil {
+		t.logger.blockNumber.Set(env.Context.BlockNumber)
+	}
+	t.logger.CaptureStart(env, from, to, create, input, gas, value)
+}
+
+// CaptureState only checks if the tracing was stopped
+func (t *flatCallTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	t.stopped()
+}
+
+// CaptureEnter records the inner call
+func (t *flatCallTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	if t.logger != nil {
+		t.logger.CaptureEnter(typ, from, to, input, gas, value)
+	}
+}
+
+// CaptureExit records the result of the inner call
+func (t *flatCallTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+	if t.logger != nil {
+		t.logger.CaptureExit(output, gasUsed, err)
+	}
+}
+
+// CaptureFault is not used, errors are reported by CaptureExit and CaptureEnd
+func (t *flatCallTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+}
+
+// CaptureEnd records the result of the traced call
+func (t *flatCallTracer) CaptureEnd(output []byte, gasUsed uint64, d time.Duration, err error) {
+	if t.logger != nil {
+		t.logger.CaptureEnd(output, gasUsed, d, err)
+	}
+}
+
+// GetResult returns the flat list of calls
+func (t *flatCallTracer) GetResult() (json.RawMessage, error) {
+	traces := make([]ActionTrace, 0)
+	if t.logger != nil {
+		if actions := t.logger.GetResult(); actions != nil {
+			traces = *actions
+		}
+	}
+	res, err := json.Marshal(traces)
+	if err != nil {
+		return nil, err
+	}
+	return res, t.stopReason()
+}
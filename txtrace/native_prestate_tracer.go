@@ -0,0 +1,268 @@
+package txtrace
+
+import (
+	"encoding/json"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+
+	"github.com/Fantom-foundation/go-opera/evmcore"
+)
+
+// prestateTracerConfig are the options of the native prestateTracer
+type prestateTracerConfig struct {
+	DiffMode bool `json:"diffMode"` // If true, the pre and post state of modified accounts is returned
+}
+
+// prestateAccount is the state of an account touched by the traced call
+type prestateAccount struct {
+	Balance *hexutil.Big                `json:"balance,omitempty"`
+	Code    hexutil.Bytes               `json:"code,omitempty"`
+	Nonce   uint64                      `json:"nonce,omitempty"`
+	Storage map[common.Hash]common.Hash `json:"storage,omitempty"`
+}
+
+// empty checks if the account has no balance, nonce, code and storage
+func (a *prestateAccount) empty() bool {
+	if a.Balance != nil && a.Balance.ToInt().Sign() != 0 {
+		return false
+	}
+	if a.Nonce != 0 || len(a.Code) != 0 {
+		return false
+	}
+	for _, value := range a.Storage {
+		if value != (common.Hash{}) {
+			return false
+		}
+	}
+	return true
+}
+
+// prestateDiff is the result of the prestateTracer in the diff mode
+type prestateDiff struct {
+	Pre  map[common.Address]*prestateAccount `json:"pre"`
+	Post map[common.Address]*prestateAccount `json:"post"`
+}
+
+// prestateTracer is the native implementation of the prestateTracer.
+// It collects the state of all the accounts touched by the traced call
+// as it was before the call. In the diff mode, the state of modified
+// accounts before and after the call is returned.
+type prestateTracer struct {
+	nativeInterrupt
+	config  prestateTracerConfig
+	pre     map[common.Address]*prestateAccount
+	created map[common.Address]bool
+}
+
+func newPrestateTracer(ctx *tracers.Context, cfg json.RawMessage) (NativeTracer, error) {
+	t := &prestateTracer{
+		pre:     make(map[common.Address]*prestateAccount),
+		created: make(map[common.Address]bool),
+	}
+	if err := parseNativeTracerConfig(cfg, &t.config); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// lookupAccount records the current state of the account if it was not touched yet
+func (t *prestateTracer) lookupAccount(addr common.Address) {
+	if _, ok := t.pre[addr]; ok {
+		return
+	}
+	db := t.env.StateDB
+	t.pre[addr] = &prestateAccount{
+		Balance: (*hexutil.Big)(new(big.Int).Set(db.GetBalance(addr))),
+		Nonce:   db.GetNonce(addr),
+		Code:    common.CopyBytes(db.GetCode(addr)),
+		Storage: make(map[common.Hash]common.Hash),
+	}
+}
+
+// lookupStorage records the current value of the storage slot if it was not touched yet
+func (t *prestateTracer) lookupStorage(addr common.Address, key common.Hash) {
+	t.lookupAccount(addr)
+	storage := t.pre[addr].Storage
+	if _, ok := storage[key]; ok {
+		return
+	}
+	storage[key] = t.env.StateDB.GetState(addr, key)
+}
+
+// CaptureStart records the sender and the recipient. Their state is already
+// modified by the nonce increment, gas purchase and value transfer, so it is
+// reverted to the state before the call.
+func (t *prestateTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	t.env = env
+	t.lookupAccount(from)
+	t.lookupAccount(to)
+	if value == nil {
+		value = new(big.Int)
+	}
+
+	toBalance := t.pre[to].Balance.ToInt()
+	toBalance.Sub(toBalance, value)
+
+	fromAcc := t.pre[from]
+	fromBalance := fromAcc.Balance.ToInt()
+	fromBalance.Add(fromBalance, value)
+	if gasPrice := env.TxContext.GasPrice; gasPrice != nil {
+		intrinsicGas, _ := evmcore.IntrinsicGas(input, nil, create)
+		gasLimit := new(big.Int).SetUint64(gas + intrinsicGas)
+		fromBalance.Add(fromBalance, gasLimit.Mul(gasLimit, gasPrice))
+	}
+	if fromAcc.Nonce > 0 {
+		fromAcc.Nonce--
+	}
+
+	if create {
+		t.created[to] = true
+	}
+}
+
+// CaptureState records accounts and storage slots touched by the instruction
+func (t *prestateTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	if t.stopped() || err != nil {
+		return
+	}
+	stack := scope.Stack
+	caller := scope.Contract.Address()
+	switch op {
+	case vm.SLOAD, vm.SSTORE:
+		t.lookupStorage(caller, common.Hash(stack.Back(0).Bytes32()))
+	case vm.EXTCODECOPY, vm.EXTCODESIZE, vm.EXTCODEHASH, vm.BALANCE, vm.SELFDESTRUCT:
+		t.lookupAccount(common.Address(stack.Back(0).Bytes20()))
+	case vm.CALL, vm.CALLCODE, vm.DELEGATECALL, vm.STATICCALL:
+		t.lookupAccount(common.Address(stack.Back(1).Bytes20()))
+	case vm.CREATE:
+		t.lookupAccount(crypto.CreateAddress(caller, env.StateDB.GetNonce(caller)))
+	case vm.CREATE2:
+		offset, size := stack.Back(1), stack.Back(2)
+		initCode := scope.Memory.GetCopy(int64(offset.Uint64()), int64(size.Uint64()))
+		salt := stack.Back(3).Bytes32()
+		t.lookupAccount(crypto.CreateAddress2(caller, salt, crypto.Keccak256(initCode)))
+	}
+}
+
+// CaptureEnter is not used, touched accounts are recorded by CaptureState
+func (t *prestateTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+}
+
+// CaptureExit is not used
+func (t *prestateTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+}
+
+// CaptureFault is not used
+func (t *prestateTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+}
+
+// CaptureEnd is not used
+func (t *prestateTracer) CaptureEnd(output []byte, gasUsed uint64, d time.Duration, err error) {
+}
+
+// GetResult returns the pre state of touched accounts, or the pre and post
+// state of modified accounts in the diff mode
+func (t *prestateTracer) GetResult() (json.RawMessage, error) {
+	var (
+		res json.RawMessage
+		err error
+	)
+	if t.config.DiffMode {
+		res, err = json.Marshal(t.diff())
+	} else {
+		pre := make(map[common.Address]*prestateAccount, len(t.pre))
+		for addr, acc := range t.pre {
+			if !t.created[addr] {
+				pre[addr] = acc
+			}
+		}
+		res, err = json.Marshal(pre)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, t.stopReason()
+}
+
+// diff compares the recorded pre state with the current state
+func (t *prestateTracer) diff() *prestateDiff {
+	res := &prestateDiff{
+		Pre:  make(map[common.Address]*prestateAccount),
+		Post: make(map[common.Address]*prestateAccount),
+	}
+	if t.env == nil {
+		return res
+	}
+	db := t.env.StateDB
+	for addr, preAcc := range t.pre {
+		alive := db.Exist(addr) && !db.HasSuicided(addr)
+		if t.created[addr] || preAcc.empty() {
+			// the account was created by the call
+			if !alive {
+				continue
+			}
+			postAcc := &prestateAccount{
+				Balance: (*hexutil.Big)(new(big.Int).Set(db.GetBalance(addr))),
+				Nonce:   db.GetNonce(addr),
+				Code:    common.CopyBytes(db.GetCode(addr)),
+				Storage: make(map[common.Hash]common.Hash),
+			}
+			for key := range preAcc.Storage {
+				if value := db.GetState(addr, key); value != (common.Hash{}) {
+					postAcc.Storage[key] = value
+				}
+			}
+			if !postAcc.empty() {
+				res.Post[addr] = postAcc
+			}
+			continue
+		}
+		if !alive {
+			// the account was destructed by the call
+			res.Pre[addr] = preAcc
+			continue
+		}
+
+		modified := false
+		postAcc := &prestateAccount{
+			Storage: make(map[common.Hash]common.Hash),
+		}
+		if balance := db.GetBalance(addr); balance.Cmp(preAcc.Balance.ToInt()) != 0 {
+			postAcc.Balance = (*hexutil.Big)(new(big.Int).Set(balance))
+			modified = true
+		}
+		if nonce := db.GetNonce(addr); nonce != preAcc.Nonce {
+			postAcc.Nonce = nonce
+			modified = true
+		}
+		if code := db.GetCode(addr); string(code) != string(preAcc.Code) {
+			postAcc.Code = common.CopyBytes(code)
+			modified = true
+		}
+		preStorage := make(map[common.Hash]common.Hash)
+		for key, preValue := range preAcc.Storage {
+			if value := db.GetState(addr, key); value != preValue {
+				preStorage[key] = preValue
+				postAcc.Storage[key] = value
+				modified = true
+			}
+		}
+		if !modified {
+			continue
+		}
+		res.Pre[addr] = &prestateAccount{
+			Balance: preAcc.Balance,
+			Nonce:   preAcc.Nonce,
+			Code:    preAcc.Code,
+			Storage: preStorage,
+		}
+		res.Post[addr] = postAcc
+	}
+	return res
+}
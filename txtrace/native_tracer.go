@@ -0,0 +1,104 @@
+package txtrace
+
+import (
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+)
+
+// NativeTracer is a tracer implemented in Go. Native tracers are selected
+// by name the same way as the built-in JS tracers, but they are much faster
+// and they don't occupy the JS tracers limit.
+type NativeTracer interface {
+	vm.Tracer
+	// GetResult returns the JSON encoded result of the tracing
+	GetResult() (json.RawMessage, error)
+	// Stop terminates the tracing, the error is returned by GetResult
+	Stop(err error)
+}
+
+type nativeTracerConstructor func(ctx *tracers.Context, cfg json.RawMessage) (NativeTracer, error)
+
+// nativeTracers are the native tracers by their names
+var nativeTracers = map[string]nativeTracerConstructor{
+	"callTracer":     newCallTracer,
+	"flatCallTracer": newFlatCallTracer,
+	"prestateTracer": newPrestateTracer,
+	"4byteTracer":    newFourByteTracer,
+}
+
+// NewNativeTracer creates the native tracer of the given name with the given config.
+// Returns false if there is no native tracer of such name.
+func NewNativeTracer(name string, ctx *tracers.Context, cfg json.RawMessage) (NativeTracer, bool, error) {
+	constructor, ok := nativeTracers[name]
+	if !ok {
+		return nil, false, nil
+	}
+	if ctx == nil {
+		ctx = &tracers.Context{}
+	}
+	tracer, err := constructor(ctx, cfg)
+	return tracer, true, err
+}
+
+// parseNativeTracerConfig decodes the tracer config, the config is optional
+func parseNativeTracerConfig(cfg json.RawMessage, v interface{}) error {
+	if len(cfg) == 0 || string(cfg) == "null" {
+		return nil
+	}
+	return json.Unmarshal(cfg, v)
+}
+
+// nativeInterrupt implements stopping of a native tracer
+type nativeInterrupt struct {
+	env       *vm.EVM
+	interrupt uint32
+
+	reasonMu sync.Mutex
+	reason   error
+}
+
+// Stop terminates the tracing, the EVM execution is aborted on the next captured event
+func (i *nativeInterrupt) Stop(err error) {
+	i.reasonMu.Lock()
+	i.reason = err
+	i.reasonMu.Unlock()
+	atomic.StoreUint32(&i.interrupt, 1)
+}
+
+// stopReason returns the error the tracing was stopped with
+func (i *nativeInterrupt) stopReason() error {
+	i.reasonMu.Lock()
+	defer i.reasonMu.Unlock()
+	return i.reason
+}
+
+// stopped returns true if the tracing was stopped and aborts the EVM execution
+func (i *nativeInterrupt) stopped() bool {
+	if atomic.LoadUint32(&i.interrupt) == 0 {
+		return false
+	}
+	if i.env != nil {
+		i.env.Cancel()
+	}
+	return true
+}
+
+// activePrecompiles returns the precompiled contracts of the EVM rules
+func activePrecompiles(env *vm.EVM) []common.Address {
+	return vm.ActivePrecompiles(env.ChainConfig().Rules(env.Context.BlockNumber))
+}
+
+// isPrecompiled checks if the address is one of the precompiled contracts
+func isPrecompiled(precompiles []common.Address, addr common.Address) bool {
+	for _, p := range precompiles {
+		if p == addr {
+			return true
+		}
+	}
+	return false
+}
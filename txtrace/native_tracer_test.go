@@ -0,0 +1,307 @@
+package txtrace
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// TestNativeCallTracerMatchesJS Run native and JS callTracer on complex inner calls and compare results
+func TestNativeCallTracerMatchesJS(t *testing.T) {
+
+	jsTracer := getJSTracer("callTracer", t)
+	defer jsTracer.Destroy()
+	executeCallbacks(jsTracer)
+	want, err := jsTracer.GetResult()
+	if err != nil {
+		t.Fatalf("JS callTracer GetResult must not fail, error: %v", err.Error())
+	}
+
+	nativeTracer := getNativeTracer("callTracer", nil, t)
+	executeCallbacks(nativeTracer)
+	result, err := nativeTracer.GetResult()
+	if err != nil {
+		t.Fatalf("native callTracer GetResult must not fail, error: %v", err.Error())
+	}
+
+	wantIndented, err := json.MarshalIndent(want, "", "    ")
+	if err != nil {
+		t.Fatalf("problem with formating result, got error: %v", err)
+	}
+	checkTracerResult(t, result, string(wantIndented))
+}
+
+// TestNativeCallTracerOnlyTopCall Check inner calls are skipped with onlyTopCall option
+func TestNativeCallTracerOnlyTopCall(t *testing.T) {
+
+	tracer := getNativeTracer("callTracer", json.RawMessage(`{"onlyTopCall": true}`), t)
+	executeCallbacks(tracer)
+
+	want := `{
+    "type": "CALL",
+    "from": "0x0000000000000000000000000000000000000001",
+    "to": "0x0000000000000000000000000000000000000002",
+    "value": "0x5",
+    "gas": "0x3e8",
+    "gasUsed": "0x64",
+    "input": "0x2f7468610000000000000000000000000000000000000000000000000000000000000008",
+    "output": "0x45"
+}`
+	result, err := tracer.GetResult()
+	if err != nil {
+		t.Fatalf("callTracer GetResult must not fail, error: %v", err.Error())
+	}
+	checkTracerResult(t, result, want)
+}
+
+// TestNativeFourByteTracer Check selectors of calls are counted and precompiles are skipped
+func TestNativeFourByteTracer(t *testing.T) {
+
+	tracer := getNativeTracer("4byteTracer", nil, t)
+	contract := common.HexToAddress("0x100")
+
+	tracer.CaptureStart(getEVMEnv(), from, to, false, inputData, 1000, value)
+	tracer.CaptureEnter(vm.CALL, to, contract, inputDataInner, 600, value)
+	tracer.CaptureExit(outputDataInner, 400, nil)
+	tracer.CaptureEnter(vm.STATICCALL, to, contract, inputDataInner[:4], 600, nil)
+	tracer.CaptureExit(outputDataInner, 400, nil)
+	tracer.CaptureEnter(vm.CALL, to, toInner, inputDataInner, 600, value) // precompile
+	tracer.CaptureExit(outputDataInner, 400, nil)
+	tracer.CaptureEnter(vm.CREATE, to, contract, inputDataInner, 600, value)
+	tracer.CaptureExit(outputDataInner, 400, nil)
+	tracer.CaptureEnd(outputData, 100, time.Since(time.Now()), nil)
+
+	want := `{
+    "0x2f746861-0": 1,
+    "0x2f746861-32": 2
+}`
+	result, err := tracer.GetResult()
+	if err != nil {
+		t.Fatalf("4byteTracer GetResult must not fail, error: %v", err.Error())
+	}
+	checkTracerResult(t, result, want)
+}
+
+// TestNativeFlatCallTracer Check calls are returned in the format of trace_* methods
+func TestNativeFlatCallTracer(t *testing.T) {
+
+	tracer := getNativeTracer("flatCallTracer", nil, t)
+	tracer.CaptureStart(getEVMEnv(), from, to, false, inputData, 1000, value)
+	tracer.CaptureEnter(vm.CALL, to, toInner, inputDataInner, 600, value)
+	tracer.CaptureExit(outputDataInner, 400, vm.ErrOutOfGas)
+	tracer.CaptureEnd(outputData, 100, time.Since(time.Now()), nil)
+
+	want := `[
+    {
+        "action": {
+            "callType": "call",
+            "from": "0x0000000000000000000000000000000000000001",
+            "to": "0x0000000000000000000000000000000000000002",
+            "value": "0x5",
+            "gas": "0x3e8",
+            "input": "0x2f7468610000000000000000000000000000000000000000000000000000000000000008"
+        },
+        "blockHash": "0x0000000000000000000000000000000000000000000000000000000000000123",
+        "blockNumber": 123,
+        "result": {
+            "gasUsed": "0x64",
+            "output": "0x45"
+        },
+        "subtraces": 1,
+        "traceAddress": [],
+        "transactionHash": "0x0000000000000000000000000000000000000000000000000000000000000456",
+        "transactionPosition": 3,
+        "type": "call"
+    },
+    {
+        "action": {
+            "callType": "call",
+            "from": "0x0000000000000000000000000000000000000002",
+            "to": "0x0000000000000000000000000000000000000003",
+            "value": "0x5",
+            "gas": "0x258",
+            "input": "0x2f7468610000000000000000000000000000000000000000000000000000000000000002"
+        },
+        "blockHash": "0x0000000000000000000000000000000000000000000000000000000000000123",
+        "blockNumber": 123,
+        "error": "Out of gas",
+        "subtraces": 0,
+        "traceAddress": [
+            0
+        ],
+        "transactionHash": "0x0000000000000000000000000000000000000000000000000000000000000456",
+        "transactionPosition": 3,
+        "type": "call"
+    }
+]`
+	result, err := tracer.GetResult()
+	if err != nil {
+		t.Fatalf("flatCallTracer GetResult must not fail, error: %v", err.Error())
+	}
+	checkTracerResult(t, result, want)
+}
+
+// TestNativePrestateTracer Check the state before the call is restored
+func TestNativePrestateTracer(t *testing.T) {
+
+	db := newPrestateTestStateDB()
+	tracer := getNativeTracer("prestateTracer", nil, t)
+	tracer.CaptureStart(getEVMEnvWithState(db), from, to, false, inputData, 1000, value)
+	tracer.CaptureEnd(outputData, 100, time.Since(time.Now()), nil)
+
+	// from balance: 100000 + value 5 + (gas 1000 + intrinsic gas 21204) * gas price 100
+	want := `{
+    "0x0000000000000000000000000000000000000001": {
+        "balance": "0x236815"
+    },
+    "0x0000000000000000000000000000000000000002": {
+        "balance": "0x0",
+        "code": "0x6001"
+    }
+}`
+	result, err := tracer.GetResult()
+	if err != nil {
+		t.Fatalf("prestateTracer GetResult must not fail, error: %v", err.Error())
+	}
+	checkTracerResult(t, result, want)
+}
+
+// TestNativePrestateTracerDiffMode Check the state of modified accounts before and after the call
+func TestNativePrestateTracerDiffMode(t *testing.T) {
+
+	db := newPrestateTestStateDB()
+	tracer := getNativeTracer("prestateTracer", json.RawMessage(`{"diffMode": true}`), t)
+	tracer.CaptureStart(getEVMEnvWithState(db), from, to, false, inputData, 1000, value)
+	db.balances[from] = big.NewInt(99000)
+	tracer.CaptureEnd(outputData, 100, time.Since(time.Now()), nil)
+
+	want := `{
+    "pre": {
+        "0x0000000000000000000000000000000000000001": {
+            "balance": "0x236815"
+        },
+        "0x0000000000000000000000000000000000000002": {
+            "balance": "0x0",
+            "code": "0x6001"
+        }
+    },
+    "post": {
+        "0x0000000000000000000000000000000000000001": {
+            "balance": "0x182b8",
+            "nonce": 1
+        },
+        "0x0000000000000000000000000000000000000002": {
+            "balance": "0x5"
+        }
+    }
+}`
+	result, err := tracer.GetResult()
+	if err != nil {
+		t.Fatalf("prestateTracer GetResult must not fail, error: %v", err.Error())
+	}
+	checkTracerResult(t, result, want)
+}
+
+// TestNativeTracerNotFound Check unknown tracers are left for the JS engine
+func TestNativeTracerNotFound(t *testing.T) {
+	tracer, ok, err := NewNativeTracer("unknownTracer", nil, nil)
+	if tracer != nil || ok || err != nil {
+		t.Errorf("unknown native tracer must not be found")
+	}
+	if _, _, err := NewNativeTracer("callTracer", nil, json.RawMessage(`{"onlyTopCall": 1}`)); err == nil {
+		t.Errorf("invalid tracer config must fail")
+	}
+}
+
+// TestNativeTracerStop Check the tracing may be stopped concurrently and the reason is returned
+func TestNativeTracerStop(t *testing.T) {
+	tracer := getNativeTracer("callTracer", nil, t)
+	reason := errors.New("execution timeout")
+
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		tracer.Stop(reason)
+	}()
+	executeCallbacks(tracer)
+	<-stopped
+
+	if _, err := tracer.GetResult(); err != reason {
+		t.Fatalf("stopped tracer GetResult must fail with the stop reason, error: %v", err)
+	}
+}
+
+// getNativeTracer Creates new native tracer of the given name
+func getNativeTracer(name string, cfg json.RawMessage, t *testing.T) NativeTracer {
+	tracer, ok, err := NewNativeTracer(name, &tracers.Context{
+		BlockHash: blockHash,
+		TxIndex:   int(txIndex),
+		TxHash:    common.HexToHash("0x456"),
+	}, cfg)
+	if !ok || err != nil {
+		t.Fatalf("native tracer %s creation must not fail, error: %v", name, err)
+	}
+	return tracer
+}
+
+// getEVMEnvWithState Creates EVM environment on top of the given state
+func getEVMEnvWithState(db vm.StateDB) *vm.EVM {
+	return vm.NewEVM(
+		vm.BlockContext{BlockNumber: blockNumber},
+		vm.TxContext{GasPrice: gasprice},
+		db,
+		&params.ChainConfig{},
+		vm.Config{})
+}
+
+// prestateTestStateDB is a state with the sender and the recipient
+// after the nonce increment and the value transfer
+type prestateTestStateDB struct {
+	vm.StateDB
+	balances map[common.Address]*big.Int
+	nonces   map[common.Address]uint64
+	code     map[common.Address][]byte
+}
+
+func newPrestateTestStateDB() *prestateTestStateDB {
+	return &prestateTestStateDB{
+		balances: map[common.Address]*big.Int{from: big.NewInt(100000), to: big.NewInt(5)},
+		nonces:   map[common.Address]uint64{from: 1},
+		code:     map[common.Address][]byte{to: {0x60, 0x01}},
+	}
+}
+
+func (db *prestateTestStateDB) GetBalance(addr common.Address) *big.Int {
+	if balance, ok := db.balances[addr]; ok {
+		return balance
+	}
+	return new(big.Int)
+}
+
+func (db *prestateTestStateDB) GetNonce(addr common.Address) uint64 {
+	return db.nonces[addr]
+}
+
+func (db *prestateTestStateDB) GetCode(addr common.Address) []byte {
+	return db.code[addr]
+}
+
+func (db *prestateTestStateDB) GetState(addr common.Address, key common.Hash) common.Hash {
+	return common.Hash{}
+}
+
+func (db *prestateTestStateDB) Exist(addr common.Address) bool {
+	_, ok := db.balances[addr]
+	return ok
+}
+
+func (db *prestateTestStateDB) HasSuicided(addr common.Address) bool {
+	return false
+}
@@ -0,0 +1,110 @@
+package txtrace
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+func TestStateDiffMarshal(t *testing.T) {
+
+	slot := common.HexToHash("0x1")
+	diff := StateDiff{
+		from: &AccountDiff{
+			Balance: &Diff{Kind: "*", From: (*hexutil.Big)(big.NewInt(10)), To: (*hexutil.Big)(big.NewInt(5))},
+			Code:    &Diff{Kind: "="},
+			Nonce:   &Diff{Kind: "*", From: hexutil.Uint64(0), To: hexutil.Uint64(1)},
+			Storage: map[common.Hash]*Diff{
+				slot: {Kind: "*", From: common.Hash{}, To: common.HexToHash("0x2")},
+			},
+		},
+		to: &AccountDiff{
+			Balance: &Diff{Kind: "+", To: (*hexutil.Big)(big.NewInt(5))},
+			Code:    &Diff{Kind: "+", To: hexutil.Bytes{}},
+			Nonce:   &Diff{Kind: "+", To: hexutil.Uint64(0)},
+			Storage: map[common.Hash]*Diff{},
+		},
+	}
+
+	want := `{
+    "0x0000000000000000000000000000000000000001": {
+        "balance": {
+            "*": {
+                "from": "0xa",
+                "to": "0x5"
+            }
+        },
+        "code": "=",
+        "nonce": {
+            "*": {
+                "from": "0x0",
+                "to": "0x1"
+            }
+        },
+        "storage": {
+            "0x0000000000000000000000000000000000000000000000000000000000000001": {
+                "*": {
+                    "from": "0x0000000000000000000000000000000000000000000000000000000000000000",
+                    "to": "0x0000000000000000000000000000000000000000000000000000000000000002"
+                }
+            }
+        }
+    },
+    "0x0000000000000000000000000000000000000002": {
+        "balance": {
+            "+": "0x5"
+        },
+        "code": {
+            "+": "0x"
+        },
+        "nonce": {
+            "+": "0x0"
+        },
+        "storage": {}
+    }
+}`
+	result, err := json.MarshalIndent(diff, "", "    ")
+	if err != nil {
+		t.Fatalf("problem with formating result, got error: %v", err)
+	}
+	if want != string(result) {
+		t.Errorf("expected result is not the same as output got: %v, want: %v", string(result), want)
+	}
+}
+
+func TestVmTraceNestedCall(t *testing.T) {
+
+	tracer := NewVmTraceLogger()
+
+	tracer.CaptureStart(nil, from, to, false, inputData, 1000, value)
+	tracer.CaptureState(nil, 0, vm.PUSH1, 1000, 3, nil, nil, 1, nil)
+	tracer.CaptureState(nil, 2, vm.CALL, 997, 100, nil, nil, 1, nil)
+	tracer.CaptureEnter(vm.CALL, to, toInner, inputDataInner, 500, value)
+	tracer.CaptureState(nil, 0, vm.STOP, 500, 0, nil, nil, 2, nil)
+	tracer.CaptureExit(nil, 0, nil)
+	tracer.CaptureState(nil, 3, vm.STOP, 897, 0, nil, nil, 1, nil)
+	tracer.CaptureEnd(nil, 103, time.Since(time.Now()), nil)
+
+	result := tracer.GetResult()
+	if result == nil || len(result.Ops) != 3 {
+		t.Fatalf("unexpected number of captured instructions")
+	}
+	if used := result.Ops[0].Ex.Used; used != 997 {
+		t.Errorf("unexpected gas left after first instruction, got: %d, want: %d", used, 997)
+	}
+	if used := result.Ops[1].Ex.Used; used != 897 {
+		t.Errorf("unexpected gas left after call instruction, got: %d, want: %d", used, 897)
+	}
+	sub := result.Ops[1].Sub
+	if sub == nil || len(sub.Ops) != 1 {
+		t.Fatalf("inner call trace is not captured")
+	}
+	if result.Ops[2].Sub != nil {
+		t.Errorf("unexpected inner trace of the last instruction")
+	}
+}
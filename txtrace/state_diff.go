@@ -0,0 +1,216 @@
+package txtrace
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/Fantom-foundation/go-opera/inter/state"
+)
+
+// StateDiff represents state changes of all accounts modified by a transaction
+// in the parity (OpenEthereum) stateDiff format
+type StateDiff map[common.Address]*AccountDiff
+
+// AccountDiff holds changes of a single account
+type AccountDiff struct {
+	Balance *Diff                 `json:"balance"`
+	Code    *Diff                 `json:"code"`
+	Nonce   *Diff                 `json:"nonce"`
+	Storage map[common.Hash]*Diff `json:"storage"`
+}
+
+// Diff represents change of a single value
+//   - "=" value is unchanged
+//   - "+" value was created
+//   - "-" value was removed
+//   - "*" value was changed from one value to another
+type Diff struct {
+	Kind string
+	From interface{}
+	To   interface{}
+}
+
+// MarshalJSON encodes the difference in the parity format
+func (d *Diff) MarshalJSON() ([]byte, error) {
+	switch d.Kind {
+	case "+":
+		return json.Marshal(map[string]interface{}{"+": d.To})
+	case "-":
+		return json.Marshal(map[string]interface{}{"-": d.From})
+	case "*":
+		return json.Marshal(map[string]interface{}{"*": map[string]interface{}{"from": d.From, "to": d.To}})
+	default:
+		return json.Marshal("=")
+	}
+}
+
+// accountSnapshot holds account values before they were modified
+type accountSnapshot struct {
+	exists  bool
+	balance *big.Int
+	nonce   uint64
+	code    []byte
+}
+
+// StateDiffRecorder is a StateDB wrapper which remembers original values
+// of all accounts and storage slots modified during the transaction execution
+type StateDiffRecorder struct {
+	state.StateDB
+
+	accounts map[common.Address]*accountSnapshot
+	storage  map[common.Address]map[common.Hash]common.Hash
+}
+
+// NewStateDiffRecorder wraps the state for recording of changes
+func NewStateDiffRecorder(statedb state.StateDB) *StateDiffRecorder {
+	return &StateDiffRecorder{
+		StateDB:  statedb,
+		accounts: make(map[common.Address]*accountSnapshot),
+		storage:  make(map[common.Address]map[common.Hash]common.Hash),
+	}
+}
+
+// touchAccount stores original account values if the account is not recorded yet
+func (r *StateDiffRecorder) touchAccount(addr common.Address) {
+	if _, ok := r.accounts[addr]; ok {
+		return
+	}
+	r.accounts[addr] = &accountSnapshot{
+		exists:  r.StateDB.Exist(addr),
+		balance: new(big.Int).Set(r.StateDB.GetBalance(addr)),
+		nonce:   r.StateDB.GetNonce(addr),
+		code:    common.CopyBytes(r.StateDB.GetCode(addr)),
+	}
+}
+
+// touchSlot stores original storage value if the slot is not recorded yet
+func (r *StateDiffRecorder) touchSlot(addr common.Address, key common.Hash) {
+	r.touchAccount(addr)
+	slots, ok := r.storage[addr]
+	if !ok {
+		slots = make(map[common.Hash]common.Hash)
+		r.storage[addr] = slots
+	}
+	if _, ok := slots[key]; !ok {
+		slots[key] = r.StateDB.GetState(addr, key)
+	}
+}
+
+func (r *StateDiffRecorder) CreateAccount(addr common.Address) {
+	r.touchAccount(addr)
+	r.StateDB.CreateAccount(addr)
+}
+
+func (r *StateDiffRecorder) SubBalance(addr common.Address, amount *big.Int) {
+	r.touchAccount(addr)
+	r.StateDB.SubBalance(addr, amount)
+}
+
+func (r *StateDiffRecorder) AddBalance(addr common.Address, amount *big.Int) {
+	r.touchAccount(addr)
+	r.StateDB.AddBalance(addr, amount)
+}
+
+func (r *StateDiffRecorder) SetBalance(addr common.Address, amount *big.Int) {
+	r.touchAccount(addr)
+	r.StateDB.SetBalance(addr, amount)
+}
+
+func (r *StateDiffRecorder) SetNonce(addr common.Address, nonce uint64) {
+	r.touchAccount(addr)
+	r.StateDB.SetNonce(addr, nonce)
+}
+
+func (r *StateDiffRecorder) SetCode(addr common.Address, code []byte) {
+	r.touchAccount(addr)
+	r.StateDB.SetCode(addr, code)
+}
+
+func (r *StateDiffRecorder) SetState(addr common.Address, key, value common.Hash) {
+	r.touchSlot(addr, key)
+	r.StateDB.SetState(addr, key, value)
+}
+
+func (r *StateDiffRecorder) SetStorage(addr common.Address, storage map[common.Hash]common.Hash) {
+	for key := range storage {
+		r.touchSlot(addr, key)
+	}
+	r.StateDB.SetStorage(addr, storage)
+}
+
+func (r *StateDiffRecorder) Suicide(addr common.Address) bool {
+	r.touchAccount(addr)
+	// storage of the destructed account is not known here,
+	// so only slots modified by the transaction are reported
+	return r.StateDB.Suicide(addr)
+}
+
+// GetDiff compares recorded original values with the current state.
+// It has to be called after the transaction is finalised.
+func (r *StateDiffRecorder) GetDiff() StateDiff {
+	diff := make(StateDiff)
+	for addr, pre := range r.accounts {
+		exists := r.StateDB.Exist(addr)
+		if !pre.exists && !exists {
+			continue
+		}
+
+		balance := r.StateDB.GetBalance(addr)
+		nonce := r.StateDB.GetNonce(addr)
+		code := r.StateDB.GetCode(addr)
+
+		account := &AccountDiff{
+			Storage: make(map[common.Hash]*Diff),
+		}
+		switch {
+		case !pre.exists:
+			account.Balance = &Diff{Kind: "+", To: (*hexutil.Big)(new(big.Int).Set(balance))}
+			account.Nonce = &Diff{Kind: "+", To: hexutil.Uint64(nonce)}
+			account.Code = &Diff{Kind: "+", To: hexutil.Bytes(common.CopyBytes(code))}
+		case !exists:
+			account.Balance = &Diff{Kind: "-", From: (*hexutil.Big)(pre.balance)}
+			account.Nonce = &Diff{Kind: "-", From: hexutil.Uint64(pre.nonce)}
+			account.Code = &Diff{Kind: "-", From: hexutil.Bytes(pre.code)}
+		default:
+			account.Balance = &Diff{Kind: "="}
+			if pre.balance.Cmp(balance) != 0 {
+				account.Balance = &Diff{Kind: "*", From: (*hexutil.Big)(pre.balance), To: (*hexutil.Big)(new(big.Int).Set(balance))}
+			}
+			account.Nonce = &Diff{Kind: "="}
+			if pre.nonce != nonce {
+				account.Nonce = &Diff{Kind: "*", From: hexutil.Uint64(pre.nonce), To: hexutil.Uint64(nonce)}
+			}
+			account.Code = &Diff{Kind: "="}
+			if !bytes.Equal(pre.code, code) {
+				account.Code = &Diff{Kind: "*", From: hexutil.Bytes(pre.code), To: hexutil.Bytes(common.CopyBytes(code))}
+			}
+		}
+
+		for key, preValue := range r.storage[addr] {
+			value := common.Hash{}
+			if exists {
+				value = r.StateDB.GetState(addr, key)
+			}
+			switch {
+			case preValue == value:
+				continue
+			case !pre.exists:
+				account.Storage[key] = &Diff{Kind: "+", To: value}
+			case !exists:
+				account.Storage[key] = &Diff{Kind: "-", From: preValue}
+			default:
+				account.Storage[key] = &Diff{Kind: "*", From: preValue, To: value}
+			}
+		}
+
+		if account.Balance.Kind == "=" && account.Nonce.Kind == "=" && account.Code.Kind == "=" && len(account.Storage) == 0 {
+			continue
+		}
+		diff[addr] = account
+	}
+	return diff
+}
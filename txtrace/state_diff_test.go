@@ -0,0 +1,187 @@
+package txtrace
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/Fantom-foundation/go-opera/inter/state"
+)
+
+type testAccount struct {
+	balance *big.Int
+	nonce   uint64
+	code    []byte
+	storage map[common.Hash]common.Hash
+}
+
+// testState is an in-memory state, only the methods used by StateDiffRecorder are implemented
+type testState struct {
+	state.StateDB
+	accounts map[common.Address]*testAccount
+}
+
+func newTestState() *testState {
+	return &testState{accounts: make(map[common.Address]*testAccount)}
+}
+
+func (s *testState) account(addr common.Address) *testAccount {
+	acc, ok := s.accounts[addr]
+	if !ok {
+		acc = &testAccount{balance: new(big.Int), storage: make(map[common.Hash]common.Hash)}
+		s.accounts[addr] = acc
+	}
+	return acc
+}
+
+func (s *testState) Exist(addr common.Address) bool {
+	_, ok := s.accounts[addr]
+	return ok
+}
+
+func (s *testState) GetBalance(addr common.Address) *big.Int {
+	if acc, ok := s.accounts[addr]; ok {
+		return acc.balance
+	}
+	return new(big.Int)
+}
+
+func (s *testState) GetNonce(addr common.Address) uint64 {
+	if acc, ok := s.accounts[addr]; ok {
+		return acc.nonce
+	}
+	return 0
+}
+
+func (s *testState) GetCode(addr common.Address) []byte {
+	if acc, ok := s.accounts[addr]; ok {
+		return acc.code
+	}
+	return nil
+}
+
+func (s *testState) GetState(addr common.Address, key common.Hash) common.Hash {
+	if acc, ok := s.accounts[addr]; ok {
+		return acc.storage[key]
+	}
+	return common.Hash{}
+}
+
+func (s *testState) CreateAccount(addr common.Address) {
+	s.account(addr)
+}
+
+func (s *testState) AddBalance(addr common.Address, amount *big.Int) {
+	acc := s.account(addr)
+	acc.balance = new(big.Int).Add(acc.balance, amount)
+}
+
+func (s *testState) SubBalance(addr common.Address, amount *big.Int) {
+	acc := s.account(addr)
+	acc.balance = new(big.Int).Sub(acc.balance, amount)
+}
+
+func (s *testState) SetNonce(addr common.Address, nonce uint64) {
+	s.account(addr).nonce = nonce
+}
+
+func (s *testState) SetState(addr common.Address, key, value common.Hash) {
+	s.account(addr).storage[key] = value
+}
+
+func checkStateDiff(t *testing.T, diff StateDiff, want string) {
+	t.Helper()
+	var have, expected interface{}
+	enc, err := json.Marshal(diff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(enc, &have); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(want), &expected); err != nil {
+		t.Fatal(err)
+	}
+	haveEnc, _ := json.Marshal(have)
+	wantEnc, _ := json.Marshal(expected)
+	if string(haveEnc) != string(wantEnc) {
+		t.Fatalf("state diff mismatch:\nhave %s\nwant %s", haveEnc, wantEnc)
+	}
+}
+
+// Tests the state diff of a value transfer to a new account.
+func TestStateDiffRecorderTransfer(t *testing.T) {
+	var (
+		sender    = common.HexToAddress("0x1")
+		recipient = common.HexToAddress("0x2")
+		untouched = common.HexToAddress("0x3")
+	)
+	statedb := newTestState()
+	statedb.AddBalance(sender, big.NewInt(1000))
+	statedb.AddBalance(untouched, big.NewInt(1))
+
+	recorder := NewStateDiffRecorder(statedb)
+	recorder.SetNonce(sender, 1)
+	recorder.SubBalance(sender, big.NewInt(100))
+	recorder.AddBalance(recipient, big.NewInt(100))
+	// a touched but unchanged account isn't reported
+	recorder.AddBalance(untouched, big.NewInt(0))
+
+	checkStateDiff(t, recorder.GetDiff(), `{
+		"0x0000000000000000000000000000000000000001": {
+			"balance": {"*": {"from": "0x3e8", "to": "0x384"}},
+			"code": "=",
+			"nonce": {"*": {"from": "0x0", "to": "0x1"}},
+			"storage": {}
+		},
+		"0x0000000000000000000000000000000000000002": {
+			"balance": {"+": "0x64"},
+			"code": {"+": "0x"},
+			"nonce": {"+": "0x0"},
+			"storage": {}
+		}
+	}`)
+}
+
+// Tests the state diff of storage writes, only the changed slots are reported.
+func TestStateDiffRecorderStorage(t *testing.T) {
+	var (
+		contract = common.HexToAddress("0x1")
+		slot1    = common.HexToHash("0x1")
+		slot2    = common.HexToHash("0x2")
+		slot3    = common.HexToHash("0x3")
+	)
+	statedb := newTestState()
+	statedb.CreateAccount(contract)
+	statedb.SetState(contract, slot1, common.HexToHash("0xa"))
+	statedb.SetState(contract, slot3, common.HexToHash("0xc"))
+
+	recorder := NewStateDiffRecorder(statedb)
+	recorder.SetState(contract, slot1, common.HexToHash("0xb"))
+	recorder.SetState(contract, slot2, common.HexToHash("0x1"))
+	// the original value is kept on repeated writes
+	recorder.SetState(contract, slot2, common.HexToHash("0x2"))
+	// a slot restored to its original value isn't reported
+	recorder.SetState(contract, slot3, common.HexToHash("0xd"))
+	recorder.SetState(contract, slot3, common.HexToHash("0xc"))
+
+	checkStateDiff(t, recorder.GetDiff(), `{
+		"0x0000000000000000000000000000000000000001": {
+			"balance": "=",
+			"code": "=",
+			"nonce": "=",
+			"storage": {
+				"0x0000000000000000000000000000000000000000000000000000000000000001": {"*": {
+					"from": "0x000000000000000000000000000000000000000000000000000000000000000a",
+					"to": "0x000000000000000000000000000000000000000000000000000000000000000b"
+				}},
+				"0x0000000000000000000000000000000000000000000000000000000000000002": {"*": {
+					"from": "0x0000000000000000000000000000000000000000000000000000000000000000",
+					"to": "0x0000000000000000000000000000000000000000000000000000000000000002"
+				}}
+			}
+		}
+	}`)
+}
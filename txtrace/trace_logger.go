@@ -60,6 +60,18 @@ type ActionTrace struct {
 	TraceType           string             `json:"type"`
 }
 
+// ReplayTrace represents single interaction with blockchain
+// without the block and transaction position fields, as
+// returned by trace_replay* and trace_call* methods
+type ReplayTrace struct {
+	Action       *AddressAction     `json:"action"`
+	Result       *TraceActionResult `json:"result,omitempty"`
+	Error        string             `json:"error,omitempty"`
+	Subtraces    uint64             `json:"subtraces"`
+	TraceAddress []uint32           `json:"traceAddress"`
+	TraceType    string             `json:"type"`
+}
+
 // AddressAction represents more specific information about
 // account interaction
 type AddressAction struct {
@@ -100,6 +112,21 @@ func NewTraceStructLogger(block *evmcore.EvmBlock, tx *types.Transaction, msg ty
 	return &traceStructLogger
 }
 
+// NewCallTraceStructLogger creates new instance of trace creator for a message
+// which is not a part of any transaction, e.g. for the trace_call method
+func NewCallTraceStructLogger(header *evmcore.EvmHeader, msg types.Message, index uint) *TraceStructLogger {
+	traceStructLogger := TraceStructLogger{
+		from:        msg.From(),
+		to:          msg.To(),
+		value:       *msg.Value(),
+		blockHash:   header.Hash,
+		blockNumber: *header.Number,
+		txIndex:     index,
+		gasLimit:    msg.Gas(),
+	}
+	return &traceStructLogger
+}
+
 // NewActionTrace creates new instance of type ActionTrace
 func NewActionTrace(bHash common.Hash, bNumber big.Int, tHash common.Hash, tPos uint64, tType string) *ActionTrace {
 	return &ActionTrace{
@@ -305,6 +332,10 @@ func (tr *TraceStructLogger) CaptureEnd(output []byte, gasUsed uint64, t time.Du
 			// set gas used of the root call with the gas from transaction receipt
 			// to present all cumulative gas used by this call and its inner calls
 			trace.Result.GasUsed = hexutil.Uint64(tr.gasUsed)
+			// there is no receipt for the traced call, so use gas reported by EVM
+			if tr.gasUsed == 0 {
+				trace.Result.GasUsed = hexutil.Uint64(gasUsed)
+			}
 		}
 
 		tr.rootTrace.processTraces()
@@ -372,6 +403,25 @@ func (tr *TraceStructLogger) GetResult() *[]ActionTrace {
 	return &empty
 }
 
+// NewReplayTraces converts action traces into the replay traces
+func NewReplayTraces(traces *[]ActionTrace) []ReplayTrace {
+	result := make([]ReplayTrace, 0)
+	if traces == nil {
+		return result
+	}
+	for _, trace := range *traces {
+		result = append(result, ReplayTrace{
+			Action:       trace.Action,
+			Result:       trace.Result,
+			Error:        trace.Error,
+			Subtraces:    trace.Subtraces,
+			TraceAddress: trace.TraceAddress,
+			TraceType:    trace.TraceType,
+		})
+	}
+	return result
+}
+
 // AddTrace Append trace to call trace list
 func (callTrace *CallTrace) AddTrace(blockTrace *ActionTrace) {
 	if callTrace.Actions == nil {
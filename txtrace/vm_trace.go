@@ -0,0 +1,254 @@
+package txtrace
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// VmTrace represents virtual machine execution trace in the parity format
+type VmTrace struct {
+	Code hexutil.Bytes `json:"code"`
+	Ops  []*VmTraceOp  `json:"ops"`
+}
+
+// VmTraceOp represents single executed instruction
+type VmTraceOp struct {
+	Cost uint64     `json:"cost"`
+	Ex   *VmTraceEx `json:"ex"`
+	Pc   uint64     `json:"pc"`
+	Sub  *VmTrace   `json:"sub"`
+}
+
+// VmTraceEx holds the result of the executed instruction
+type VmTraceEx struct {
+	Mem   *VmTraceMem   `json:"mem"`
+	Push  []hexutil.Big `json:"push"`
+	Store *VmTraceStore `json:"store"`
+	Used  uint64        `json:"used"`
+}
+
+// VmTraceMem represents memory region written by the instruction
+type VmTraceMem struct {
+	Data hexutil.Bytes `json:"data"`
+	Off  uint64        `json:"off"`
+}
+
+// VmTraceStore represents storage slot written by the instruction
+type VmTraceStore struct {
+	Key hexutil.Big `json:"key"`
+	Val hexutil.Big `json:"val"`
+}
+
+// vmTraceFrame holds tracing state of a single call frame
+type vmTraceFrame struct {
+	trace *VmTrace
+
+	// last captured instruction, its result is known
+	// only when the next instruction is captured
+	pending     *VmTraceOp
+	pendingOp   vm.OpCode
+	memOff      uint64
+	memSize     uint64
+	hasCodeInit bool
+}
+
+// VmTraceLogger is a tracer collecting the virtual machine trace
+type VmTraceLogger struct {
+	root   *VmTrace
+	frames []*vmTraceFrame
+}
+
+// NewVmTraceLogger creates new instance of the virtual machine tracer
+func NewVmTraceLogger() *VmTraceLogger {
+	return &VmTraceLogger{}
+}
+
+// GetResult returns the collected virtual machine trace
+func (tr *VmTraceLogger) GetResult() *VmTrace {
+	return tr.root
+}
+
+func (tr *VmTraceLogger) pushFrame(code []byte) *VmTrace {
+	trace := &VmTrace{
+		Code: common.CopyBytes(code),
+		Ops:  make([]*VmTraceOp, 0),
+	}
+	tr.frames = append(tr.frames, &vmTraceFrame{trace: trace})
+	return trace
+}
+
+func (tr *VmTraceLogger) popFrame() {
+	if len(tr.frames) == 0 {
+		return
+	}
+	frame := tr.frames[len(tr.frames)-1]
+	frame.finishPending(nil, frame.pendingGasLeft())
+	tr.frames = tr.frames[:len(tr.frames)-1]
+}
+
+// CaptureStart implements the tracer interface to initialize the tracing operation.
+func (tr *VmTraceLogger) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	var code []byte
+	if create {
+		code = input
+	} else if env != nil {
+		code = env.StateDB.GetCode(to)
+	}
+	tr.root = tr.pushFrame(code)
+	tr.frames[0].hasCodeInit = true
+}
+
+// CaptureState records executed instruction
+func (tr *VmTraceLogger) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Error("VmTrace CaptureState failed", "err", r)
+		}
+	}()
+	if err != nil || len(tr.frames) == 0 {
+		return
+	}
+	frame := tr.frames[len(tr.frames)-1]
+	if !frame.hasCodeInit && scope != nil && scope.Contract != nil {
+		frame.trace.Code = common.CopyBytes(scope.Contract.Code)
+		frame.hasCodeInit = true
+	}
+	frame.finishPending(scope, gas)
+
+	traceOp := &VmTraceOp{
+		Cost: cost,
+		Pc:   pc,
+		Ex:   &VmTraceEx{Used: gas - cost},
+	}
+	frame.trace.Ops = append(frame.trace.Ops, traceOp)
+	frame.pending = traceOp
+	frame.pendingOp = op
+	frame.memOff, frame.memSize = 0, 0
+
+	if scope == nil || scope.Stack == nil {
+		return
+	}
+	stack := scope.Stack
+	stackArg := func(n int) uint64 {
+		if stack.Len() <= n {
+			return 0
+		}
+		return stack.Back(n).Uint64()
+	}
+	switch op {
+	case vm.MSTORE:
+		frame.memOff, frame.memSize = stackArg(0), 32
+	case vm.MSTORE8:
+		frame.memOff, frame.memSize = stackArg(0), 1
+	case vm.CALLDATACOPY, vm.CODECOPY, vm.RETURNDATACOPY:
+		frame.memOff, frame.memSize = stackArg(0), stackArg(2)
+	case vm.EXTCODECOPY:
+		frame.memOff, frame.memSize = stackArg(1), stackArg(3)
+	case vm.CALL, vm.CALLCODE:
+		frame.memOff, frame.memSize = stackArg(5), stackArg(6)
+	case vm.DELEGATECALL, vm.STATICCALL:
+		frame.memOff, frame.memSize = stackArg(4), stackArg(5)
+	case vm.SSTORE:
+		if stack.Len() >= 2 {
+			traceOp.Ex.Store = &VmTraceStore{
+				Key: hexutil.Big(*stack.Back(0).ToBig()),
+				Val: hexutil.Big(*stack.Back(1).ToBig()),
+			}
+		}
+	}
+}
+
+// finishPending fills the result of the last captured instruction
+func (f *vmTraceFrame) finishPending(scope *vm.ScopeContext, gasLeft uint64) {
+	if f.pending == nil {
+		return
+	}
+	ex := f.pending.Ex
+	ex.Used = gasLeft
+	ex.Push = make([]hexutil.Big, 0)
+	if scope != nil && scope.Stack != nil {
+		n := pushedItems(f.pendingOp)
+		if n > scope.Stack.Len() {
+			n = scope.Stack.Len()
+		}
+		for i := n - 1; i >= 0; i-- {
+			ex.Push = append(ex.Push, hexutil.Big(*scope.Stack.Back(i).ToBig()))
+		}
+	}
+	if f.memSize > 0 && scope != nil && scope.Memory != nil && f.memOff+f.memSize <= uint64(scope.Memory.Len()) {
+		ex.Mem = &VmTraceMem{
+			Off:  f.memOff,
+			Data: scope.Memory.GetCopy(int64(f.memOff), int64(f.memSize)),
+		}
+	}
+	f.pending = nil
+}
+
+// pendingGasLeft estimates gas left after the last instruction of the frame
+func (f *vmTraceFrame) pendingGasLeft() uint64 {
+	if f.pending == nil {
+		return 0
+	}
+	return f.pending.Ex.Used
+}
+
+// pushedItems returns number of stack items the instruction puts on the stack
+func pushedItems(op vm.OpCode) int {
+	switch {
+	case op.IsPush():
+		return 1
+	case op >= vm.DUP1 && op <= vm.DUP16:
+		return int(op-vm.DUP1) + 2
+	case op >= vm.SWAP1 && op <= vm.SWAP16:
+		return int(op-vm.SWAP1) + 2
+	case op >= vm.LOG0 && op <= vm.LOG4:
+		return 0
+	}
+	switch op {
+	case vm.STOP, vm.POP, vm.MSTORE, vm.MSTORE8, vm.SSTORE, vm.JUMP, vm.JUMPI, vm.JUMPDEST,
+		vm.CALLDATACOPY, vm.CODECOPY, vm.EXTCODECOPY, vm.RETURNDATACOPY,
+		vm.RETURN, vm.REVERT, vm.SELFDESTRUCT, vm.INVALID:
+		return 0
+	}
+	return 1
+}
+
+// CaptureEnter creates a nested trace for the inner call
+func (tr *VmTraceLogger) CaptureEnter(op vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	if len(tr.frames) == 0 {
+		return
+	}
+	parent := tr.frames[len(tr.frames)-1]
+	var code []byte
+	if op == vm.CREATE || op == vm.CREATE2 {
+		code = input
+	}
+	sub := tr.pushFrame(code)
+	if op == vm.CREATE || op == vm.CREATE2 || op == vm.SELFDESTRUCT {
+		tr.frames[len(tr.frames)-1].hasCodeInit = true
+	}
+	if parent.pending != nil && op != vm.SELFDESTRUCT {
+		parent.pending.Sub = sub
+	}
+}
+
+// CaptureExit closes the nested trace of the inner call
+func (tr *VmTraceLogger) CaptureExit(output []byte, gasUsed uint64, err error) {
+	tr.popFrame()
+}
+
+// CaptureFault is not used as the failed instruction is captured by CaptureState
+func (tr *VmTraceLogger) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+}
+
+// CaptureEnd is called after the call finishes to finalize the tracing.
+func (tr *VmTraceLogger) CaptureEnd(output []byte, gasUsed uint64, t time.Duration, err error) {
+	for len(tr.frames) > 0 {
+		tr.popFrame()
+	}
+}
@@ -0,0 +1,121 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// thrift compact protocol types
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// thriftWriter encodes structs of the parquet metadata by the thrift compact protocol
+type thriftWriter struct {
+	buf       bytes.Buffer
+	lastField []int16
+}
+
+func newThriftWriter() *thriftWriter {
+	return &thriftWriter{
+		lastField: []int16{0},
+	}
+}
+
+func (w *thriftWriter) varint(v uint64) {
+	var b [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(b[:], v)
+	w.buf.Write(b[:n])
+}
+
+func (w *thriftWriter) zigzag32(v int32) {
+	w.varint(uint64(uint32((v << 1) ^ (v >> 31))))
+}
+
+func (w *thriftWriter) zigzag64(v int64) {
+	w.varint(uint64((v << 1) ^ (v >> 63)))
+}
+
+func (w *thriftWriter) fieldHeader(id int16, typ byte) {
+	last := &w.lastField[len(w.lastField)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		w.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		w.buf.WriteByte(typ)
+		w.zigzag32(int32(id))
+	}
+	*last = id
+}
+
+func (w *thriftWriter) i32(id int16, v int32) {
+	w.fieldHeader(id, thriftI32)
+	w.zigzag32(v)
+}
+
+func (w *thriftWriter) i64(id int16, v int64) {
+	w.fieldHeader(id, thriftI64)
+	w.zigzag64(v)
+}
+
+func (w *thriftWriter) binary(id int16, v []byte) {
+	w.fieldHeader(id, thriftBinary)
+	w.varint(uint64(len(v)))
+	w.buf.Write(v)
+}
+
+func (w *thriftWriter) listHeader(id int16, elemType byte, size int) {
+	w.fieldHeader(id, thriftList)
+	if size < 15 {
+		w.buf.WriteByte(byte(size)<<4 | elemType)
+	} else {
+		w.buf.WriteByte(0xf0 | elemType)
+		w.varint(uint64(size))
+	}
+}
+
+func (w *thriftWriter) i32List(id int16, vv []int32) {
+	w.listHeader(id, thriftI32, len(vv))
+	for _, v := range vv {
+		w.zigzag32(v)
+	}
+}
+
+func (w *thriftWriter) binaryList(id int16, vv []string) {
+	w.listHeader(id, thriftBinary, len(vv))
+	for _, v := range vv {
+		w.varint(uint64(len(v)))
+		w.buf.WriteString(v)
+	}
+}
+
+// structList writes the list of structs, each one is written by the callback
+func (w *thriftWriter) structList(id int16, size int, write func(i int)) {
+	w.listHeader(id, thriftStruct, size)
+	for i := 0; i < size; i++ {
+		w.lastField = append(w.lastField, 0)
+		write(i)
+		w.end()
+	}
+}
+
+// structBegin starts the nested struct field, it's finished by end
+func (w *thriftWriter) structBegin(id int16) {
+	w.fieldHeader(id, thriftStruct)
+	w.lastField = append(w.lastField, 0)
+}
+
+// end writes the stop field of the current struct
+func (w *thriftWriter) end() {
+	w.buf.WriteByte(0)
+	w.lastField = w.lastField[:len(w.lastField)-1]
+}
+
+// bytes finishes the top-level struct and returns the encoded data
+func (w *thriftWriter) bytes() []byte {
+	w.end()
+	return w.buf.Bytes()
+}
@@ -0,0 +1,275 @@
+// Package parquet implements a minimal writer of Parquet files.
+// Only flat schemas of required INT64 and UTF8 string columns are supported.
+// Values are PLAIN encoded and not compressed, so the files are readable by any
+// Parquet implementation.
+package parquet
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+var magic = []byte("PAR1")
+
+// ColumnType is the type of the column values
+type ColumnType int
+
+const (
+	// Int64 column values are int64
+	Int64 ColumnType = iota
+	// String column values are string
+	String
+)
+
+// Column describes a column of the file
+type Column struct {
+	Name string
+	Type ColumnType
+}
+
+// parquet format constants
+const (
+	typeInt64     = 2
+	typeByteArray = 6
+
+	repetitionRequired = 0
+	convertedUTF8      = 0
+	encodingPlain      = 0
+	encodingRLE        = 3
+	codecUncompressed  = 0
+	pageTypeData       = 0
+)
+
+// DefaultRowGroupSize is the number of rows buffered in memory before a row group is written
+const DefaultRowGroupSize = 64 * 1024
+
+// maxPageSize is the maximum size of the values of a column in a row group,
+// as the size of a data page is encoded as int32
+var maxPageSize = math.MaxInt32
+
+var errClosed = errors.New("parquet writer is closed")
+
+type columnChunk struct {
+	offset int64
+	size   int64
+	values int64
+}
+
+type rowGroup struct {
+	columns []columnChunk
+	size    int64
+	rows    int64
+}
+
+// Writer writes rows into a Parquet file
+type Writer struct {
+	w            io.Writer
+	offset       int64
+	columns      []Column
+	rowGroupSize int
+
+	buffers [][]byte
+	rows    int
+
+	rowGroups []rowGroup
+	totalRows int64
+	closed    bool
+}
+
+// NewWriter writes the file header and returns the writer of rows.
+// The underlying writer is not closed by the Writer.
+func NewWriter(w io.Writer, columns []Column, rowGroupSize int) (*Writer, error) {
+	if len(columns) == 0 {
+		return nil, errors.New("no columns")
+	}
+	if rowGroupSize <= 0 {
+		rowGroupSize = DefaultRowGroupSize
+	}
+	if rowGroupSize > math.MaxInt32 {
+		return nil, fmt.Errorf("row group size %d exceeds the maximum of %d rows", rowGroupSize, math.MaxInt32)
+	}
+	pw := &Writer{
+		w:            w,
+		columns:      columns,
+		rowGroupSize: rowGroupSize,
+		buffers:      make([][]byte, len(columns)),
+	}
+	if err := pw.write(magic); err != nil {
+		return nil, err
+	}
+	return pw, nil
+}
+
+func (w *Writer) write(b []byte) error {
+	n, err := w.w.Write(b)
+	w.offset += int64(n)
+	return err
+}
+
+// Write appends the row, values have to match the types of the columns.
+// The buffered rows are flushed before the row if a page of any column would exceed the maximum page size.
+func (w *Writer) Write(row []interface{}) error {
+	if w.closed {
+		return errClosed
+	}
+	if len(row) != len(w.columns) {
+		return fmt.Errorf("row has %d values, expected %d", len(row), len(w.columns))
+	}
+	split := false
+	for i, c := range w.columns {
+		var size int
+		switch c.Type {
+		case Int64:
+			if _, ok := row[i].(int64); !ok {
+				return fmt.Errorf("column %s: expected int64, got %T", c.Name, row[i])
+			}
+			size = 8
+		case String:
+			v, ok := row[i].(string)
+			if !ok {
+				return fmt.Errorf("column %s: expected string, got %T", c.Name, row[i])
+			}
+			size = 4 + len(v)
+		}
+		if size > maxPageSize {
+			return fmt.Errorf("column %s: value of %d bytes exceeds the maximum page size", c.Name, size)
+		}
+		if len(w.buffers[i])+size > maxPageSize {
+			split = true
+		}
+	}
+	if split {
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+	for i, c := range w.columns {
+		switch c.Type {
+		case Int64:
+			w.buffers[i] = binary.LittleEndian.AppendUint64(w.buffers[i], uint64(row[i].(int64)))
+		case String:
+			v := row[i].(string)
+			w.buffers[i] = binary.LittleEndian.AppendUint32(w.buffers[i], uint32(len(v)))
+			w.buffers[i] = append(w.buffers[i], v...)
+		}
+	}
+	w.rows++
+	if w.rows >= w.rowGroupSize {
+		return w.Flush()
+	}
+	return nil
+}
+
+// Flush writes the buffered rows as a row group
+func (w *Writer) Flush() error {
+	if w.closed {
+		return errClosed
+	}
+	if w.rows == 0 {
+		return nil
+	}
+	group := rowGroup{
+		columns: make([]columnChunk, len(w.columns)),
+		rows:    int64(w.rows),
+	}
+	for i := range w.columns {
+		data := w.buffers[i]
+		header := newThriftWriter()
+		header.i32(1, pageTypeData)
+		header.i32(2, int32(len(data)))
+		header.i32(3, int32(len(data)))
+		header.structBegin(5)
+		header.i32(1, int32(w.rows))
+		header.i32(2, encodingPlain)
+		header.i32(3, encodingRLE)
+		header.i32(4, encodingRLE)
+		header.end()
+
+		chunk := columnChunk{
+			offset: w.offset,
+			values: int64(w.rows),
+		}
+		if err := w.write(header.bytes()); err != nil {
+			return err
+		}
+		if err := w.write(data); err != nil {
+			return err
+		}
+		chunk.size = w.offset - chunk.offset
+		group.columns[i] = chunk
+		group.size += chunk.size
+		w.buffers[i] = data[:0]
+	}
+	w.rowGroups = append(w.rowGroups, group)
+	w.totalRows += group.rows
+	w.rows = 0
+	return nil
+}
+
+func (w *Writer) physicalType(c Column) int32 {
+	if c.Type == Int64 {
+		return typeInt64
+	}
+	return typeByteArray
+}
+
+// Close flushes the buffered rows and writes the file footer
+func (w *Writer) Close() error {
+	if w.closed {
+		return errClosed
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	w.closed = true
+
+	meta := newThriftWriter()
+	meta.i32(1, 1) // version
+	meta.structList(2, len(w.columns)+1, func(i int) {
+		if i == 0 {
+			meta.binary(4, []byte("schema"))
+			meta.i32(5, int32(len(w.columns)))
+			return
+		}
+		c := w.columns[i-1]
+		meta.i32(1, w.physicalType(c))
+		meta.i32(3, repetitionRequired)
+		meta.binary(4, []byte(c.Name))
+		if c.Type == String {
+			meta.i32(6, convertedUTF8)
+		}
+	})
+	meta.i64(3, w.totalRows)
+	meta.structList(4, len(w.rowGroups), func(i int) {
+		group := w.rowGroups[i]
+		meta.structList(1, len(group.columns), func(j int) {
+			chunk := group.columns[j]
+			meta.i64(2, chunk.offset)
+			meta.structBegin(3)
+			meta.i32(1, w.physicalType(w.columns[j]))
+			meta.i32List(2, []int32{encodingPlain, encodingRLE})
+			meta.binaryList(3, []string{w.columns[j].Name})
+			meta.i32(4, codecUncompressed)
+			meta.i64(5, chunk.values)
+			meta.i64(6, chunk.size)
+			meta.i64(7, chunk.size)
+			meta.i64(9, chunk.offset)
+			meta.end()
+		})
+		meta.i64(2, group.size)
+		meta.i64(3, group.rows)
+	})
+	meta.binary(6, []byte("go-opera"))
+	footer := meta.bytes()
+
+	if err := w.write(footer); err != nil {
+		return err
+	}
+	if err := w.write(binary.LittleEndian.AppendUint32(nil, uint32(len(footer)))); err != nil {
+		return err
+	}
+	return w.write(magic)
+}
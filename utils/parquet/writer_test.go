@@ -0,0 +1,227 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// thriftReader decodes thrift compact structs into maps of field IDs to values
+type thriftReader struct {
+	t *testing.T
+	r *bytes.Reader
+}
+
+func (r *thriftReader) varint() uint64 {
+	v, err := binary.ReadUvarint(r.r)
+	require.NoError(r.t, err)
+	return v
+}
+
+func (r *thriftReader) zigzag() int64 {
+	v := r.varint()
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func (r *thriftReader) value(typ byte) interface{} {
+	switch typ {
+	case thriftI32, thriftI64:
+		return r.zigzag()
+	case thriftBinary:
+		b := make([]byte, r.varint())
+		_, err := r.r.Read(b)
+		require.NoError(r.t, err)
+		return string(b)
+	case thriftList:
+		h, err := r.r.ReadByte()
+		require.NoError(r.t, err)
+		size := int(h >> 4)
+		if size == 15 {
+			size = int(r.varint())
+		}
+		list := make([]interface{}, size)
+		for i := range list {
+			list[i] = r.value(h & 0x0f)
+		}
+		return list
+	case thriftStruct:
+		return r.readStruct()
+	}
+	r.t.Fatalf("unexpected thrift type %d", typ)
+	return nil
+}
+
+func (r *thriftReader) readStruct() map[int16]interface{} {
+	res := make(map[int16]interface{})
+	var last int16
+	for {
+		h, err := r.r.ReadByte()
+		require.NoError(r.t, err)
+		if h == 0 {
+			return res
+		}
+		id := last + int16(h>>4)
+		if h>>4 == 0 {
+			id = int16(r.zigzag())
+		}
+		res[id] = r.value(h & 0x0f)
+		last = id
+	}
+}
+
+// updateGolden rewrites the golden files by the output of the writer.
+// The rewritten files have to be validated by an independent Parquet reader before they are committed.
+var updateGolden = flag.Bool("update", false, "update the golden files")
+
+func TestWriter(t *testing.T) {
+	require := require.New(t)
+
+	columns := []Column{
+		{Name: "number", Type: Int64},
+		{Name: "hash", Type: String},
+	}
+	buf := &bytes.Buffer{}
+	w, err := NewWriter(buf, columns, 2)
+	require.NoError(err)
+	require.NoError(w.Write([]interface{}{int64(1), "0x01"}))
+	require.NoError(w.Write([]interface{}{int64(-2), ""}))
+	require.NoError(w.Write([]interface{}{int64(3), "0x0303"}))
+	require.Error(w.Write([]interface{}{"4", "0x04"}))
+	require.Error(w.Write([]interface{}{int64(4)}))
+	require.NoError(w.Close())
+	require.Error(w.Write([]interface{}{int64(4), "0x04"}))
+
+	data := buf.Bytes()
+	require.Equal(magic, data[:4])
+	require.Equal(magic, data[len(data)-4:])
+	footerSize := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	footer := data[len(data)-8-footerSize : len(data)-8]
+
+	meta := (&thriftReader{t, bytes.NewReader(footer)}).readStruct()
+	require.Equal(int64(1), meta[1])
+	require.Equal(int64(3), meta[3])
+	require.Equal("go-opera", meta[6])
+
+	schema := meta[2].([]interface{})
+	require.Len(schema, 3)
+	require.Equal("schema", schema[0].(map[int16]interface{})[4])
+	require.Equal(int64(2), schema[0].(map[int16]interface{})[5])
+	require.Equal("number", schema[1].(map[int16]interface{})[4])
+	require.Equal(int64(typeInt64), schema[1].(map[int16]interface{})[1])
+	require.Equal("hash", schema[2].(map[int16]interface{})[4])
+	require.Equal(int64(typeByteArray), schema[2].(map[int16]interface{})[1])
+	require.Equal(int64(convertedUTF8), schema[2].(map[int16]interface{})[6])
+
+	// read all the values back by the column chunks metadata
+	numbers := []int64{}
+	hashes := []string{}
+	groups := meta[4].([]interface{})
+	require.Len(groups, 2)
+	for _, g := range groups {
+		group := g.(map[int16]interface{})
+		chunks := group[1].([]interface{})
+		require.Len(chunks, 2)
+		for i, c := range chunks {
+			cm := c.(map[int16]interface{})[3].(map[int16]interface{})
+			require.Equal([]interface{}{columns[i].Name}, cm[3])
+			offset, size := cm[9].(int64), cm[7].(int64)
+			page := bytes.NewReader(data[offset : offset+size])
+			header := (&thriftReader{t, page}).readStruct()
+			require.Equal(int64(pageTypeData), header[1])
+			values := header[5].(map[int16]interface{})[1].(int64)
+			require.Equal(group[3], values)
+			require.Equal(header[2], int64(page.Len()))
+			for v := int64(0); v < values; v++ {
+				if columns[i].Type == Int64 {
+					var n int64
+					require.NoError(binary.Read(page, binary.LittleEndian, &n))
+					numbers = append(numbers, n)
+				} else {
+					var l uint32
+					require.NoError(binary.Read(page, binary.LittleEndian, &l))
+					s := make([]byte, l)
+					_, _ = page.Read(s)
+					hashes = append(hashes, string(s))
+				}
+			}
+			require.Zero(page.Len())
+		}
+	}
+	require.Equal([]int64{1, -2, 3}, numbers)
+	require.Equal([]string{"0x01", "", "0x0303"}, hashes)
+}
+
+func TestWriterMaxPageSize(t *testing.T) {
+	require := require.New(t)
+
+	defer func(size int) {
+		maxPageSize = size
+	}(maxPageSize)
+	maxPageSize = 20
+
+	columns := []Column{
+		{Name: "number", Type: Int64},
+		{Name: "hash", Type: String},
+	}
+	buf := &bytes.Buffer{}
+	w, err := NewWriter(buf, columns, 100)
+	require.NoError(err)
+	require.NoError(w.Write([]interface{}{int64(1), "0x0101"}))
+	require.NoError(w.Write([]interface{}{int64(2), "0x0202"}))
+	// the buffered rows are flushed not to exceed the page size
+	require.NoError(w.Write([]interface{}{int64(3), "0x0303"}))
+	// a value which doesn't fit into a page is rejected without breaking the row group
+	require.Error(w.Write([]interface{}{int64(4), "0x04040404040404040404"}))
+	require.NoError(w.Close())
+
+	data := buf.Bytes()
+	footerSize := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	footer := data[len(data)-8-footerSize : len(data)-8]
+	meta := (&thriftReader{t, bytes.NewReader(footer)}).readStruct()
+	require.Equal(int64(3), meta[3])
+
+	groups := meta[4].([]interface{})
+	require.Len(groups, 2)
+	require.Equal(int64(2), groups[0].(map[int16]interface{})[3])
+	require.Equal(int64(1), groups[1].(map[int16]interface{})[3])
+	for _, g := range groups {
+		for _, c := range g.(map[int16]interface{})[1].([]interface{}) {
+			cm := c.(map[int16]interface{})[3].(map[int16]interface{})
+			offset, size := cm[9].(int64), cm[7].(int64)
+			header := (&thriftReader{t, bytes.NewReader(data[offset : offset+size])}).readStruct()
+			require.LessOrEqual(header[2].(int64), int64(maxPageSize))
+		}
+	}
+}
+
+// TestWriterGolden compares the output with the golden file, which is validated by an independent
+// Parquet reader (github.com/parquet-go/parquet-go). The reader decodes the schema
+// {required int64 number; required binary hash (STRING)} and the rows (1, "0x01"), (-2, ""), (3, "0x0303")
+// in the row groups of 2 and 1 rows.
+func TestWriterGolden(t *testing.T) {
+	require := require.New(t)
+
+	buf := &bytes.Buffer{}
+	w, err := NewWriter(buf, []Column{
+		{Name: "number", Type: Int64},
+		{Name: "hash", Type: String},
+	}, 2)
+	require.NoError(err)
+	require.NoError(w.Write([]interface{}{int64(1), "0x01"}))
+	require.NoError(w.Write([]interface{}{int64(-2), ""}))
+	require.NoError(w.Write([]interface{}{int64(3), "0x0303"}))
+	require.NoError(w.Close())
+
+	golden := filepath.Join("testdata", "rows.parquet")
+	if *updateGolden {
+		require.NoError(os.WriteFile(golden, buf.Bytes(), 0644))
+	}
+	expected, err := os.ReadFile(golden)
+	require.NoError(err)
+	require.Equal(expected, buf.Bytes())
+}
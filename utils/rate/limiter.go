@@ -0,0 +1,50 @@
+package rate
+
+import (
+	"time"
+)
+
+// Limiter is a token bucket which is refilled with a constant rate up to its burst size.
+// Not safe for concurrent use.
+type Limiter struct {
+	rate   float64 // tokens per second
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewLimiter constructs a new Limiter with a full bucket
+func NewLimiter(rate float64, burst int, now time.Time) *Limiter {
+	return &Limiter{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   now,
+	}
+}
+
+func (l *Limiter) refill(now time.Time) {
+	if elapsed := now.Sub(l.last); elapsed > 0 {
+		l.tokens += elapsed.Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	l.last = now
+}
+
+// Allow takes a token from the bucket and returns false if the bucket is empty
+func (l *Limiter) Allow(now time.Time) bool {
+	l.refill(now)
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
+
+// Full returns true if the bucket is full, i.e. the limiter is equal to a new one
+func (l *Limiter) Full(now time.Time) bool {
+	l.refill(now)
+	return l.tokens >= l.burst
+}
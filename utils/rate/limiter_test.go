@@ -0,0 +1,41 @@
+package rate
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLimiter(t *testing.T) {
+	now := time.Now()
+	l := NewLimiter(2, 3, now)
+	for i := 0; i < 3; i++ {
+		if !l.Allow(now) {
+			t.Fatalf("burst token %d is rejected", i)
+		}
+	}
+	if l.Allow(now) {
+		t.Fatal("empty bucket allowed a token")
+	}
+	now = now.Add(500 * time.Millisecond)
+	if !l.Allow(now) {
+		t.Fatal("refilled token is rejected")
+	}
+	if l.Allow(now) {
+		t.Fatal("empty bucket allowed a token")
+	}
+	if l.Full(now) {
+		t.Fatal("empty bucket is full")
+	}
+	now = now.Add(time.Hour)
+	if !l.Full(now) {
+		t.Fatal("bucket isn't refilled")
+	}
+	for i := 0; i < 3; i++ {
+		if !l.Allow(now) {
+			t.Fatalf("burst token %d is rejected", i)
+		}
+	}
+	if l.Allow(now) {
+		t.Fatal("bucket exceeded the burst size")
+	}
+}
@@ -2,7 +2,6 @@ package encryption
 
 import (
 	"bytes"
-	"crypto/ecdsa"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -11,7 +10,6 @@ import (
 
 	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/crypto"
 
 	"github.com/Fantom-foundation/go-opera/inter/validatorpk"
 )
@@ -55,9 +53,8 @@ func (ks Keystore) ReadKey(wantPubkey validatorpk.PubKey, filename, auth string)
 		return nil, err
 	}
 	// Make sure we're really operating on the requested key (no swap attacks)
-	keySecp256k1 := key.Decoded.(*ecdsa.PrivateKey)
-	gotPubkey := crypto.FromECDSAPub(&keySecp256k1.PublicKey)
-	if bytes.Compare(wantPubkey.Raw, gotPubkey) != 0 {
+	gotPubkey := key.RawPubKey()
+	if key.Type != wantPubkey.Type || bytes.Compare(wantPubkey.Raw, gotPubkey) != 0 {
 		return nil, fmt.Errorf("key content mismatch: have public key %X, want %X", gotPubkey, wantPubkey.Raw)
 	}
 	return key, nil
@@ -79,7 +76,7 @@ func (ks Keystore) StoreKey(filename string, pubkey validatorpk.PubKey, key []by
 // EncryptKey encrypts a key using the specified scrypt parameters into a json
 // blob that can be decrypted later on.
 func (ks Keystore) EncryptKey(pubkey validatorpk.PubKey, key []byte, auth string) ([]byte, error) {
-	if pubkey.Type != validatorpk.Types.Secp256k1 {
+	if !isSupportedType(pubkey.Type) {
 		return nil, ErrNotSupportedType
 	}
 	cryptoStruct, err := keystore.EncryptDataV3(key, []byte(auth), ks.scryptN, ks.scryptP)
@@ -109,16 +106,16 @@ func DecryptKey(keyjson []byte, auth string) (*PrivateKey, error) {
 	if err := json.Unmarshal(keyjson, k); err != nil {
 		return nil, err
 	}
-	if k.Type != validatorpk.Types.Secp256k1 {
+	if !isSupportedType(k.Type) {
 		return nil, ErrNotSupportedType
 	}
-	keyBytes, err = decryptKey_secp256k1(k, auth)
+	keyBytes, err = decryptKey(k, auth)
 	// Handle any decryption errors and return the key
 	if err != nil {
 		return nil, err
 	}
 
-	decoded, err := crypto.ToECDSA(keyBytes)
+	decoded, err := DecodeKey(k.Type, keyBytes)
 	if err != nil {
 		return nil, err
 	}
@@ -130,7 +127,7 @@ func DecryptKey(keyjson []byte, auth string) (*PrivateKey, error) {
 	}, nil
 }
 
-func decryptKey_secp256k1(keyProtected *EncryptedKeyJSON, auth string) (keyBytes []byte, err error) {
+func decryptKey(keyProtected *EncryptedKeyJSON, auth string) (keyBytes []byte, err error) {
 	plainText, err := keystore.DecryptDataV3(keyProtected.Crypto, auth)
 	if err != nil {
 		return nil, err
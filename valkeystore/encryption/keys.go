@@ -0,0 +1,47 @@
+package encryption
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/Fantom-foundation/go-opera/inter/validatorpk"
+)
+
+var ErrInvalidKeyLength = errors.New("invalid key length")
+
+func isSupportedType(keyType uint8) bool {
+	return keyType == validatorpk.Types.Secp256k1 || keyType == validatorpk.Types.Ed25519
+}
+
+// DecodeKey decodes the private key of the key type.
+// Secp256k1 keys are decoded into *ecdsa.PrivateKey, ed25519 keys (the 32 bytes seed) into ed25519.PrivateKey.
+func DecodeKey(keyType uint8, key []byte) (interface{}, error) {
+	switch keyType {
+	case validatorpk.Types.Secp256k1:
+		decoded, err := crypto.ToECDSA(key)
+		if err != nil {
+			return nil, err
+		}
+		return decoded, nil
+	case validatorpk.Types.Ed25519:
+		if len(key) != ed25519.SeedSize {
+			return nil, ErrInvalidKeyLength
+		}
+		return ed25519.NewKeyFromSeed(key), nil
+	}
+	return nil, ErrNotSupportedType
+}
+
+// RawPubKey returns the raw public key of the decoded private key
+func (k *PrivateKey) RawPubKey() []byte {
+	switch decoded := k.Decoded.(type) {
+	case *ecdsa.PrivateKey:
+		return crypto.FromECDSAPub(&decoded.PublicKey)
+	case ed25519.PrivateKey:
+		return decoded.Public().(ed25519.PublicKey)
+	}
+	return nil
+}
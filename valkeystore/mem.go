@@ -3,8 +3,6 @@ package valkeystore
 import (
 	"errors"
 
-	"github.com/ethereum/go-ethereum/crypto"
-
 	"github.com/Fantom-foundation/go-opera/inter/validatorpk"
 	"github.com/Fantom-foundation/go-opera/valkeystore/encryption"
 )
@@ -30,10 +28,7 @@ func (m *MemKeystore) Add(pubkey validatorpk.PubKey, key []byte, auth string) er
 	if m.Has(pubkey) {
 		return ErrAlreadyExists
 	}
-	if pubkey.Type != validatorpk.Types.Secp256k1 {
-		return encryption.ErrNotSupportedType
-	}
-	decoded, err := crypto.ToECDSA(key)
+	decoded, err := encryption.DecodeKey(pubkey.Type, key)
 	if err != nil {
 		return err
 	}
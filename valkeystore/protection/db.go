@@ -0,0 +1,278 @@
+// Package protection implements the slashing protection database of a validator.
+//
+// Every event signed by a validator is appended to the database before the event is broadcast,
+// and signing of an event which conflicts with the previously signed ones is refused.
+// The database is a portable append-only file, so it can be moved together with the
+// validator key when the validator is migrated to another host.
+package protection
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+var (
+	ErrConflictingEvent      = errors.New("event conflicts with a signed event")
+	ErrConflictingBlockVotes = errors.New("block votes conflict with signed block votes")
+	ErrConflictingEpochVote  = errors.New("epoch vote conflicts with a signed epoch vote")
+	ErrNotApproved           = errors.New("signing isn't approved by the slashing protection")
+)
+
+// validatorState is the latest signed data of a validator
+type validatorState struct {
+	event     *Record
+	lastBlock idx.Block
+	lastEpoch idx.Epoch
+}
+
+func (s *validatorState) check(r Record) error {
+	if s.event != nil {
+		if s.event.same(r) {
+			// signing of the same event again is harmless
+			return nil
+		}
+		if !r.after(*s.event) {
+			return fmt.Errorf("%w: %d:%d is not after %d:%d", ErrConflictingEvent, r.Epoch, r.Seq, s.event.Epoch, s.event.Seq)
+		}
+	}
+	if r.BlockVotes != nil && r.BlockVotes.Start <= s.lastBlock {
+		return fmt.Errorf("%w: block %d is already voted", ErrConflictingBlockVotes, r.BlockVotes.Start)
+	}
+	if r.EpochVote != nil && r.EpochVote.Epoch <= s.lastEpoch {
+		return fmt.Errorf("%w: epoch %d is already voted", ErrConflictingEpochVote, r.EpochVote.Epoch)
+	}
+	return nil
+}
+
+func (s *validatorState) add(r Record) {
+	if s.event == nil || r.after(*s.event) {
+		s.event = &r
+	}
+	if r.BlockVotes != nil && r.BlockVotes.Last > s.lastBlock {
+		s.lastBlock = r.BlockVotes.Last
+	}
+	if r.EpochVote != nil && r.EpochVote.Epoch > s.lastEpoch {
+		s.lastEpoch = r.EpochVote.Epoch
+	}
+}
+
+// DB is the slashing protection database.
+// Records are stored in a file as JSON lines.
+type DB struct {
+	path     string
+	syncMode bool
+
+	mu       sync.Mutex
+	file     *os.File
+	states   map[idx.ValidatorID]*validatorState
+	approved map[idx.ValidatorID]Record
+}
+
+// Open opens the database file, the file is created if it doesn't exist.
+// If syncMode is true, every record is flushed to the disk before it's considered written.
+func Open(path string, syncMode bool) (*DB, error) {
+	const dirPerm = 0700
+	if err := os.MkdirAll(filepath.Dir(path), dirPerm); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	db := &DB{
+		path:     path,
+		syncMode: syncMode,
+		file:     file,
+		states:   make(map[idx.ValidatorID]*validatorState),
+		approved: make(map[idx.ValidatorID]Record),
+	}
+	size, err := db.load()
+	if err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("failed to read slashing protection DB %s: %w", path, err)
+	}
+	// new records are written after the last complete record
+	if _, err := file.Seek(size, io.SeekStart); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// load reads the records and returns the size of the complete records.
+// A trailing incomplete record is left by a crash during the writing, it's truncated.
+func (db *DB) load() (int64, error) {
+	size, err := readRecords(db.file, func(r Record) {
+		db.state(r.Validator).add(r)
+	})
+	if err != nil {
+		return 0, err
+	}
+	info, err := db.file.Stat()
+	if err != nil {
+		return 0, err
+	}
+	if info.Size() != size {
+		log.Warn("Truncating incomplete slashing protection record", "file", db.path, "size", info.Size()-size)
+		if err := db.file.Truncate(size); err != nil {
+			return 0, err
+		}
+	}
+	return size, nil
+}
+
+// readRecords calls fn for every complete record and returns the size of the complete records
+func readRecords(r io.Reader, fn func(r Record)) (int64, error) {
+	var size int64
+	reader := bufio.NewReader(r)
+	for line := 1; ; line++ {
+		b, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			// incomplete last line is ignored
+			return size, nil
+		}
+		if err != nil {
+			return size, err
+		}
+		size += int64(len(b))
+		if len(bytes.TrimSpace(b)) == 0 {
+			continue
+		}
+		var rec Record
+		if err := json.Unmarshal(b, &rec); err != nil {
+			return size, fmt.Errorf("line %d: %w", line, err)
+		}
+		fn(rec)
+	}
+}
+
+func (db *DB) state(validator idx.ValidatorID) *validatorState {
+	s := db.states[validator]
+	if s == nil {
+		s = &validatorState{}
+		db.states[validator] = s
+	}
+	return s
+}
+
+func (db *DB) write(r Record) error {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	if _, err := db.file.Write(append(b, '\n')); err != nil {
+		return err
+	}
+	if db.syncMode {
+		return db.file.Sync()
+	}
+	return nil
+}
+
+// Approve checks the event isn't conflicting with the signed events and approves its signing.
+// Only the last approved event of every validator may be signed.
+func (db *DB) Approve(r Record) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if err := db.state(r.Validator).check(r); err != nil {
+		return err
+	}
+	db.approved[r.Validator] = r
+	return nil
+}
+
+// IsApproved returns true if the digest is the hash of an approved event
+func (db *DB) IsApproved(digest hash.Hash) bool {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	for _, r := range db.approved {
+		if r.HashToSign() == digest {
+			return true
+		}
+	}
+	return false
+}
+
+// Record writes the signed event.
+// It must be called before the event is broadcast.
+func (db *DB) Record(r Record) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	s := db.state(r.Validator)
+	if err := s.check(r); err != nil {
+		return err
+	}
+	if s.event != nil && s.event.same(r) {
+		// already recorded
+		return nil
+	}
+	if err := db.write(r); err != nil {
+		return err
+	}
+	s.add(r)
+	if approved, ok := db.approved[r.Validator]; ok && approved.same(r) {
+		delete(db.approved, r.Validator)
+	}
+	return nil
+}
+
+// LastEvent returns the ID of the last event signed by the validator
+func (db *DB) LastEvent(validator idx.ValidatorID) *hash.Event {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	s := db.states[validator]
+	if s == nil || s.event == nil {
+		return nil
+	}
+	id := s.event.ID()
+	return &id
+}
+
+// LastBlockVote returns the last block voted by the validator
+func (db *DB) LastBlockVote(validator idx.ValidatorID) *idx.Block {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	s := db.states[validator]
+	if s == nil || s.lastBlock == 0 {
+		return nil
+	}
+	last := s.lastBlock
+	return &last
+}
+
+// LastEpochVote returns the last epoch voted by the validator
+func (db *DB) LastEpochVote(validator idx.ValidatorID) *idx.Epoch {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	s := db.states[validator]
+	if s == nil || s.lastEpoch == 0 {
+		return nil
+	}
+	last := s.lastEpoch
+	return &last
+}
+
+// Close closes the database file
+func (db *DB) Close() error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	return db.file.Close()
+}
@@ -0,0 +1,150 @@
+package protection
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/inter"
+)
+
+func fakeEvent(validator idx.ValidatorID, epoch idx.Epoch, seq idx.Event, extra string, bvs *inter.LlrBlockVotes, ev *inter.LlrEpochVote) Record {
+	e := &inter.MutableEventPayload{}
+	e.SetVersion(1)
+	e.SetCreator(validator)
+	e.SetEpoch(epoch)
+	e.SetSeq(seq)
+	e.SetLamport(idx.Lamport(seq))
+	e.SetExtra([]byte(extra))
+	if bvs != nil {
+		e.SetBlockVotes(*bvs)
+	}
+	if ev != nil {
+		e.SetEpochVote(*ev)
+	}
+	e.SetPayloadHash(inter.CalcPayloadHash(e))
+	return NewRecord(e)
+}
+
+func blockVotes(start idx.Block, num int) *inter.LlrBlockVotes {
+	votes := make([]hash.Hash, num)
+	for i := range votes {
+		votes[i] = hash.Of(start.Bytes(), idx.Block(i).Bytes())
+	}
+	return &inter.LlrBlockVotes{Start: start, Epoch: 1, Votes: votes}
+}
+
+func TestDB(t *testing.T) {
+	require := require.New(t)
+	path := filepath.Join(t.TempDir(), "emitter", "slashing-protection")
+
+	db, err := Open(path, true)
+	require.NoError(err)
+	require.Nil(db.LastEvent(1))
+	require.Nil(db.LastBlockVote(1))
+	require.Nil(db.LastEpochVote(1))
+
+	e1 := fakeEvent(1, 2, 1, "", blockVotes(10, 3), &inter.LlrEpochVote{Epoch: 1, Vote: hash.Of([]byte{1})})
+	require.False(db.IsApproved(e1.HashToSign()))
+	require.NoError(db.Approve(e1))
+	require.True(db.IsApproved(e1.HashToSign()))
+	require.NoError(db.Record(e1))
+	require.False(db.IsApproved(e1.HashToSign()))
+
+	// same event may be signed again
+	require.NoError(db.Approve(e1))
+	require.NoError(db.Record(e1))
+
+	// conflicting events
+	require.ErrorIs(db.Approve(fakeEvent(1, 2, 1, "fork", nil, nil)), ErrConflictingEvent)
+	require.ErrorIs(db.Approve(fakeEvent(1, 1, 5, "", nil, nil)), ErrConflictingEvent)
+	require.ErrorIs(db.Approve(fakeEvent(1, 2, 2, "", blockVotes(12, 2), nil)), ErrConflictingBlockVotes)
+	require.ErrorIs(db.Approve(fakeEvent(1, 2, 2, "", nil, &inter.LlrEpochVote{Epoch: 1, Vote: hash.Of([]byte{2})})), ErrConflictingEpochVote)
+	require.ErrorIs(db.Record(fakeEvent(1, 2, 1, "fork", nil, nil)), ErrConflictingEvent)
+
+	// other validators aren't affected
+	e2 := fakeEvent(2, 2, 1, "fork", nil, nil)
+	require.NoError(db.Record(e2))
+
+	e3 := fakeEvent(1, 2, 2, "", blockVotes(13, 1), nil)
+	require.NoError(db.Record(e3))
+	require.NoError(db.Close())
+
+	// state is restored after reopening
+	db, err = Open(path, false)
+	require.NoError(err)
+	require.Equal(e3.ID(), *db.LastEvent(1))
+	require.Equal(e2.ID(), *db.LastEvent(2))
+	require.Equal(idx.Block(13), *db.LastBlockVote(1))
+	require.Equal(idx.Epoch(1), *db.LastEpochVote(1))
+	require.Nil(db.LastEpochVote(2))
+	require.ErrorIs(db.Approve(fakeEvent(1, 2, 2, "fork", nil, nil)), ErrConflictingEvent)
+	require.NoError(db.Close())
+
+	// incomplete record is truncated
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	require.NoError(err)
+	_, err = f.WriteString(`{"validator":1,"epoch":`)
+	require.NoError(err)
+	require.NoError(f.Close())
+
+	db, err = Open(path, false)
+	require.NoError(err)
+	require.Equal(e3.ID(), *db.LastEvent(1))
+	e4 := fakeEvent(1, 3, 1, "", nil, nil)
+	require.NoError(db.Record(e4))
+	require.NoError(db.Close())
+
+	db, err = Open(path, false)
+	require.NoError(err)
+	require.Equal(e4.ID(), *db.LastEvent(1))
+	require.NoError(db.Close())
+}
+
+func TestDBExportImport(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+
+	src, err := Open(filepath.Join(dir, "src"), false)
+	require.NoError(err)
+	defer src.Close()
+	records := []Record{
+		fakeEvent(1, 1, 1, "", nil, nil),
+		fakeEvent(1, 1, 2, "", blockVotes(1, 5), nil),
+		fakeEvent(2, 1, 1, "", nil, &inter.LlrEpochVote{Epoch: 1, Vote: hash.Of([]byte{1})}),
+	}
+	for _, r := range records {
+		require.NoError(src.Record(r))
+	}
+
+	exported := new(bytes.Buffer)
+	n, err := src.Export(exported)
+	require.NoError(err)
+	require.Equal(len(records), n)
+
+	dst, err := Open(filepath.Join(dir, "dst"), false)
+	require.NoError(err)
+	defer dst.Close()
+	require.NoError(dst.Record(records[0]))
+
+	n, err = dst.Import(bytes.NewReader(exported.Bytes()))
+	require.NoError(err)
+	require.Equal(len(records)-1, n)
+	require.Equal(records[1].ID(), *dst.LastEvent(1))
+	require.Equal(idx.Block(5), *dst.LastBlockVote(1))
+	require.Equal(idx.Epoch(1), *dst.LastEpochVote(2))
+	require.ErrorIs(dst.Approve(fakeEvent(1, 1, 2, "fork", nil, nil)), ErrConflictingEvent)
+
+	// importing again adds nothing
+	n, err = dst.Import(bytes.NewReader(exported.Bytes()))
+	require.NoError(err)
+	require.Equal(0, n)
+
+	_, err = dst.Import(bytes.NewReader([]byte(`{"version":2,"records":[]}`)))
+	require.Error(err)
+}
@@ -0,0 +1,95 @@
+package protection
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+)
+
+// InterchangeVersion is the version of the export format
+const InterchangeVersion = 1
+
+// interchange is the export format of the database
+type interchange struct {
+	Version uint     `json:"version"`
+	Records []Record `json:"records"`
+}
+
+// Export writes all the records of the database as a JSON document
+func (db *DB) Export(w io.Writer) (int, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	file, err := os.Open(db.path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	data := interchange{
+		Version: InterchangeVersion,
+		Records: []Record{},
+	}
+	_, err = readRecords(file, func(r Record) {
+		data.Records = append(data.Records, r)
+	})
+	if err != nil {
+		return 0, err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return len(data.Records), enc.Encode(data)
+}
+
+// Import appends the exported records which aren't in the database yet.
+// The records aren't checked for conflicts, as they are already signed,
+// but the following signing is checked against them.
+func (db *DB) Import(r io.Reader) (int, error) {
+	var data interchange
+	if err := json.NewDecoder(r).Decode(&data); err != nil {
+		return 0, fmt.Errorf("failed to decode slashing protection data: %w", err)
+	}
+	if data.Version != InterchangeVersion {
+		return 0, fmt.Errorf("unsupported slashing protection data version %d, expected %d", data.Version, InterchangeVersion)
+	}
+	sort.SliceStable(data.Records, func(i, j int) bool {
+		a, b := data.Records[i], data.Records[j]
+		if a.Validator != b.Validator {
+			return a.Validator < b.Validator
+		}
+		return b.after(a)
+	})
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	file, err := os.Open(db.path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	known := make(map[hash.Hash]bool)
+	_, err = readRecords(file, func(r Record) {
+		known[r.HashToSign()] = true
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	imported := 0
+	for _, rec := range data.Records {
+		if known[rec.HashToSign()] {
+			continue
+		}
+		if err := db.write(rec); err != nil {
+			return imported, err
+		}
+		known[rec.HashToSign()] = true
+		db.state(rec.Validator).add(rec)
+		imported++
+	}
+	return imported, nil
+}
@@ -0,0 +1,108 @@
+package protection
+
+import (
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+
+	"github.com/Fantom-foundation/go-opera/inter"
+)
+
+// BlockVotes is the range of blocks voted by a signed event
+type BlockVotes struct {
+	Start idx.Block `json:"start"`
+	Last  idx.Block `json:"last"`
+	Epoch idx.Epoch `json:"epoch"`
+}
+
+// EpochVote is the epoch voted by a signed event
+type EpochVote struct {
+	Epoch idx.Epoch `json:"epoch"`
+	Vote  hash.Hash `json:"vote"`
+}
+
+// Record is a signed event, it contains the event locator and the LLR votes of the event
+type Record struct {
+	Validator   idx.ValidatorID `json:"validator"`
+	Version     uint8           `json:"version"`
+	Epoch       idx.Epoch       `json:"epoch"`
+	Seq         idx.Event       `json:"seq"`
+	Lamport     idx.Lamport     `json:"lamport"`
+	NetForkID   uint16          `json:"netForkID"`
+	BaseHash    hash.Hash       `json:"baseHash"`
+	PayloadHash hash.Hash       `json:"payloadHash"`
+	BlockVotes  *BlockVotes     `json:"blockVotes,omitempty"`
+	EpochVote   *EpochVote      `json:"epochVote,omitempty"`
+}
+
+// NewRecord makes the protection record of the event
+func NewRecord(e inter.EventPayloadI) Record {
+	l := e.Locator()
+	r := Record{
+		Validator:   l.Creator,
+		Version:     e.Version(),
+		Epoch:       l.Epoch,
+		Seq:         l.Seq,
+		Lamport:     l.Lamport,
+		NetForkID:   l.NetForkID,
+		BaseHash:    l.BaseHash,
+		PayloadHash: l.PayloadHash,
+	}
+	if bvs := e.BlockVotes(); len(bvs.Votes) != 0 {
+		r.BlockVotes = &BlockVotes{
+			Start: bvs.Start,
+			Last:  bvs.LastBlock(),
+			Epoch: bvs.Epoch,
+		}
+	}
+	if ev := e.EpochVote(); ev.Epoch != 0 {
+		r.EpochVote = &EpochVote{
+			Epoch: ev.Epoch,
+			Vote:  ev.Vote,
+		}
+	}
+	return r
+}
+
+// Locator returns the locator of the signed event
+func (r Record) Locator() inter.EventLocator {
+	return inter.EventLocator{
+		BaseHash:    r.BaseHash,
+		NetForkID:   r.NetForkID,
+		Epoch:       r.Epoch,
+		Seq:         r.Seq,
+		Lamport:     r.Lamport,
+		Creator:     r.Validator,
+		PayloadHash: r.PayloadHash,
+	}
+}
+
+// HashToSign returns the signed digest
+func (r Record) HashToSign() hash.Hash {
+	if r.Version < 1 {
+		return r.BaseHash
+	}
+	return r.Locator().HashToSign()
+}
+
+// ID returns the ID of the signed event
+func (r Record) ID() hash.Event {
+	var id hash.Event
+	copy(id[0:4], r.Epoch.Bytes())
+	copy(id[4:8], r.Lamport.Bytes())
+	h := r.HashToSign()
+	copy(id[8:], h[:24])
+	return id
+}
+
+// same returns true if it's the same event
+func (r Record) same(other Record) bool {
+	return r.Version == other.Version && r.Locator() == other.Locator()
+}
+
+// after returns true if the event is signed after the other event of the same validator
+func (r Record) after(other Record) bool {
+	if r.Epoch != other.Epoch {
+		return r.Epoch > other.Epoch
+	}
+	return r.Seq > other.Seq
+}
@@ -0,0 +1,157 @@
+package valkeystore
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/Fantom-foundation/go-opera/inter/validatorpk"
+	"github.com/Fantom-foundation/go-opera/valkeystore/encryption"
+)
+
+// DefaultRemoteSignerTimeout is the default timeout of a signing request
+const DefaultRemoteSignerTimeout = 2 * time.Second
+
+// maxRemoteSignerResponseSize limits the size of the remote signer response body
+const maxRemoteSignerResponseSize = 64 * 1024
+
+var ErrInvalidRemoteSignature = errors.New("invalid signature of the remote signer")
+
+// RemoteSignerConfig is the configuration of the remote signer client
+type RemoteSignerConfig struct {
+	// URL of the signer, digests are posted to <URL>/sign/<pubkey>
+	URL string
+	// Client certificate and key files for the mutual TLS authentication
+	CertFile string
+	KeyFile  string
+	// CA certificate file to verify the signer certificate
+	CAFile string
+	// Timeout of a signing request
+	Timeout time.Duration
+}
+
+// remoteSignRequest is the body of a signing request
+type remoteSignRequest struct {
+	Digest hexutil.Bytes `json:"digest"`
+}
+
+// remoteSignResponse is the body of a signing response
+type remoteSignResponse struct {
+	Signature hexutil.Bytes `json:"signature"`
+}
+
+// RemoteSigner forwards signing requests to a separate signing service,
+// so validator keys are never loaded into the node memory.
+type RemoteSigner struct {
+	url     string
+	timeout time.Duration
+	client  *http.Client
+}
+
+// NewRemoteSigner creates a signer client authenticated to the signer by the client certificate.
+// The mutual TLS is mandatory, so the client certificate, its key and the signer CA are required.
+func NewRemoteSigner(cfg RemoteSignerConfig) (*RemoteSigner, error) {
+	if !strings.HasPrefix(cfg.URL, "https://") {
+		return nil, fmt.Errorf("remote signer URL must be https, got %q", cfg.URL)
+	}
+	if cfg.CertFile == "" || cfg.KeyFile == "" || cfg.CAFile == "" {
+		return nil, errors.New("remote signer requires the client certificate, the client key and the CA certificate")
+	}
+	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load remote signer client certificate: %w", err)
+	}
+	pem, err := os.ReadFile(cfg.CAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read remote signer CA certificate: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, errors.New("failed to parse remote signer CA certificate")
+	}
+	tlsCfg := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      pool,
+	}
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = DefaultRemoteSignerTimeout
+	}
+	return &RemoteSigner{
+		url:     strings.TrimSuffix(cfg.URL, "/"),
+		timeout: timeout,
+		client: &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig:   tlsCfg,
+				ForceAttemptHTTP2: true,
+			},
+		},
+	}, nil
+}
+
+func (s *RemoteSigner) Sign(pubkey validatorpk.PubKey, digest []byte) ([]byte, error) {
+	if pubkey.Type != validatorpk.Types.Secp256k1 && pubkey.Type != validatorpk.Types.Ed25519 {
+		return nil, encryption.ErrNotSupportedType
+	}
+
+	body, err := json.Marshal(remoteSignRequest{Digest: digest})
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url+"/sign/"+pubkey.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("remote signer request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxRemoteSignerResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read remote signer response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("remote signer responded with %s: %s", resp.Status, strings.TrimSpace(string(respBody)))
+	}
+	var res remoteSignResponse
+	if err := json.Unmarshal(respBody, &res); err != nil {
+		return nil, fmt.Errorf("failed to decode remote signer response: %w", err)
+	}
+
+	// signer may return the secp256k1 signature with the recovery ID, which is not used
+	sig := res.Signature
+	if len(sig) == 65 && pubkey.Type == validatorpk.Types.Secp256k1 {
+		sig = sig[:64]
+	}
+	// do not trust the signer, a wrong signature would make the emitted events invalid
+	if len(sig) != 64 || !verifySignature(pubkey, digest, sig) {
+		return nil, ErrInvalidRemoteSignature
+	}
+	return sig, nil
+}
+
+func verifySignature(pubkey validatorpk.PubKey, digest, sig []byte) bool {
+	if pubkey.Type == validatorpk.Types.Ed25519 {
+		return len(pubkey.Raw) == ed25519.PublicKeySize && ed25519.Verify(pubkey.Raw, digest, sig)
+	}
+	return crypto.VerifySignature(pubkey.Raw, digest, sig)
+}
@@ -0,0 +1,242 @@
+package valkeystore
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/inter/validatorpk"
+	"github.com/Fantom-foundation/go-opera/valkeystore/encryption"
+)
+
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	der  []byte
+}
+
+func newTestCert(t *testing.T, template *x509.Certificate, parent *testCert) *testCert {
+	// x509 doesn't support secp256k1 keys, so P256 is used for the certificates
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(time.Hour)
+	signerCert, signerKey := template, key
+	if parent != nil {
+		signerCert, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signerCert, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCert{cert, key, der}
+}
+
+func (c *testCert) writeFiles(t *testing.T, dir, name string) (certFile, keyFile string) {
+	certFile = filepath.Join(dir, name+".crt")
+	keyFile = filepath.Join(dir, name+".key")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.der}), 0600))
+	keyDer, err := x509.MarshalECPrivateKey(c.key)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600))
+	return
+}
+
+// startTestSigner starts a stand-in remote signer which requires a client certificate signed by the CA
+func startTestSigner(t *testing.T, ca, server *testCert, handler http.HandlerFunc) *httptest.Server {
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.cert)
+	srv := httptest.NewUnstartedServer(handler)
+	srv.TLS = &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{server.der}, PrivateKey: server.key}},
+		ClientCAs:    clientCAs,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	}
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestRemoteSigner(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+
+	ca := newTestCert(t, &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, nil)
+	server := newTestCert(t, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "signer"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, ca)
+	client := newTestCert(t, &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: "validator"},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, ca)
+	caFile, _ := ca.writeFiles(t, dir, "ca")
+	certFile, keyFile := client.writeFiles(t, dir, "client")
+
+	validatorKey, err := crypto.GenerateKey()
+	require.NoError(err)
+	pubkey := validatorpk.PubKey{
+		Type: validatorpk.Types.Secp256k1,
+		Raw:  crypto.FromECDSAPub(&validatorKey.PublicKey),
+	}
+	otherKey, err := crypto.GenerateKey()
+	require.NoError(err)
+
+	var (
+		mu         sync.Mutex
+		signingKey = validatorKey
+		delay      time.Duration
+	)
+	setSigner := func(key *ecdsa.PrivateKey, d time.Duration) {
+		mu.Lock()
+		defer mu.Unlock()
+		signingKey, delay = key, d
+	}
+	srv := startTestSigner(t, ca, server, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		key, d := signingKey, delay
+		mu.Unlock()
+		time.Sleep(d)
+		if r.URL.Path != "/sign/"+pubkey.String() {
+			http.Error(w, "unknown key", http.StatusNotFound)
+			return
+		}
+		var req remoteSignRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		sig, err := crypto.Sign(req.Digest, key)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(remoteSignResponse{Signature: sig})
+	})
+
+	signer, err := NewRemoteSigner(RemoteSignerConfig{
+		URL:      srv.URL,
+		CertFile: certFile,
+		KeyFile:  keyFile,
+		CAFile:   caFile,
+		Timeout:  500 * time.Millisecond,
+	})
+	require.NoError(err)
+	digest := crypto.Keccak256([]byte("event"))
+
+	t.Run("sign", func(t *testing.T) {
+		sig, err := signer.Sign(pubkey, digest)
+		require.NoError(err)
+		require.Len(sig, 64)
+		require.True(crypto.VerifySignature(pubkey.Raw, digest, sig))
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		unknown := validatorpk.PubKey{
+			Type: validatorpk.Types.Secp256k1,
+			Raw:  crypto.FromECDSAPub(&otherKey.PublicKey),
+		}
+		_, err := signer.Sign(unknown, digest)
+		require.Error(err)
+		require.Contains(err.Error(), "404")
+	})
+
+	t.Run("unsupported type", func(t *testing.T) {
+		_, err := signer.Sign(validatorpk.PubKey{Type: 0x01, Raw: pubkey.Raw}, digest)
+		require.Equal(encryption.ErrNotSupportedType, err)
+	})
+
+	t.Run("wrong signature", func(t *testing.T) {
+		setSigner(otherKey, 0)
+		defer setSigner(validatorKey, 0)
+		_, err := signer.Sign(pubkey, digest)
+		require.Equal(ErrInvalidRemoteSignature, err)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		setSigner(validatorKey, time.Second)
+		defer setSigner(validatorKey, 0)
+		_, err := signer.Sign(pubkey, digest)
+		require.Error(err)
+		require.True(strings.Contains(err.Error(), "deadline exceeded"), err.Error())
+	})
+
+	t.Run("incomplete TLS config", func(t *testing.T) {
+		for _, cfg := range []RemoteSignerConfig{
+			{URL: srv.URL, KeyFile: keyFile, CAFile: caFile},
+			{URL: srv.URL, CertFile: certFile, CAFile: caFile},
+			{URL: srv.URL, CertFile: certFile, KeyFile: keyFile},
+		} {
+			_, err := NewRemoteSigner(cfg)
+			require.Error(err)
+		}
+	})
+
+	t.Run("no client certificate", func(t *testing.T) {
+		// the signer has to reject clients without the certificate
+		pool := x509.NewCertPool()
+		pool.AddCert(ca.cert)
+		anonymous := &RemoteSigner{
+			url:     srv.URL,
+			timeout: time.Second,
+			client: &http.Client{Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{RootCAs: pool},
+			}},
+		}
+		_, err := anonymous.Sign(pubkey, digest)
+		require.Error(err)
+	})
+
+	t.Run("untrusted signer", func(t *testing.T) {
+		otherCA := newTestCert(t, &x509.Certificate{
+			SerialNumber:          big.NewInt(4),
+			Subject:               pkix.Name{CommonName: "other CA"},
+			IsCA:                  true,
+			BasicConstraintsValid: true,
+			KeyUsage:              x509.KeyUsageCertSign,
+		}, nil)
+		otherCAFile, _ := otherCA.writeFiles(t, dir, "other-ca")
+		untrusting, err := NewRemoteSigner(RemoteSignerConfig{
+			URL:      srv.URL,
+			CertFile: certFile,
+			KeyFile:  keyFile,
+			CAFile:   otherCAFile,
+		})
+		require.NoError(err)
+		_, err = untrusting.Sign(pubkey, digest)
+		require.Error(err)
+		require.Contains(err.Error(), "certificate")
+	})
+
+	t.Run("not https", func(t *testing.T) {
+		_, err := NewRemoteSigner(RemoteSignerConfig{URL: "http://127.0.0.1:1"})
+		require.Error(err)
+	})
+}
@@ -2,11 +2,14 @@ package valkeystore
 
 import (
 	"crypto/ecdsa"
+	"crypto/ed25519"
 
+	"github.com/Fantom-foundation/lachesis-base/hash"
 	"github.com/ethereum/go-ethereum/crypto"
 
 	"github.com/Fantom-foundation/go-opera/inter/validatorpk"
 	"github.com/Fantom-foundation/go-opera/valkeystore/encryption"
+	"github.com/Fantom-foundation/go-opera/valkeystore/protection"
 )
 
 type SignerI interface {
@@ -24,7 +27,7 @@ func NewSigner(backend KeystoreI) *Signer {
 }
 
 func (s *Signer) Sign(pubkey validatorpk.PubKey, digest []byte) ([]byte, error) {
-	if pubkey.Type != validatorpk.Types.Secp256k1 {
+	if pubkey.Type != validatorpk.Types.Secp256k1 && pubkey.Type != validatorpk.Types.Ed25519 {
 		return nil, encryption.ErrNotSupportedType
 	}
 	key, err := s.backend.GetUnlocked(pubkey)
@@ -32,12 +35,36 @@ func (s *Signer) Sign(pubkey validatorpk.PubKey, digest []byte) ([]byte, error)
 		return nil, err
 	}
 
-	secp256k1Key := key.Decoded.(*ecdsa.PrivateKey)
+	switch decoded := key.Decoded.(type) {
+	case *ecdsa.PrivateKey:
+		sigRSV, err := crypto.Sign(digest, decoded)
+		if err != nil {
+			return nil, err
+		}
+		sigRS := sigRSV[:64]
+		return sigRS, err
+	case ed25519.PrivateKey:
+		return ed25519.Sign(decoded, digest), nil
+	}
+	return nil, encryption.ErrNotSupportedType
+}
 
-	sigRSV, err := crypto.Sign(digest, secp256k1Key)
-	if err != nil {
-		return nil, err
+// ProtectedSigner signs only the events approved by the slashing protection DB
+type ProtectedSigner struct {
+	signer     SignerI
+	protection *protection.DB
+}
+
+func NewProtectedSigner(signer SignerI, db *protection.DB) *ProtectedSigner {
+	return &ProtectedSigner{
+		signer:     signer,
+		protection: db,
+	}
+}
+
+func (s *ProtectedSigner) Sign(pubkey validatorpk.PubKey, digest []byte) ([]byte, error) {
+	if !s.protection.IsApproved(hash.BytesToHash(digest)) {
+		return nil, protection.ErrNotApproved
 	}
-	sigRS := sigRSV[:64]
-	return sigRS, err
+	return s.signer.Sign(pubkey, digest)
 }
@@ -0,0 +1,94 @@
+package valkeystore
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/inter"
+	"github.com/Fantom-foundation/go-opera/inter/validatorpk"
+	"github.com/Fantom-foundation/go-opera/valkeystore/encryption"
+	"github.com/Fantom-foundation/go-opera/valkeystore/protection"
+)
+
+func TestSignerEd25519(t *testing.T) {
+	require := require.New(t)
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(err)
+	pubkey := validatorpk.PubKey{
+		Type: validatorpk.Types.Ed25519,
+		Raw:  pub,
+	}
+	digest := crypto.Keccak256([]byte("event"))
+
+	for name, raw := range map[string]RawKeystoreI{
+		"mem":  NewMemKeystore(),
+		"file": NewFileKeystore(t.TempDir(), encryption.New(keystore.LightScryptN, keystore.LightScryptP)),
+	} {
+		t.Run(name, func(t *testing.T) {
+			ks := NewSyncedKeystore(NewCachedKeystore(raw))
+			require.NoError(ks.Add(pubkey, priv.Seed(), "auth"))
+			require.NoError(ks.Unlock(pubkey, "auth"))
+
+			sig, err := NewSigner(ks).Sign(pubkey, digest)
+			require.NoError(err)
+			require.True(ed25519.Verify(pub, digest, sig))
+
+			// a key of other type isn't accepted as the ed25519 key
+			wrongType := pubkey
+			wrongType.Type = validatorpk.Types.Secp256k1
+			require.Error(ks.Unlock(wrongType, "auth"))
+		})
+	}
+
+	_, err = encryption.DecodeKey(validatorpk.Types.Ed25519, priv)
+	require.Equal(encryption.ErrInvalidKeyLength, err)
+}
+
+func TestProtectedSigner(t *testing.T) {
+	require := require.New(t)
+
+	keystore := NewDefaultMemKeystore()
+	require.NoError(keystore.Add(pubkey1, key1, "auth1"))
+	require.NoError(keystore.Unlock(pubkey1, "auth1"))
+
+	db, err := protection.Open(filepath.Join(t.TempDir(), "slashing-protection"), false)
+	require.NoError(err)
+	defer db.Close()
+	signer := NewProtectedSigner(NewSigner(keystore), db)
+
+	newEvent := func(extra string) protection.Record {
+		e := &inter.MutableEventPayload{}
+		e.SetVersion(1)
+		e.SetCreator(1)
+		e.SetEpoch(1)
+		e.SetSeq(1)
+		e.SetLamport(1)
+		e.SetExtra([]byte(extra))
+		e.SetPayloadHash(inter.CalcPayloadHash(e))
+		return protection.NewRecord(e)
+	}
+	e := newEvent("")
+	digest := e.HashToSign().Bytes()
+
+	_, err = signer.Sign(pubkey1, digest)
+	require.ErrorIs(err, protection.ErrNotApproved)
+
+	require.NoError(db.Approve(e))
+	sig, err := signer.Sign(pubkey1, digest)
+	require.NoError(err)
+	require.True(crypto.VerifySignature(pubkey1.Raw, digest, sig))
+	require.NoError(db.Record(e))
+
+	// a fork of the signed event is refused
+	fork := newEvent("fork")
+	require.ErrorIs(db.Approve(fork), protection.ErrConflictingEvent)
+	_, err = signer.Sign(pubkey1, fork.HashToSign().Bytes())
+	require.ErrorIs(err, protection.ErrNotApproved)
+}